@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/disgoorg/omit"
+
+	"github.com/fluxergo/fluxergo"
+	"github.com/fluxergo/fluxergo/bot"
+	"github.com/fluxergo/fluxergo/commands"
+	"github.com/fluxergo/fluxergo/fluxer"
+)
+
+var token = os.Getenv("fluxergo_token")
+
+func main() {
+	slog.Info("starting example...")
+	slog.Info("FluxerGo version", slog.Any("version", fluxergo.Version))
+
+	router := commands.New(commands.WithPrefixes("!", "?"))
+	router.AddCommands(
+		&commands.Command{
+			Name:        "ping",
+			Description: "Replies with pong",
+			Cooldown:    &commands.Cooldown{Scope: commands.CooldownScopeUser, Duration: 5 * time.Second},
+			Handler: func(e *commands.Event) error {
+				_, err := e.Reply("pong")
+				return err
+			},
+		},
+		&commands.Command{
+			Name:        "timeout",
+			Description: "Times out a member",
+			Permissions: fluxer.PermissionModerateMembers,
+			Args: []commands.Arg{
+				{Name: "member", Type: commands.ArgTypeMember},
+				{Name: "duration", Type: commands.ArgTypeDuration},
+				{Name: "reason", Optional: true, Rest: true},
+			},
+			Handler: func(e *commands.Event) error {
+				member := e.Member("member")
+				until := time.Now().Add(e.Duration("duration"))
+				if _, err := e.Client().Rest.UpdateMember(member.GuildID, member.User.ID, fluxer.MemberUpdate{
+					CommunicationDisabledUntil: omit.NewPtr(until),
+				}); err != nil {
+					return err
+				}
+				_, err := e.Reply("Timed out %s until %s", member.User.Mention(), fluxer.FormattedTimestampMention(until.Unix(), fluxer.TimestampStyleShortDateTime))
+				return err
+			},
+		},
+	)
+
+	client, err := fluxergo.New(token,
+		bot.WithDefaultGateway(),
+		bot.WithEventListeners(router),
+		bot.WithEventManagerConfigOpts(bot.WithAsyncEventsEnabled()),
+	)
+	if err != nil {
+		slog.Error("error while building bot instance", slog.Any("err", err))
+		return
+	}
+	defer client.Close(context.TODO())
+
+	if err = client.OpenGateway(context.TODO()); err != nil {
+		slog.Error("error while connecting to gateway", slog.Any("err", err))
+		return
+	}
+
+	slog.Info("ExampleBot is now running. Press CTRL-C to exit.")
+	s := make(chan os.Signal, 1)
+	signal.Notify(s, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+	<-s
+}
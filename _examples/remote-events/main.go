@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/fluxergo/fluxergo"
+	"github.com/fluxergo/fluxergo/bot"
+	"github.com/fluxergo/fluxergo/events"
+)
+
+var (
+	token   = os.Getenv("fluxergo_token")
+	address = flag.String("address", "/tmp/fluxergo-events.sock", "unix socket to forward events over")
+	worker  = flag.Bool("worker", false, "run the worker process handling the events instead of the gateway process")
+)
+
+func main() {
+	flag.Parse()
+
+	var client *bot.Client
+	var err error
+	if *worker {
+		client, err = runWorker()
+	} else {
+		client, err = runGateway()
+	}
+	if err != nil {
+		slog.Error("error while starting example", slog.Any("err", err))
+		return
+	}
+	defer client.Close(context.TODO())
+
+	slog.Info("ExampleBot is now running. Press CTRL-C to exit.")
+	s := make(chan os.Signal, 1)
+	signal.Notify(s, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+	<-s
+}
+
+// runGateway connects to the gateway and forwards all events to the worker.
+func runGateway() (*bot.Client, error) {
+	client, err := fluxergo.New(token,
+		bot.WithDefaultGateway(),
+		bot.WithEventSink(bot.NewNetEventSink("unix", *address)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return client, client.OpenGateway(context.TODO())
+}
+
+// runWorker receives the events of the gateway process and handles them.
+func runWorker() (*bot.Client, error) {
+	_ = os.Remove(*address)
+	listener, err := net.Listen("unix", *address)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := fluxergo.New(token,
+		bot.WithEventSource(bot.NewNetEventSource(listener)),
+		bot.WithEventListenerFunc(func(e *events.MessageCreate) {
+			slog.Info("received message", slog.String("content", e.Message.Content), slog.Int("shard_id", e.ShardID()))
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return client, client.OpenEventSource()
+}
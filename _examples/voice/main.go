@@ -1,16 +1,11 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	_ "embed"
-	"encoding/binary"
-	"errors"
-	"io"
 	"log/slog"
 	"os"
 	"os/signal"
-	"strconv"
 	"syscall"
 	"time"
 
@@ -19,7 +14,6 @@ import (
 	"github.com/fluxergo/fluxergo/bot"
 	"github.com/fluxergo/fluxergo/events"
 	"github.com/fluxergo/fluxergo/voice"
-	"github.com/fluxergo/livekit"
 )
 
 var (
@@ -41,9 +35,9 @@ func main() {
 		bot.WithEventListenerFunc(func(e *events.Ready) {
 			go play(e.Client())
 		}),
-		bot.WithVoiceManagerConfigOpts(
-			voice.WithLiveKitConnCreateFunc(livekit.NewConn),
-		),
+		bot.WithEventListenerFunc(func(e *events.PlayerTrackStart) {
+			slog.Info("started playing track", slog.String("track", e.Track.Name))
+		}),
 	)
 	if err != nil {
 		slog.Error("error creating client", slog.Any("err", err))
@@ -68,7 +62,7 @@ func main() {
 }
 
 func play(client *bot.Client) {
-	conn := client.VoiceManager.CreateConn(guildID)
+	conn := client.VoiceManager.CreateConn(voice.GuildTarget(guildID))
 	slog.Info("connecting to voice manager")
 
 	if err := conn.Open(context.Background(), channelID); err != nil {
@@ -76,44 +70,12 @@ func play(client *bot.Client) {
 	}
 	slog.Info("connected to voice channel")
 
-	w, err := conn.LiveKit().AudioWriter("audio", voice.AudioSourceMicrophone)
+	player, err := voice.NewPlayer(conn, voice.WithPlayerEventFunc(events.NewPlayerEventFunc(client)))
 	if err != nil {
-		panic("error creating audio writer: " + err.Error())
+		panic("error creating player: " + err.Error())
 	}
-	defer w.Close()
-	slog.Info("created audio writer")
-
-	writeAudio(w)
-}
-
-func writeAudio(w io.Writer) {
-	ticker := time.NewTicker(time.Millisecond * 20)
-	defer ticker.Stop()
+	slog.Info("created player")
 
-	var frameLen int16
-	for {
-		r := bytes.NewReader(testAudio)
-		for range ticker.C {
-			if err := binary.Read(r, binary.LittleEndian, &frameLen); err != nil {
-				if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
-					break
-				}
-				panic("error reading file: " + err.Error())
-			}
-			if frameLen <= 0 {
-				panic("invalid frame length: " + strconv.Itoa(int(frameLen)))
-			}
-
-			buf := make([]byte, frameLen)
-			_, err := r.Read(buf)
-			if err != nil {
-				break
-			}
-
-			_, err = w.Write(buf)
-			if err != nil {
-				slog.Error("error writing sample", slog.Any("err", err))
-			}
-		}
-	}
+	player.SetLoop(voice.LoopModeTrack)
+	player.Enqueue(voice.NewBytesTrack("nico", testAudio, voice.TrackFormatDCA))
 }
@@ -5,7 +5,6 @@ import (
 	"context"
 	_ "embed"
 	"io"
-	"log"
 	"log/slog"
 	"os"
 	"os/exec"
@@ -15,8 +14,6 @@ import (
 	"time"
 
 	"github.com/disgoorg/snowflake/v2"
-	"github.com/fluxergo/livekit"
-	"github.com/jonas747/ogg"
 
 	"github.com/fluxergo/fluxergo"
 	"github.com/fluxergo/fluxergo/bot"
@@ -35,9 +32,6 @@ func main() {
 	client, err := fluxergo.New(token,
 		bot.WithDefaultGateway(),
 		bot.WithEventListenerFunc(onMessage),
-		bot.WithVoiceManagerConfigOpts(
-			voice.WithLiveKitConnCreateFunc(livekit.NewConn),
-		),
 	)
 	if err != nil {
 		slog.Error("error creating client", slog.Any("err", err))
@@ -90,7 +84,7 @@ func onCommand(e *events.MessageCreate) {
 			})
 			return
 		}
-		if err := e.Client().VoiceManager.CreateConn(*e.GuildID).Open(context.Background(), channelID); err != nil {
+		if err := e.Client().VoiceManager.CreateConn(voice.GuildTarget(*e.GuildID)).Open(context.Background(), channelID); err != nil {
 			e.Client().Rest.CreateMessage(e.Message.ChannelID, fluxer.MessageCreate{
 				Content: "Error connecting to voice channel: " + err.Error(),
 			})
@@ -106,7 +100,7 @@ func onCommand(e *events.MessageCreate) {
 		url := args[0]
 		play(e.Client(), *e.GuildID, e.ChannelID, url)
 	case "stop":
-		conn := e.Client().VoiceManager.GetConn(*e.GuildID)
+		conn := e.Client().VoiceManager.GetConn(voice.GuildTarget(*e.GuildID))
 		if conn != nil {
 			conn.Close(context.Background())
 		}
@@ -114,7 +108,7 @@ func onCommand(e *events.MessageCreate) {
 }
 
 func play(client *bot.Client, guildID snowflake.ID, channelID snowflake.ID, url string) {
-	conn := client.VoiceManager.GetConn(guildID)
+	conn := client.VoiceManager.GetConn(voice.GuildTarget(guildID))
 	if conn == nil {
 		client.Rest.CreateMessage(channelID, fluxer.MessageCreate{
 			Content: "Not connected to a voice channel. Use !join first.",
@@ -148,10 +142,18 @@ func play(client *bot.Client, guildID snowflake.ID, channelID snowflake.ID, url
 	}
 	defer audio.Close()
 
-	go writeVideo(video, videoReader)
-	go writeAudio(audio, audioReader)
+	h264Reader, err := voice.NewH264Reader(videoReader, 30)
+	if err != nil {
+		client.Rest.CreateMessage(channelID, fluxer.MessageCreate{
+			Content: "Error creating video reader: " + err.Error(),
+		})
+		return
+	}
 
-	select {}
+	// the audio and video are written on a common clock, so they stay in sync
+	if err = voice.WriteAudioVideo(context.Background(), audio, voice.NewOggOpusReader(bufio.NewReader(audioReader)), video, h264Reader); err != nil {
+		slog.Error("error while streaming", slog.Any("err", err))
+	}
 }
 
 func startStream(url string) (video io.Reader, audio io.Reader, err error) {
@@ -188,6 +190,7 @@ func startStream(url string) (video io.Reader, audio io.Reader, err error) {
 
 		// Video output
 		"-map", "0:v:0",
+		"-r", "30",
 		"-c:v", "libx264",
 		"-profile:v", "baseline",
 		"-pix_fmt", "yuv420p",
@@ -230,70 +233,3 @@ func startStream(url string) (video io.Reader, audio io.Reader, err error) {
 
 	return videoPipe, audioPipeReader, nil
 }
-
-func writeVideo(w io.Writer, r io.Reader) {
-	reader := bufio.NewReader(r)
-
-	var nalBuf []byte
-	startCode := []byte{0x00, 0x00, 0x00, 0x01}
-
-	for {
-		chunk := make([]byte, 4096)
-		n, err := reader.Read(chunk)
-		if err != nil {
-			if err == io.EOF {
-				return
-			}
-			log.Println("read error:", err)
-			continue
-		}
-
-		nalBuf = append(nalBuf, chunk[:n]...)
-
-		for {
-			idx := indexOf(nalBuf[4:], startCode)
-			if idx == -1 {
-				break
-			}
-
-			// safe copy of one NAL frame
-			frame := make([]byte, idx+4)
-			copy(frame, nalBuf[:idx+4])
-			nalBuf = nalBuf[idx+4:]
-
-			if _, err := w.Write(frame); err != nil {
-				log.Println("write frame error:", err)
-				return
-			}
-		}
-	}
-}
-
-func writeAudio(w io.Writer, r io.Reader) {
-	decoder := ogg.NewPacketDecoder(ogg.NewDecoder(bufio.NewReader(r)))
-
-	for {
-		data, _, err := decoder.Decode()
-		if err != nil {
-			if err == io.EOF {
-				return
-			}
-			log.Println("decode error:", err)
-			continue
-		}
-
-		if _, err = w.Write(data); err != nil {
-			log.Println("write error:", err)
-			return
-		}
-	}
-}
-
-func indexOf(data, sep []byte) int {
-	for i := 0; i <= len(data)-len(sep); i++ {
-		if string(data[i:i+len(sep)]) == string(sep) {
-			return i
-		}
-	}
-	return -1
-}
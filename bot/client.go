@@ -3,6 +3,7 @@ package bot
 import (
 	"context"
 	"log/slog"
+	"sync"
 
 	"github.com/disgoorg/snowflake/v2"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/fluxergo/fluxergo/fluxer"
 	"github.com/fluxergo/fluxergo/gateway"
 	"github.com/fluxergo/fluxergo/rest"
+	"github.com/fluxergo/fluxergo/scheduler"
 	"github.com/fluxergo/fluxergo/voice"
 )
 
@@ -26,15 +28,86 @@ type Client struct {
 	VoiceManager          voice.Manager
 	Caches                cache.Caches
 	MemberChunkingManager MemberChunkingManager
+	Scheduler             scheduler.Scheduler
+
+	ctx         context.Context
+	cancel      context.CancelFunc
+	eventOutbox EventOutbox
+	replayOnce  sync.Once
+
+	eventSink          EventSink
+	eventSinkForwarder *eventSinkForwarder
+	eventSource        EventSource
+	eventSourceOnce    sync.Once
+	eventSourceDoneMu  sync.Mutex
+	eventSourceDone    chan struct{}
 }
 
+// Context returns the context of the Client which is cancelled by Close, see Close for when.
+// Events carry this context and listeners should pass it to long-running calls like rest.WithCtx.
+func (c *Client) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// Close closes the VoiceManager and Gateway, waits until all running EventListener(s) returned or the context is done,
+// closes the Scheduler, cancels the Client's context and closes the Rest client.
+// If the context has a deadline, running EventListener(s) can finish until it and the Client's context is cancelled once it passed.
+// Otherwise, the Client's context is cancelled before waiting, so EventListener(s) using Event.Context return.
 func (c *Client) Close(ctx context.Context) {
+	if c.cancel != nil {
+		stop := context.AfterFunc(ctx, c.cancel)
+		defer stop()
+	}
 	if c.VoiceManager != nil {
 		c.VoiceManager.Close(ctx)
 	}
 	if c.Gateway != nil {
 		c.Gateway.Close(ctx)
 	}
+	if c.eventSinkForwarder != nil {
+		c.eventSinkForwarder.Close(ctx)
+	}
+	if c.eventSink != nil {
+		if err := c.eventSink.Close(ctx); err != nil {
+			c.Logger.Error("failed to close event sink", slog.Any("err", err))
+		}
+	}
+	if c.eventSource != nil {
+		if err := c.eventSource.Close(ctx); err != nil {
+			c.Logger.Error("failed to close event source", slog.Any("err", err))
+		}
+		c.eventSourceDoneMu.Lock()
+		done := c.eventSourceDone
+		c.eventSourceDoneMu.Unlock()
+		if done != nil {
+			select {
+			case <-done:
+			case <-ctx.Done():
+			}
+		}
+	}
+	if c.EventManager != nil {
+		if _, ok := ctx.Deadline(); !ok && c.cancel != nil {
+			c.cancel()
+		}
+		c.EventManager.Close(ctx)
+	}
+	if c.Scheduler != nil {
+		if err := c.Scheduler.Close(ctx); err != nil {
+			c.Logger.Error("failed to close scheduler", slog.Any("err", err))
+		}
+	}
+	if c.cancel != nil {
+		c.cancel()
+	}
+	if c.eventOutbox != nil {
+		if err := c.eventOutbox.Close(); err != nil {
+			c.Logger.Error("failed to close event outbox", slog.Any("err", err))
+		}
+	}
 	if c.Rest != nil {
 		c.Rest.Close(ctx)
 	}
@@ -59,9 +132,30 @@ func (c *Client) OpenGateway(ctx context.Context) error {
 	if c.Gateway == nil {
 		return fluxer.ErrNoGateway
 	}
+	if c.eventOutbox != nil {
+		c.replayOnce.Do(func() {
+			replayEventOutbox(c, c.eventOutbox)
+		})
+	}
 	return c.Gateway.Open(ctx)
 }
 
+// OpenEventSource starts passing the events received by the EventSource configured with WithEventSource to the EventManager.
+// Each event is acknowledged after EventManager.HandleShardEvent returned.
+func (c *Client) OpenEventSource() error {
+	if c.eventSource == nil {
+		return fluxer.ErrNoEventSource
+	}
+	c.eventSourceOnce.Do(func() {
+		done := make(chan struct{})
+		c.eventSourceDoneMu.Lock()
+		c.eventSourceDone = done
+		c.eventSourceDoneMu.Unlock()
+		go receiveRemoteEvents(c, c.eventSource, done)
+	})
+	return nil
+}
+
 func (c *Client) HasGateway() bool {
 	return c.Gateway != nil
 }
@@ -73,6 +167,8 @@ func (c *Client) shard() (gateway.Gateway, error) {
 	return nil, fluxer.ErrNoGateway
 }
 
+// UpdateVoiceState updates the voice state of the bot in a guild or, if the gateway.MessageDataVoiceStateUpdate has no GuildID, in a call in a private channel.
+// Use the VoiceManager to open voice connections.
 func (c *Client) UpdateVoiceState(ctx context.Context, data gateway.MessageDataVoiceStateUpdate) error {
 	shard, err := c.shard()
 	if err != nil {
@@ -1,14 +1,17 @@
 package bot
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/fluxergo/fluxergo/cache"
 	"github.com/fluxergo/fluxergo/fluxer"
 	"github.com/fluxergo/fluxergo/gateway"
 	"github.com/fluxergo/fluxergo/internal/tokenhelper"
 	"github.com/fluxergo/fluxergo/rest"
+	"github.com/fluxergo/fluxergo/scheduler"
 	"github.com/fluxergo/fluxergo/voice"
 )
 
@@ -17,6 +20,8 @@ func defaultConfig(gatewayHandlers map[gateway.EventType]GatewayEventHandler) co
 		Logger:                 slog.Default(),
 		EventManagerConfigOpts: []EventManagerConfigOpt{WithGatewayHandlers(gatewayHandlers)},
 		MemberChunkingFilter:   MemberChunkingFilterNone,
+		EventSinkSendTimeout:   10 * time.Second,
+		EventSinkBufferSize:    1024,
 	}
 }
 
@@ -30,9 +35,16 @@ type config struct {
 
 	EventManager           EventManager
 	EventManagerConfigOpts []EventManagerConfigOpt
+	EventOutbox            EventOutbox
+	EventSink              EventSink
+	EventSinkSendTimeout   time.Duration
+	EventSinkBufferSize    int
+	EventSinkDropFunc      EventSinkDropFunc
+	EventSource            EventSource
 
 	VoiceManager           voice.Manager
 	VoiceManagerConfigOpts []voice.ManagerConfigOpt
+	VoiceConnEventHandler  VoiceConnEventHandler
 
 	Gateway           gateway.Gateway
 	GatewayConfigOpts []gateway.ConfigOpt
@@ -42,6 +54,10 @@ type config struct {
 
 	MemberChunkingManager MemberChunkingManager
 	MemberChunkingFilter  MemberChunkingFilter
+
+	Scheduler           scheduler.Scheduler
+	SchedulerEnabled    bool
+	SchedulerConfigOpts []scheduler.ConfigOpt
 }
 
 // ConfigOpt is a type alias for a function that takes a config and is used to configure your Client.
@@ -110,6 +126,13 @@ func WithEventListeners(eventListeners ...EventListener) ConfigOpt {
 	}
 }
 
+// WithEventMiddlewares adds the given EventMiddleware(s) to the default EventManager.
+func WithEventMiddlewares(middlewares ...EventMiddleware) ConfigOpt {
+	return func(config *config) {
+		config.EventManagerConfigOpts = append(config.EventManagerConfigOpts, WithMiddlewares(middlewares...))
+	}
+}
+
 // WithEventListenerFunc adds the given func(e E) to the default EventManager.
 func WithEventListenerFunc[E Event](f func(e E)) ConfigOpt {
 	return WithEventListeners(NewListenerFunc(f))
@@ -120,6 +143,60 @@ func WithEventListenerChan[E Event](c chan<- E) ConfigOpt {
 	return WithEventListeners(NewListenerChan(c))
 }
 
+// WithEventOutbox persists every dispatch of the default Gateway in the EventOutbox until it is handled by the EventManager.
+// Pending dispatches of a previous run are replayed by Client.OpenGateway. This enables raw events on the Gateway.
+// Dispatches are acknowledged once EventManager.HandleGatewayEvent returned, so use it without async events or an EventWorkerPool
+// to only acknowledge them after all EventListener(s) finished.
+func WithEventOutbox(outbox EventOutbox) ConfigOpt {
+	return func(config *config) {
+		config.EventOutbox = outbox
+		config.GatewayConfigOpts = append(config.GatewayConfigOpts, gateway.WithEnableRawEvents(true))
+	}
+}
+
+// WithEventSink forwards every dispatch of the default Gateway with its shard ID and sequence number to the EventSink,
+// so they can be handled by another process using WithEventSource. The dispatches are still passed to the local EventManager.
+// This enables raw events on the Gateway.
+func WithEventSink(sink EventSink) ConfigOpt {
+	return func(config *config) {
+		config.EventSink = sink
+		config.GatewayConfigOpts = append(config.GatewayConfigOpts, gateway.WithEnableRawEvents(true))
+	}
+}
+
+// WithEventSinkSendTimeout sets how long the EventSink can take to accept a dispatch before it is dropped.
+// A timeout of 0 waits forever. The default is 10s.
+func WithEventSinkSendTimeout(timeout time.Duration) ConfigOpt {
+	return func(config *config) {
+		config.EventSinkSendTimeout = timeout
+	}
+}
+
+// WithEventSinkBufferSize sets how many dispatches are queued for the EventSink, so the Gateway is not blocked by it.
+// Dispatches are dropped while the queue is full. The default is 1024.
+func WithEventSinkBufferSize(bufferSize int) ConfigOpt {
+	return func(config *config) {
+		config.EventSinkBufferSize = bufferSize
+	}
+}
+
+// WithEventSinkDropFunc sets the EventSinkDropFunc which is called for every dispatch which was dropped because the queue was full,
+// the EventSink did not accept it within the send timeout or it was closed.
+func WithEventSinkDropFunc(dropFunc EventSinkDropFunc) ConfigOpt {
+	return func(config *config) {
+		config.EventSinkDropFunc = dropFunc
+	}
+}
+
+// WithEventSource lets the Client receive the events of another process using WithEventSink, instead of connecting to the gateway itself.
+// The events are passed to the EventManager once Client.OpenEventSource is called.
+// They are acknowledged once the EventManager returned, so they are only redelivered after a crash if the events are dispatched synchronously.
+func WithEventSource(source EventSource) ConfigOpt {
+	return func(config *config) {
+		config.EventSource = source
+	}
+}
+
 // WithGateway lets you inject your own gateway.Gateway.
 func WithGateway(gateway gateway.Gateway) ConfigOpt {
 	return func(config *config) {
@@ -169,6 +246,21 @@ func WithMemberChunkingFilter(memberChunkingFilter MemberChunkingFilter) ConfigO
 	}
 }
 
+// WithScheduler lets you inject your own scheduler.Scheduler.
+func WithScheduler(scheduler scheduler.Scheduler) ConfigOpt {
+	return func(config *config) {
+		config.Scheduler = scheduler
+	}
+}
+
+// WithDefaultScheduler creates a scheduler.Scheduler using the Client's rest.Rest with the given options.
+func WithDefaultScheduler(opts ...scheduler.ConfigOpt) ConfigOpt {
+	return func(config *config) {
+		config.SchedulerEnabled = true
+		config.SchedulerConfigOpts = append(config.SchedulerConfigOpts, opts...)
+	}
+}
+
 func WithVoiceManager(voiceManager voice.Manager) ConfigOpt {
 	return func(config *config) {
 		config.VoiceManager = voiceManager
@@ -181,6 +273,17 @@ func WithVoiceManagerConfigOpts(opts ...voice.ManagerConfigOpt) ConfigOpt {
 	}
 }
 
+// VoiceConnEventHandler is called for every voice.ConnEvent of a voice.Conn created by the default voice.Manager.
+type VoiceConnEventHandler func(client *Client, conn voice.Conn, event voice.ConnEvent)
+
+// WithVoiceConnEventHandler sets the VoiceConnEventHandler of the default voice.Manager.
+// fluxergo.New uses it to dispatch the voice.ConnEvent(s) through the EventManager.
+func WithVoiceConnEventHandler(handler VoiceConnEventHandler) ConfigOpt {
+	return func(config *config) {
+		config.VoiceConnEventHandler = handler
+	}
+}
+
 func defaultGatewayEventHandlerFunc(client *Client) gateway.EventHandlerFunc {
 	return client.EventManager.HandleGatewayEvent
 }
@@ -211,6 +314,7 @@ func BuildClient(
 		Logger:        cfg.Logger,
 		ApplicationID: *id,
 	}
+	client.ctx, client.cancel = context.WithCancel(context.Background())
 
 	if cfg.RestClient == nil {
 		// prepend standard user-agent. this can be overridden as it's appended to the front of the slice
@@ -231,6 +335,11 @@ func BuildClient(
 	client.Rest = cfg.Rest
 
 	if cfg.VoiceManager == nil {
+		if handler := cfg.VoiceConnEventHandler; handler != nil {
+			cfg.VoiceManagerConfigOpts = append([]voice.ManagerConfigOpt{voice.WithConnConfigOpts(voice.WithConnEventListener(func(conn voice.Conn, event voice.ConnEvent) {
+				handler(client, conn, event)
+			}))}, cfg.VoiceManagerConfigOpts...)
+		}
 		cfg.VoiceManager = voice.NewManager(client.UpdateVoiceState, *id, append([]voice.ManagerConfigOpt{voice.WithLogger(cfg.Logger)}, cfg.VoiceManagerConfigOpts...)...)
 	}
 	client.VoiceManager = cfg.VoiceManager
@@ -258,9 +367,20 @@ func BuildClient(
 			),
 		}, cfg.GatewayConfigOpts...)
 
-		cfg.Gateway = gateway.New(token, defaultGatewayEventHandlerFunc(client), cfg.GatewayConfigOpts...)
+		eventHandlerFunc := defaultGatewayEventHandlerFunc(client)
+		if cfg.EventOutbox != nil {
+			eventHandlerFunc = newOutboxEventHandlerFunc(cfg.Logger, cfg.EventOutbox, eventHandlerFunc)
+		}
+		if cfg.EventSink != nil {
+			client.eventSinkForwarder = newEventSinkForwarder(cfg.Logger, cfg.EventSink, cfg.EventSinkSendTimeout, cfg.EventSinkBufferSize, cfg.EventSinkDropFunc)
+			eventHandlerFunc = client.eventSinkForwarder.eventHandlerFunc(eventHandlerFunc)
+		}
+		cfg.Gateway = gateway.New(token, eventHandlerFunc, cfg.GatewayConfigOpts...)
 	}
 	client.Gateway = cfg.Gateway
+	client.eventOutbox = cfg.EventOutbox
+	client.eventSink = cfg.EventSink
+	client.eventSource = cfg.EventSource
 
 	if cfg.MemberChunkingManager == nil {
 		cfg.MemberChunkingManager = NewMemberChunkingManager(client, cfg.Logger, cfg.MemberChunkingFilter)
@@ -272,5 +392,13 @@ func BuildClient(
 	}
 	client.Caches = cfg.Caches
 
+	if cfg.Scheduler == nil && cfg.SchedulerEnabled {
+		cfg.Scheduler, err = scheduler.New(client.Rest, append([]scheduler.ConfigOpt{scheduler.WithLogger(cfg.Logger)}, cfg.SchedulerConfigOpts...)...)
+		if err != nil {
+			return nil, err
+		}
+	}
+	client.Scheduler = cfg.Scheduler
+
 	return client, nil
 }
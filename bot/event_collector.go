@@ -2,45 +2,228 @@ package bot
 
 import (
 	"context"
+	"errors"
+	"iter"
 	"sync"
+	"time"
+)
+
+var (
+	// ErrCollectorTimeout is returned by Collector.Err if the Collector was stopped by its timeout.
+	ErrCollectorTimeout = errors.New("collector timed out")
+	// ErrCollectorIdleTimeout is returned by Collector.Err if the Collector was stopped because no event was collected within its idle timeout.
+	ErrCollectorIdleTimeout = errors.New("collector idle timed out")
 )
 
 // WaitForEvent waits for an event passing the filterFunc and then calls the actionFunc. You can cancel this function with the passed context.Context and the cancelFunc gets called then.
 func WaitForEvent[E Event](client *Client, ctx context.Context, filterFunc func(e E) bool, actionFunc func(e E), cancelFunc func()) {
-	ch, cancel := NewEventCollector(client, filterFunc)
-
-	select {
-	case <-ctx.Done():
-		cancel()
+	e, err := NewCollector(client, filterFunc, WithCollectorContext(ctx), WithCollectorMaxCount(1)).Next()
+	if err != nil {
 		if cancelFunc != nil {
 			cancelFunc()
 		}
-	case e := <-ch:
-		cancel()
-		if actionFunc != nil {
-			actionFunc(e)
-		}
+		return
+	}
+	if actionFunc != nil {
+		actionFunc(e)
 	}
 }
 
 // NewEventCollector returns a channel in which the events of type T gets sent which pass the passed filter and a function which can be used to stop the event collector.
 // The close function needs to be called to stop the event collector.
+// The channel is unbuffered and no events are dropped, so the EventManager blocks until the event is received or the collector is stopped.
+// Use NewCollector for more control over buffering and timeouts.
 func NewEventCollector[E Event](client *Client, filterFunc func(e E) bool) (<-chan E, func()) {
-	ch := make(chan E)
-	var once sync.Once
+	collector := NewCollector(client, filterFunc, WithCollectorBufferSize(0), WithCollectorBlocking())
+	return collector.Events(), collector.Stop
+}
+
+// NewCollector returns a new started Collector for events of type E passing the filterFunc with the CollectorConfigOpt(s) applied.
+// A nil filterFunc collects all events of type E.
+func NewCollector[E Event](client *Client, filterFunc func(e E) bool, opts ...CollectorConfigOpt) *Collector[E] {
+	cfg := defaultCollectorConfig()
+	cfg.apply(opts)
+	if cfg.Context == nil {
+		cfg.Context = client.Context()
+	}
 
-	handler := NewListenerFunc(func(e E) {
-		if !filterFunc(e) {
+	c := &Collector[E]{
+		config: cfg,
+		ch:     make(chan E, cfg.BufferSize),
+		done:   make(chan struct{}),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.unsubscribe = client.EventManager.Subscribe(NewListenerFunc(func(e E) {
+		if filterFunc != nil && !filterFunc(e) {
 			return
 		}
-		ch <- e
+		c.collect(e)
+	}))
+	c.stopContext = context.AfterFunc(cfg.Context, func() {
+		c.stop(cfg.Context.Err())
 	})
-	client.EventManager.AddEventListeners(handler)
-
-	return ch, func() {
-		once.Do(func() {
-			client.EventManager.RemoveEventListeners(handler)
-			close(ch)
+	if cfg.Timeout > 0 {
+		c.timeout = time.AfterFunc(cfg.Timeout, func() {
+			c.stop(ErrCollectorTimeout)
 		})
 	}
+	if cfg.IdleTimeout > 0 {
+		c.idleTimeout = time.AfterFunc(cfg.IdleTimeout, func() {
+			c.stop(ErrCollectorIdleTimeout)
+		})
+	}
+	return c
+}
+
+// Collector collects events of type E until it is stopped by Stop, its maximum count, a timeout or its context.
+type Collector[E Event] struct {
+	config collectorConfig
+
+	mu   sync.Mutex
+	ch   chan E
+	done chan struct{}
+	// sending is the number of blocking sends to ch, which is closed once all of them returned.
+	sending     int
+	stopped     bool
+	err         error
+	count       int
+	dropped     int
+	unsubscribe Unsubscribe
+	stopContext func() bool
+	timeout     *time.Timer
+	idleTimeout *time.Timer
+}
+
+func (c *Collector[E]) collect(e E) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped {
+		return
+	}
+
+	if c.config.Blocking {
+		c.sending++
+		c.mu.Unlock()
+		var sent bool
+		select {
+		case c.ch <- e:
+			sent = true
+		case <-c.done:
+		}
+		c.mu.Lock()
+		c.sending--
+		if c.stopped {
+			if c.sending == 0 {
+				close(c.ch)
+			}
+			return
+		}
+		if !sent {
+			return
+		}
+	} else {
+		select {
+		case c.ch <- e:
+		default:
+			c.dropped++
+			return
+		}
+	}
+	c.count++
+	if c.idleTimeout != nil {
+		c.idleTimeout.Reset(c.config.IdleTimeout)
+	}
+	if c.config.MaxCount > 0 && c.count >= c.config.MaxCount {
+		c.stopLocked(nil)
+	}
+}
+
+// Events returns the channel the collected events are sent to. It is closed once the Collector is stopped.
+func (c *Collector[E]) Events() <-chan E {
+	return c.ch
+}
+
+// All returns an iter.Seq yielding all collected events until the Collector is stopped.
+// Breaking out of the loop stops the Collector.
+func (c *Collector[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for e := range c.ch {
+			if !yield(e) {
+				c.Stop()
+				return
+			}
+		}
+	}
+}
+
+// Next returns the next collected event or the error the Collector was stopped with.
+// If the Collector was stopped without error, context.Canceled is returned.
+func (c *Collector[E]) Next() (E, error) {
+	e, ok := <-c.ch
+	if !ok {
+		if err := c.Err(); err != nil {
+			return e, err
+		}
+		return e, context.Canceled
+	}
+	return e, nil
+}
+
+// Collect blocks until the Collector is stopped and returns all collected events and the error it was stopped with.
+func (c *Collector[E]) Collect() ([]E, error) {
+	var events []E
+	for e := range c.ch {
+		events = append(events, e)
+	}
+	return events, c.Err()
+}
+
+// Err returns why the Collector was stopped: ErrCollectorTimeout, ErrCollectorIdleTimeout, the context's error
+// or nil if it is still running, was stopped via Stop or reached its maximum count.
+func (c *Collector[E]) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Dropped returns the number of events dropped because the buffer was full. It is always 0 for a blocking Collector.
+func (c *Collector[E]) Dropped() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dropped
+}
+
+// Stop stops the Collector and closes its channel. Events which are already buffered can still be received.
+func (c *Collector[E]) Stop() {
+	c.stop(nil)
+}
+
+func (c *Collector[E]) stop(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopLocked(err)
+}
+
+func (c *Collector[E]) stopLocked(err error) {
+	if c.stopped {
+		return
+	}
+	c.stopped = true
+	c.err = err
+	close(c.done)
+	if c.sending == 0 {
+		close(c.ch)
+	}
+
+	// unsubscribing takes the EventManager's listener lock, which is never held while listeners run, so this can't deadlock
+	c.unsubscribe()
+	c.stopContext()
+	if c.timeout != nil {
+		c.timeout.Stop()
+	}
+	if c.idleTimeout != nil {
+		c.idleTimeout.Stop()
+	}
 }
@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"context"
+	"time"
+)
+
+func defaultCollectorConfig() collectorConfig {
+	return collectorConfig{
+		BufferSize: 16,
+	}
+}
+
+type collectorConfig struct {
+	Context     context.Context
+	BufferSize  int
+	Blocking    bool
+	MaxCount    int
+	Timeout     time.Duration
+	IdleTimeout time.Duration
+}
+
+// CollectorConfigOpt is a functional option for configuring a Collector.
+type CollectorConfigOpt func(config *collectorConfig)
+
+func (c *collectorConfig) apply(opts []CollectorConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.BufferSize < 0 {
+		c.BufferSize = 0
+	}
+}
+
+// WithCollectorContext stops the Collector once the context is done. The default is the Client's context.
+func WithCollectorContext(ctx context.Context) CollectorConfigOpt {
+	return func(config *collectorConfig) {
+		config.Context = ctx
+	}
+}
+
+// WithCollectorBufferSize sets how many events can be buffered until the consumer receives them.
+// Events are dropped while the buffer is full, so a slow consumer never blocks the EventManager, unless WithCollectorBlocking is used. The default is 16.
+func WithCollectorBufferSize(bufferSize int) CollectorConfigOpt {
+	return func(config *collectorConfig) {
+		config.BufferSize = bufferSize
+	}
+}
+
+// WithCollectorBlocking makes the Collector block the EventManager while the buffer is full until the consumer received the event
+// or the Collector is stopped, instead of dropping the event.
+func WithCollectorBlocking() CollectorConfigOpt {
+	return func(config *collectorConfig) {
+		config.Blocking = true
+	}
+}
+
+// WithCollectorMaxCount stops the Collector after the given amount of events were collected.
+func WithCollectorMaxCount(maxCount int) CollectorConfigOpt {
+	return func(config *collectorConfig) {
+		config.MaxCount = maxCount
+	}
+}
+
+// WithCollectorTimeout stops the Collector with ErrCollectorTimeout after the given duration.
+func WithCollectorTimeout(timeout time.Duration) CollectorConfigOpt {
+	return func(config *collectorConfig) {
+		config.Timeout = timeout
+	}
+}
+
+// WithCollectorIdleTimeout stops the Collector with ErrCollectorIdleTimeout if no event was collected for the given duration.
+func WithCollectorIdleTimeout(idleTimeout time.Duration) CollectorConfigOpt {
+	return func(config *collectorConfig) {
+		config.IdleTimeout = idleTimeout
+	}
+}
@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCollector(t *testing.T) {
+	client := &Client{EventManager: NewEventManager(nil)}
+
+	collector := NewCollector(client, func(e *testEvent) bool { return e.n%2 == 0 },
+		WithCollectorMaxCount(3),
+		WithCollectorBufferSize(2),
+	)
+	for i := range 10 {
+		client.EventManager.DispatchEvent(&testEvent{n: i})
+		if i == 4 {
+			// drain the buffer, so the next events are not dropped
+			<-collector.Events()
+			<-collector.Events()
+		}
+	}
+
+	var got []int
+	for e := range collector.All() {
+		got = append(got, e.n)
+	}
+	if len(got) != 1 || got[0] != 6 {
+		t.Errorf("expected event 6 to be collected last, got %v", got)
+	}
+	if err := collector.Err(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestCollector_Timeouts(t *testing.T) {
+	client := &Client{EventManager: NewEventManager(nil)}
+
+	events, err := NewCollector[*testEvent](client, nil, WithCollectorIdleTimeout(10*time.Millisecond)).Collect()
+	if len(events) != 0 || !errors.Is(err, ErrCollectorIdleTimeout) {
+		t.Errorf("expected idle timeout, got %v and %v", events, err)
+	}
+
+	collector := NewCollector[*testEvent](client, nil, WithCollectorTimeout(20*time.Millisecond))
+	client.EventManager.DispatchEvent(&testEvent{n: 1})
+	events, err = collector.Collect()
+	if len(events) != 1 || !errors.Is(err, ErrCollectorTimeout) {
+		t.Errorf("expected one event and timeout, got %v and %v", events, err)
+	}
+}
+
+func TestEventCollector_Lossless(t *testing.T) {
+	client := &Client{EventManager: NewEventManager(nil)}
+	ch, stop := NewEventCollector(client, func(*testEvent) bool { return true })
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 4 {
+			client.EventManager.DispatchEvent(&testEvent{n: i})
+		}
+	}()
+	for i := range 3 {
+		if e := <-ch; e.n != i {
+			t.Fatalf("expected event %d, got %d", i, e.n)
+		}
+	}
+
+	// the last dispatch blocks until the collector is stopped
+	time.Sleep(10 * time.Millisecond)
+	stop()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected blocked dispatch to be released by stop")
+	}
+	// the blocked event may still be received while the channel is closed
+	for e := range ch {
+		if e.n != 3 {
+			t.Errorf("unexpected event %d after stop", e.n)
+		}
+	}
+}
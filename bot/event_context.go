@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ContextEventListener is an EventListener which receives a context.Context with every Event.
+// The context derives from Event.Context and is additionally cancelled after the listener's timeout (see NewListenerWithTimeout and WithListenerTimeout).
+type ContextEventListener interface {
+	EventListener
+	OnEventContext(ctx context.Context, event Event)
+}
+
+// NewListenerFuncContext returns a new ContextEventListener for the given func(ctx context.Context, e E)
+func NewListenerFuncContext[E Event](f func(ctx context.Context, e E)) EventListener {
+	return &listenerFuncContext[E]{f: f}
+}
+
+type listenerFuncContext[E Event] struct {
+	f func(ctx context.Context, e E)
+}
+
+func (l *listenerFuncContext[E]) OnEvent(e Event) {
+	l.OnEventContext(e.Context(), e)
+}
+
+func (l *listenerFuncContext[E]) OnEventContext(ctx context.Context, e Event) {
+	if event, ok := e.(E); ok {
+		l.f(ctx, event)
+	}
+}
+
+func (l *listenerFuncContext[E]) EventType() reflect.Type {
+	return reflect.TypeFor[E]()
+}
+
+// NewListenerWithTimeout returns an EventListener which cancels the context passed to the given ContextEventListener after the timeout.
+// This overrides the timeout configured via WithListenerTimeout.
+func NewListenerWithTimeout(listener EventListener, timeout time.Duration) EventListener {
+	return &listenerWithTimeout{listener: listener, timeout: timeout}
+}
+
+type listenerWithTimeout struct {
+	listener EventListener
+	timeout  time.Duration
+}
+
+func (l *listenerWithTimeout) OnEvent(e Event) {
+	l.OnEventContext(e.Context(), e)
+}
+
+func (l *listenerWithTimeout) OnEventContext(ctx context.Context, e Event) {
+	if l.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.timeout)
+		defer cancel()
+	}
+	if listener, ok := l.listener.(ContextEventListener); ok {
+		listener.OnEventContext(ctx, e)
+		return
+	}
+	l.listener.OnEvent(e)
+}
+
+// EventType returns the type of the wrapped TypedEventListener or nil if it handles all events.
+func (l *listenerWithTimeout) EventType() reflect.Type {
+	if listener, ok := l.listener.(TypedEventListener); ok {
+		return listener.EventType()
+	}
+	return nil
+}
+
+// listenerTracker counts the running EventListener(s), so they can be waited for on close.
+// Unlike a sync.WaitGroup it allows starting new listeners while waiting.
+type listenerTracker struct {
+	mu      sync.Mutex
+	running int
+	idle    chan struct{}
+}
+
+func (t *listenerTracker) start() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.running == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.running++
+}
+
+func (t *listenerTracker) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.running--
+	if t.running == 0 {
+		close(t.idle)
+	}
+}
+
+// wait blocks until no EventListener is running or the context is done.
+func (t *listenerTracker) wait(ctx context.Context) error {
+	t.mu.Lock()
+	if t.running == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,112 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEventManager_ListenerTimeout(t *testing.T) {
+	errs := make(chan error, 2)
+	m := NewEventManager(nil,
+		WithListenerTimeout(10*time.Millisecond),
+		WithListeners(
+			NewListenerFuncContext(func(ctx context.Context, e *testEvent) {
+				<-ctx.Done()
+				errs <- ctx.Err()
+			}),
+			NewListenerWithTimeout(NewListenerFuncContext(func(ctx context.Context, e *testEvent) {
+				if _, ok := ctx.Deadline(); ok {
+					errs <- errors.New("expected no deadline")
+					return
+				}
+				errs <- nil
+			}), 0),
+		),
+	)
+
+	m.DispatchEvent(&testEvent{})
+	if err := <-errs; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if err := <-errs; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEventManager_CloseWaitsForListeners(t *testing.T) {
+	release := make(chan struct{})
+	var returned bool
+	m := NewEventManager(nil,
+		WithAsyncEventsEnabled(),
+		WithListenerFunc(func(e *testEvent) {
+			<-release
+			returned = true
+		}),
+	)
+	m.DispatchEvent(&testEvent{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	m.Close(ctx)
+	if returned {
+		t.Fatal("expected listener to still be running")
+	}
+
+	close(release)
+	m.Close(context.Background())
+	if !returned {
+		t.Error("expected Close to wait for the listener")
+	}
+}
+
+type clientContextEvent struct {
+	testEvent
+	client *Client
+}
+
+func (e *clientContextEvent) Context() context.Context { return e.client.Context() }
+
+func TestClient_CloseCancelsListeners(t *testing.T) {
+	client := &Client{}
+	client.ctx, client.cancel = context.WithCancel(context.Background())
+	released := make(chan struct{})
+	client.EventManager = NewEventManager(client,
+		WithAsyncEventsEnabled(),
+		WithListenerFunc(func(e *clientContextEvent) {
+			<-e.Context().Done()
+			close(released)
+		}),
+	)
+	client.EventManager.DispatchEvent(&clientContextEvent{client: client})
+
+	closed := make(chan struct{})
+	go func() {
+		client.Close(context.Background())
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("expected Close to cancel the listener and return")
+	}
+	select {
+	case <-released:
+	default:
+		t.Error("expected Close to wait for the released listener")
+	}
+
+	// with a deadline, the listener may run until the deadline passed
+	client.ctx, client.cancel = context.WithCancel(context.Background())
+	released = make(chan struct{})
+	client.EventManager.DispatchEvent(&clientContextEvent{client: client})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	client.Close(ctx)
+	<-released
+	if took := time.Since(start); took < 20*time.Millisecond {
+		t.Errorf("expected listener to run until the deadline, took %s", took)
+	}
+}
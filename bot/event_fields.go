@@ -0,0 +1,104 @@
+package bot
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/fluxer"
+)
+
+var (
+	snowflakeType    = reflect.TypeOf(snowflake.ID(0))
+	snowflakePtrType = reflect.TypeOf((*snowflake.ID)(nil))
+	messageType      = reflect.TypeOf(fluxer.Message{})
+
+	// eventFieldIndexes caches the field index of snowflake and message fields per event type and field name.
+	eventFieldIndexes sync.Map // map[eventFieldKey][]int
+)
+
+type eventFieldKey struct {
+	t    reflect.Type
+	name string
+}
+
+// EventGuildID returns the guild ID of the Event if it has a non-zero GuildID field.
+func EventGuildID(event Event) (snowflake.ID, bool) {
+	return eventSnowflakeField(event, "GuildID")
+}
+
+// EventChannelID returns the channel ID of the Event if it has a non-zero ChannelID field.
+func EventChannelID(event Event) (snowflake.ID, bool) {
+	return eventSnowflakeField(event, "ChannelID")
+}
+
+// EventMessageAuthor returns the author of the message of the Event if it has a Message field.
+func EventMessageAuthor(event Event) (fluxer.User, bool) {
+	field, ok := eventField(event, "Message", messageType)
+	if !ok {
+		return fluxer.User{}, false
+	}
+	return field.Interface().(fluxer.Message).Author, true
+}
+
+// eventSnowflakeField returns the value of the snowflake.ID or *snowflake.ID field with the given name.
+// Fields of embedded structs are found as well. Zero and nil values are reported as not found.
+func eventSnowflakeField(event Event, name string) (snowflake.ID, bool) {
+	field, ok := eventField(event, name, snowflakeType, snowflakePtrType)
+	if !ok {
+		return 0, false
+	}
+	switch field.Type() {
+	case snowflakeType:
+		id := snowflake.ID(field.Uint())
+		return id, id != 0
+	case snowflakePtrType:
+		if field.IsNil() {
+			return 0, false
+		}
+		id := field.Elem().Interface().(snowflake.ID)
+		return id, id != 0
+	}
+	return 0, false
+}
+
+// eventField returns the field with the given name if it has one of the given types.
+// Fields of embedded structs are found as well.
+func eventField(event Event, name string, types ...reflect.Type) (reflect.Value, bool) {
+	v := reflect.ValueOf(event)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	index := eventFieldIndex(v.Type(), name, types)
+	if index == nil {
+		return reflect.Value{}, false
+	}
+	field, err := v.FieldByIndexErr(index)
+	if err != nil {
+		// nil embedded pointer
+		return reflect.Value{}, false
+	}
+	return field, true
+}
+
+func eventFieldIndex(t reflect.Type, name string, types []reflect.Type) []int {
+	key := eventFieldKey{t: t, name: name}
+	if index, ok := eventFieldIndexes.Load(key); ok {
+		return index.([]int)
+	}
+	var index []int
+	if field, ok := t.FieldByName(name); ok && slices.Contains(types, field.Type) {
+		index = field.Index
+	}
+	eventFieldIndexes.Store(key, index)
+	return index
+}
@@ -1,11 +1,13 @@
 package bot
 
 import (
+	"context"
 	"log/slog"
+	"reflect"
 	"sync"
+	"time"
 
 	"github.com/fluxergo/fluxergo/gateway"
-	"github.com/fluxergo/fluxergo/internal/xdebug"
 )
 
 var _ EventManager = (*eventManagerImpl)(nil)
@@ -15,13 +17,17 @@ func NewEventManager(client *Client, opts ...EventManagerConfigOpt) EventManager
 	cfg := defaultEventManagerConfig()
 	cfg.apply(opts)
 
-	return &eventManagerImpl{
+	e := &eventManagerImpl{
 		client:             client,
 		logger:             cfg.Logger,
-		eventListeners:     cfg.EventListeners,
+		middlewares:        append([]EventMiddleware{NewRecoverMiddleware(cfg.Logger)}, cfg.EventMiddlewares...),
+		listenerTimeout:    cfg.ListenerTimeout,
 		asyncEventsEnabled: cfg.AsyncEventsEnabled,
+		workerPool:         cfg.EventWorkerPool,
 		gatewayHandlers:    cfg.GatewayHandlers,
 	}
+	e.AddEventListeners(cfg.EventListeners...)
+	return e
 }
 
 // EventManager lets you listen for specific events triggered by raw Gateway events
@@ -29,14 +35,26 @@ type EventManager interface {
 	// AddEventListeners adds one or more EventListener(s) to the EventManager
 	AddEventListeners(eventListeners ...EventListener)
 
-	// RemoveEventListeners removes one or more EventListener(s) from the EventManager
+	// RemoveEventListeners removes one or more EventListener(s) from the EventManager.
+	// EventListener(s) of incomparable types like EventListenerFunc can only be removed via the Unsubscribe returned by Subscribe.
 	RemoveEventListeners(eventListeners ...EventListener)
 
+	// Subscribe adds the EventListener to the EventManager and returns an Unsubscribe to remove it again.
+	// A TypedEventListener is only called for matching events.
+	Subscribe(eventListener EventListener) Unsubscribe
+
 	// HandleGatewayEvent calls the correct GatewayEventHandler for the payload
 	HandleGatewayEvent(gateway gateway.Gateway, eventType gateway.EventType, sequenceNumber int, event gateway.EventData)
 
+	// HandleShardEvent calls the correct GatewayEventHandler for the payload received by the shard with the given ID.
+	// It is used for events which were not received by a local gateway.Gateway, for example from an EventSource.
+	HandleShardEvent(shardID int, eventType gateway.EventType, sequenceNumber int, event gateway.EventData)
+
 	// DispatchEvent dispatches a new Event to the Client's EventListener(s)
 	DispatchEvent(event Event)
+
+	// Close waits until all queued events are handled and all running EventListener(s) returned or the context is done.
+	Close(ctx context.Context)
 }
 
 // EventListener is used to create new EventListener to listen to events
@@ -78,6 +96,8 @@ func (l *listenerChan[E]) OnEvent(e Event) {
 type Event interface {
 	Client() *Client
 	SequenceNumber() int
+	// Context returns the context.Context of the Event. It is cancelled once the Client is closed.
+	Context() context.Context
 }
 
 // GatewayEventHandler is used to handle Gateway Event(s)
@@ -112,62 +132,93 @@ type eventManagerImpl struct {
 	client             *Client
 	logger             *slog.Logger
 	eventListenerMu    sync.Mutex
-	eventListeners     []EventListener
+	eventListeners     eventRegistry
+	middlewares        []EventMiddleware
+	listenerTimeout    time.Duration
+	running            listenerTracker
 	asyncEventsEnabled bool
+	workerPool         EventWorkerPool
 	gatewayHandlers    map[gateway.EventType]GatewayEventHandler
 }
 
 func (e *eventManagerImpl) HandleGatewayEvent(gateway gateway.Gateway, eventType gateway.EventType, sequenceNumber int, event gateway.EventData) {
+	e.HandleShardEvent(gateway.ShardID(), eventType, sequenceNumber, event)
+}
+
+func (e *eventManagerImpl) HandleShardEvent(shardID int, eventType gateway.EventType, sequenceNumber int, event gateway.EventData) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	if handler, ok := e.gatewayHandlers[eventType]; ok {
-		handler.HandleGatewayEvent(e.client, sequenceNumber, gateway.ShardID(), event)
+		handler.HandleGatewayEvent(e.client, sequenceNumber, shardID, event)
 	} else {
 		e.logger.Warn("no handler for Gateway event found", slog.Any("event_type", eventType))
 	}
 }
 
 func (e *eventManagerImpl) DispatchEvent(event Event) {
-	defer func() {
-		if r := recover(); r != nil {
-			e.logger.Error("recovered from panic in event listener", slog.Any("arg", r), slog.String("stack", string(xdebug.Stack(3))))
-			return
-		}
-	}()
 	e.eventListenerMu.Lock()
-	defer e.eventListenerMu.Unlock()
-	for _, listener := range e.eventListeners {
+	listeners := e.eventListeners.listenersFor(reflect.TypeOf(event))
+	e.eventListenerMu.Unlock()
+
+	if e.workerPool != nil {
+		e.workerPool.Dispatch(event, listeners)
+		return
+	}
+	for _, listener := range listeners {
+		e.running.start()
 		if e.asyncEventsEnabled {
 			go func() {
-				defer func() {
-					if r := recover(); r != nil {
-						e.logger.Error("recovered from panic in event listener", slog.Any("arg", r), slog.String("stack", string(xdebug.Stack(3))))
-						return
-					}
-				}()
+				defer e.running.done()
 				listener.OnEvent(event)
 			}()
 			continue
 		}
 		listener.OnEvent(event)
+		e.running.done()
+	}
+}
+
+// wrapListener applies the listener timeout and all EventMiddleware(s) to the EventListener.
+func (e *eventManagerImpl) wrapListener(listener EventListener) EventListener {
+	wrapped := listener
+	if _, ok := listener.(*listenerWithTimeout); !ok && e.listenerTimeout > 0 {
+		wrapped = NewListenerWithTimeout(listener, e.listenerTimeout)
 	}
+	return applyEventMiddlewares(wrapped, e.middlewares)
 }
 
 func (e *eventManagerImpl) AddEventListeners(listeners ...EventListener) {
 	e.eventListenerMu.Lock()
 	defer e.eventListenerMu.Unlock()
-	e.eventListeners = append(e.eventListeners, listeners...)
+	for _, listener := range listeners {
+		e.eventListeners.add(listener, e.wrapListener(listener))
+	}
 }
 
 func (e *eventManagerImpl) RemoveEventListeners(listeners ...EventListener) {
 	e.eventListenerMu.Lock()
 	defer e.eventListenerMu.Unlock()
 	for _, listener := range listeners {
-		for i, l := range e.eventListeners {
-			if l == listener {
-				e.eventListeners = append(e.eventListeners[:i], e.eventListeners[i+1:]...)
-				break
-			}
-		}
+		e.eventListeners.removeListener(listener)
+	}
+}
+
+func (e *eventManagerImpl) Subscribe(listener EventListener) Unsubscribe {
+	e.eventListenerMu.Lock()
+	defer e.eventListenerMu.Unlock()
+	id := e.eventListeners.add(listener, e.wrapListener(listener))
+	return func() {
+		e.eventListenerMu.Lock()
+		defer e.eventListenerMu.Unlock()
+		e.eventListeners.removeID(id)
+	}
+}
+
+func (e *eventManagerImpl) Close(ctx context.Context) {
+	if e.workerPool != nil {
+		e.workerPool.Close(ctx)
+	}
+	if err := e.running.wait(ctx); err != nil {
+		e.logger.Warn("event listeners did not return before the context was done", slog.Any("err", err))
 	}
 }
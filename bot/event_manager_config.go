@@ -2,6 +2,7 @@ package bot
 
 import (
 	"log/slog"
+	"time"
 
 	"github.com/fluxergo/fluxergo/gateway"
 )
@@ -13,9 +14,13 @@ func defaultEventManagerConfig() eventManagerConfig {
 }
 
 type eventManagerConfig struct {
-	Logger             *slog.Logger
-	EventListeners     []EventListener
-	AsyncEventsEnabled bool
+	Logger                    *slog.Logger
+	EventMiddlewares          []EventMiddleware
+	EventListeners            []EventListener
+	AsyncEventsEnabled        bool
+	ListenerTimeout           time.Duration
+	EventWorkerPool           EventWorkerPool
+	EventWorkerPoolConfigOpts []EventWorkerPoolConfigOpt
 
 	GatewayHandlers map[gateway.EventType]GatewayEventHandler
 }
@@ -27,6 +32,9 @@ func (c *eventManagerConfig) apply(opts []EventManagerConfigOpt) {
 	for _, opt := range opts {
 		opt(c)
 	}
+	if c.EventWorkerPool == nil && len(c.EventWorkerPoolConfigOpts) > 0 {
+		c.EventWorkerPool = NewEventWorkerPool(append([]EventWorkerPoolConfigOpt{WithWorkerPoolLogger(c.Logger)}, c.EventWorkerPoolConfigOpts...)...)
+	}
 	c.Logger = c.Logger.With(slog.String("name", "bot_event_manager"))
 }
 
@@ -44,6 +52,14 @@ func WithListeners(listeners ...EventListener) EventManagerConfigOpt {
 	}
 }
 
+// WithMiddlewares adds the given EventMiddleware(s) to the eventManagerConfig.
+// They wrap every EventListener in the order they are added, inside the default recover EventMiddleware.
+func WithMiddlewares(middlewares ...EventMiddleware) EventManagerConfigOpt {
+	return func(config *eventManagerConfig) {
+		config.EventMiddlewares = append(config.EventMiddlewares, middlewares...)
+	}
+}
+
 // WithListenerFunc adds the given func(e E) to the eventManagerConfig.
 func WithListenerFunc[E Event](f func(e E)) EventManagerConfigOpt {
 	return WithListeners(NewListenerFunc(f))
@@ -61,6 +77,29 @@ func WithAsyncEventsEnabled() EventManagerConfigOpt {
 	}
 }
 
+// WithListenerTimeout sets the default timeout after which the context passed to ContextEventListener(s) is cancelled.
+// Use NewListenerWithTimeout to set the timeout of a single EventListener.
+func WithListenerTimeout(timeout time.Duration) EventManagerConfigOpt {
+	return func(config *eventManagerConfig) {
+		config.ListenerTimeout = timeout
+	}
+}
+
+// WithEventWorkerPool dispatches events using the given EventWorkerPool. This takes precedence over WithAsyncEventsEnabled.
+func WithEventWorkerPool(pool EventWorkerPool) EventManagerConfigOpt {
+	return func(config *eventManagerConfig) {
+		config.EventWorkerPool = pool
+	}
+}
+
+// WithEventWorkerPoolConfigOpts dispatches events using a new EventWorkerPool with the given EventWorkerPoolConfigOpt(s) applied.
+// This takes precedence over WithAsyncEventsEnabled.
+func WithEventWorkerPoolConfigOpts(opts ...EventWorkerPoolConfigOpt) EventManagerConfigOpt {
+	return func(config *eventManagerConfig) {
+		config.EventWorkerPoolConfigOpts = append(config.EventWorkerPoolConfigOpts, opts...)
+	}
+}
+
 // WithGatewayHandlers overrides the default GatewayEventHandler(s) in the eventManagerConfig.
 func WithGatewayHandlers(handlers map[gateway.EventType]GatewayEventHandler) EventManagerConfigOpt {
 	return func(config *eventManagerConfig) {
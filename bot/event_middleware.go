@@ -0,0 +1,128 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/internal/xdebug"
+)
+
+// EventMiddleware wraps an EventListener to run code around every invocation of it.
+// A middleware can skip the invocation by not calling next.
+type EventMiddleware func(next EventListener) EventListener
+
+// EventListenerFunc is a func(Event) which implements EventListener.
+type EventListenerFunc func(event Event)
+
+// OnEvent calls f(event).
+func (f EventListenerFunc) OnEvent(event Event) {
+	f(event)
+}
+
+// applyEventMiddlewares wraps the EventListener with the given EventMiddleware(s).
+// The first EventMiddleware is the outermost one.
+func applyEventMiddlewares(listener EventListener, middlewares []EventMiddleware) EventListener {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		listener = middlewares[i](listener)
+	}
+	return listener
+}
+
+// NewRecoverMiddleware returns an EventMiddleware which recovers from panics in the EventListener and logs them.
+// The default EventManager always uses this as its outermost EventMiddleware.
+func NewRecoverMiddleware(logger *slog.Logger) EventMiddleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next EventListener) EventListener {
+		return EventListenerFunc(func(event Event) {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Error("recovered from panic in event listener", slog.Any("arg", r), slog.String("stack", string(xdebug.Stack(3))))
+				}
+			}()
+			next.OnEvent(event)
+		})
+	}
+}
+
+// NewLoggerMiddleware returns an EventMiddleware which logs every Event at the given level before passing it on.
+// The event type, sequence number and if present the guild and channel ID are added as attributes.
+func NewLoggerMiddleware(logger *slog.Logger, level slog.Level) EventMiddleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next EventListener) EventListener {
+		return EventListenerFunc(func(event Event) {
+			logger.LogAttrs(context.Background(), level, "handling event", eventAttrs(event)...)
+			next.OnEvent(event)
+		})
+	}
+}
+
+// NewTimingMiddleware returns an EventMiddleware which calls report with the duration every EventListener invocation took.
+func NewTimingMiddleware(report func(event Event, took time.Duration)) EventMiddleware {
+	return func(next EventListener) EventListener {
+		return EventListenerFunc(func(event Event) {
+			start := time.Now()
+			defer func() {
+				report(event, time.Since(start))
+			}()
+			next.OnEvent(event)
+		})
+	}
+}
+
+// NewGuildFilterMiddleware returns an EventMiddleware which only passes on events of guilds for which allow returns true.
+// Events without a guild are always passed on.
+func NewGuildFilterMiddleware(allow func(guildID snowflake.ID) bool) EventMiddleware {
+	return newFilterMiddleware(EventGuildID, allow)
+}
+
+// NewChannelFilterMiddleware returns an EventMiddleware which only passes on events of channels for which allow returns true.
+// Events without a channel are always passed on.
+func NewChannelFilterMiddleware(allow func(channelID snowflake.ID) bool) EventMiddleware {
+	return newFilterMiddleware(EventChannelID, allow)
+}
+
+// NewIgnoreBotsMiddleware returns an EventMiddleware which drops message events whose author is a bot, see EventMessageAuthor.
+// Events without a message are always passed on.
+func NewIgnoreBotsMiddleware() EventMiddleware {
+	return func(next EventListener) EventListener {
+		return EventListenerFunc(func(event Event) {
+			if author, ok := EventMessageAuthor(event); ok && author.Bot {
+				return
+			}
+			next.OnEvent(event)
+		})
+	}
+}
+
+func newFilterMiddleware(id func(event Event) (snowflake.ID, bool), allow func(id snowflake.ID) bool) EventMiddleware {
+	return func(next EventListener) EventListener {
+		return EventListenerFunc(func(event Event) {
+			if eventID, ok := id(event); ok && !allow(eventID) {
+				return
+			}
+			next.OnEvent(event)
+		})
+	}
+}
+
+func eventAttrs(event Event) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("event", reflect.TypeOf(event).String()),
+		slog.Int("sequence", event.SequenceNumber()),
+	}
+	if guildID, ok := EventGuildID(event); ok {
+		attrs = append(attrs, slog.String("guild_id", guildID.String()))
+	}
+	if channelID, ok := EventChannelID(event); ok {
+		attrs = append(attrs, slog.String("channel_id", channelID.String()))
+	}
+	return attrs
+}
@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/fluxer"
+)
+
+func TestEventManager_Middlewares(t *testing.T) {
+	var calls []string
+	middleware := func(name string) EventMiddleware {
+		return func(next EventListener) EventListener {
+			return EventListenerFunc(func(event Event) {
+				calls = append(calls, name)
+				next.OnEvent(event)
+			})
+		}
+	}
+
+	var handled []int
+	m := NewEventManager(nil,
+		WithMiddlewares(middleware("a"), middleware("b"), NewGuildFilterMiddleware(func(guildID snowflake.ID) bool {
+			return guildID != 2
+		})),
+		WithListenerFunc(func(e *testEvent) {
+			handled = append(handled, e.n)
+			if e.n == 3 {
+				panic("listener panic")
+			}
+		}),
+	)
+
+	m.DispatchEvent(&testEvent{GuildID: 1, n: 1})
+	m.DispatchEvent(&testEvent{GuildID: 2, n: 2})
+	m.DispatchEvent(&testEvent{GuildID: 1, n: 3})
+
+	if len(calls) != 6 || calls[0] != "a" || calls[1] != "b" {
+		t.Errorf("expected middlewares to be called in order, got %v", calls)
+	}
+	if len(handled) != 2 || handled[0] != 1 || handled[1] != 3 {
+		t.Errorf("expected events 1 and 3 to be handled, got %v", handled)
+	}
+}
+
+type testAuthoredEvent struct {
+	*testEvent
+	Message fluxer.Message
+}
+
+func TestIgnoreBotsMiddleware(t *testing.T) {
+	var handled []int
+	m := NewEventManager(nil,
+		WithMiddlewares(NewIgnoreBotsMiddleware()),
+		WithListenerFunc(func(e *testAuthoredEvent) {
+			handled = append(handled, e.n)
+		}),
+		WithListenerFunc(func(e *testEvent) {
+			handled = append(handled, e.n)
+		}),
+	)
+
+	m.DispatchEvent(&testAuthoredEvent{testEvent: &testEvent{n: 1}, Message: fluxer.Message{Author: fluxer.User{Bot: true}}})
+	m.DispatchEvent(&testAuthoredEvent{testEvent: &testEvent{n: 2}, Message: fluxer.Message{Author: fluxer.User{}}})
+	m.DispatchEvent(&testEvent{n: 3})
+
+	if len(handled) != 2 || handled[0] != 2 || handled[1] != 3 {
+		t.Errorf("expected events 2 and 3 to be handled, got %v", handled)
+	}
+}
@@ -0,0 +1,402 @@
+package bot
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+
+	"github.com/fluxergo/fluxergo/gateway"
+)
+
+// OutboxEntry is a gateway dispatch persisted in an EventOutbox.
+type OutboxEntry struct {
+	SessionID string            `json:"session_id"`
+	Sequence  int               `json:"seq"`
+	ShardID   int               `json:"shard_id"`
+	EventType gateway.EventType `json:"t,omitempty"`
+	Data      json.RawMessage   `json:"d,omitempty"`
+}
+
+type outboxKey struct {
+	sessionID string
+	sequence  int
+}
+
+func (e OutboxEntry) key() outboxKey {
+	return outboxKey{sessionID: e.SessionID, sequence: e.Sequence}
+}
+
+// EventOutbox persists gateway dispatches until all EventListener(s) handled them, so they can be replayed after a crash.
+// Entries are identified by their session ID and sequence number.
+type EventOutbox interface {
+	// Append persists the OutboxEntry. It returns false if an entry with the same session ID and sequence number is pending or was acknowledged recently.
+	Append(entry OutboxEntry) (bool, error)
+
+	// Ack marks the OutboxEntry with the given session ID and sequence number as handled.
+	Ack(sessionID string, sequence int) error
+
+	// Pending returns all OutboxEntry(s) which are not acknowledged in the order they were appended.
+	Pending() []OutboxEntry
+
+	// Close closes the EventOutbox.
+	Close() error
+}
+
+type outboxRecord struct {
+	Op string `json:"op"`
+	OutboxEntry
+}
+
+const (
+	outboxOpAppend = "append"
+	outboxOpAck    = "ack"
+)
+
+var _ EventOutbox = (*fileEventOutbox)(nil)
+
+// NewFileEventOutbox opens or creates a write-ahead log at the given path and returns an EventOutbox backed by it.
+// Pending entries of a previous run are loaded and returned by EventOutbox.Pending.
+func NewFileEventOutbox(path string, opts ...EventOutboxConfigOpt) (EventOutbox, error) {
+	cfg := defaultEventOutboxConfig()
+	cfg.apply(opts)
+
+	o := &fileEventOutbox{
+		config:  cfg,
+		path:    path,
+		pending: make(map[outboxKey]int),
+		acked:   make(map[outboxKey]struct{}),
+	}
+	if err := o.load(); err != nil {
+		return nil, err
+	}
+	if err := o.compact(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+type fileEventOutbox struct {
+	config eventOutboxConfig
+	path   string
+
+	mu      sync.Mutex
+	file    *os.File
+	writer  *bufio.Writer
+	records int
+
+	// entries contains all appended entries in order. Acknowledged entries are set to nil and removed on compaction.
+	entries []*OutboxEntry
+	pending map[outboxKey]int
+	// acked contains the recently acknowledged keys. ackedOrder is used to evict the oldest ones.
+	acked      map[outboxKey]struct{}
+	ackedOrder []outboxKey
+}
+
+func (o *fileEventOutbox) load() error {
+	file, err := os.Open(o.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open event outbox: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var record outboxRecord
+			if jsonErr := json.Unmarshal(line, &record); jsonErr != nil {
+				// a torn write at the end of the file happens if we crashed while appending
+				if errors.Is(err, io.EOF) {
+					o.config.Logger.Warn("ignoring incomplete last record in event outbox", slog.Any("err", jsonErr))
+					break
+				}
+				return fmt.Errorf("failed to decode event outbox record: %w", jsonErr)
+			}
+			o.apply(record)
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read event outbox: %w", err)
+		}
+	}
+	return nil
+}
+
+func (o *fileEventOutbox) apply(record outboxRecord) {
+	key := record.key()
+	switch record.Op {
+	case outboxOpAppend:
+		if _, ok := o.pending[key]; ok {
+			return
+		}
+		entry := record.OutboxEntry
+		o.pending[key] = len(o.entries)
+		o.entries = append(o.entries, &entry)
+	case outboxOpAck:
+		if i, ok := o.pending[key]; ok {
+			o.entries[i] = nil
+			delete(o.pending, key)
+		}
+		o.remember(key)
+	}
+}
+
+// remember adds the key to the recently acknowledged keys and evicts the oldest ones outside the dedup window.
+func (o *fileEventOutbox) remember(key outboxKey) {
+	if _, ok := o.acked[key]; ok {
+		return
+	}
+	o.acked[key] = struct{}{}
+	o.ackedOrder = append(o.ackedOrder, key)
+	for len(o.ackedOrder) > o.config.DedupWindow {
+		delete(o.acked, o.ackedOrder[0])
+		o.ackedOrder = o.ackedOrder[1:]
+	}
+}
+
+// compact rewrites the log file to only contain the pending entries and the recently acknowledged keys.
+func (o *fileEventOutbox) compact() error {
+	tmpPath := o.path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create event outbox: %w", err)
+	}
+	writer := bufio.NewWriter(file)
+
+	var (
+		entries []*OutboxEntry
+		records int
+	)
+	for _, key := range o.ackedOrder {
+		if err = writeOutboxRecord(writer, outboxRecord{Op: outboxOpAck, OutboxEntry: OutboxEntry{SessionID: key.sessionID, Sequence: key.sequence}}); err != nil {
+			break
+		}
+		records++
+	}
+	for _, entry := range o.entries {
+		if err != nil {
+			break
+		}
+		if entry == nil {
+			continue
+		}
+		o.pending[entry.key()] = len(entries)
+		entries = append(entries, entry)
+		err = writeOutboxRecord(writer, outboxRecord{Op: outboxOpAppend, OutboxEntry: *entry})
+		records++
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write event outbox: %w", err)
+	}
+	if err = os.Rename(tmpPath, o.path); err != nil {
+		return fmt.Errorf("failed to replace event outbox: %w", err)
+	}
+
+	if o.file != nil {
+		_ = o.file.Close()
+	}
+	o.file, err = os.OpenFile(o.path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open event outbox: %w", err)
+	}
+	o.writer = bufio.NewWriter(o.file)
+	o.entries = entries
+	o.records = records
+	return nil
+}
+
+func writeOutboxRecord(w io.Writer, record outboxRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func (o *fileEventOutbox) write(record outboxRecord) error {
+	if o.file == nil {
+		return errors.New("event outbox is closed")
+	}
+	if err := writeOutboxRecord(o.writer, record); err != nil {
+		return fmt.Errorf("failed to write event outbox record: %w", err)
+	}
+	if err := o.writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write event outbox record: %w", err)
+	}
+	if o.config.Sync {
+		if err := o.file.Sync(); err != nil {
+			return fmt.Errorf("failed to sync event outbox: %w", err)
+		}
+	}
+	o.records++
+	return nil
+}
+
+// maybeCompact compacts the log file once it reached the compact threshold and mostly contains acknowledged entries.
+func (o *fileEventOutbox) maybeCompact() error {
+	if o.config.CompactThreshold > 0 && o.records >= o.config.CompactThreshold && o.records > 2*(len(o.pending)+len(o.ackedOrder)) {
+		return o.compact()
+	}
+	return nil
+}
+
+func (o *fileEventOutbox) Append(entry OutboxEntry) (bool, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	key := entry.key()
+	if _, ok := o.pending[key]; ok {
+		return false, nil
+	}
+	if _, ok := o.acked[key]; ok {
+		return false, nil
+	}
+	record := outboxRecord{Op: outboxOpAppend, OutboxEntry: entry}
+	if err := o.write(record); err != nil {
+		return false, err
+	}
+	o.apply(record)
+	return true, o.maybeCompact()
+}
+
+func (o *fileEventOutbox) Ack(sessionID string, sequence int) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	record := outboxRecord{Op: outboxOpAck, OutboxEntry: OutboxEntry{SessionID: sessionID, Sequence: sequence}}
+	if _, ok := o.pending[record.key()]; !ok {
+		return nil
+	}
+	if err := o.write(record); err != nil {
+		return err
+	}
+	o.apply(record)
+	return o.maybeCompact()
+}
+
+func (o *fileEventOutbox) Pending() []OutboxEntry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	entries := make([]OutboxEntry, 0, len(o.pending))
+	for _, entry := range o.entries {
+		if entry != nil {
+			entries = append(entries, *entry)
+		}
+	}
+	return entries
+}
+
+func (o *fileEventOutbox) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.file == nil {
+		return nil
+	}
+	err := o.file.Close()
+	o.file = nil
+	return err
+}
+
+// newOutboxEventHandlerFunc returns a gateway.EventHandlerFunc which persists every dispatch in the EventOutbox before passing it to next
+// and acknowledges it once next returned. Dispatches which were already handled are skipped.
+// This relies on the gateway passing the raw payload of every dispatch right before the parsed one.
+// The raw payload is only appended once the parsed dispatch follows, as unknown dispatches are never passed parsed.
+func newOutboxEventHandlerFunc(logger *slog.Logger, outbox EventOutbox, next gateway.EventHandlerFunc) gateway.EventHandlerFunc {
+	var (
+		mu sync.Mutex
+		// entries contains the last raw dispatch of every shard
+		entries = make(map[int]OutboxEntry)
+	)
+	return func(gw gateway.Gateway, eventType gateway.EventType, sequenceNumber int, event gateway.EventData) {
+		if eventType == gateway.EventTypeRaw {
+			raw := event.(gateway.EventRaw)
+			if raw.EventType == gateway.EventTypeReady || raw.EventType == gateway.EventTypeResumed {
+				next(gw, eventType, sequenceNumber, event)
+				return
+			}
+			data, err := io.ReadAll(raw.Payload)
+			if err != nil {
+				logger.Error("failed to read raw dispatch", slog.Any("err", err))
+				return
+			}
+			var sessionID string
+			if id := gw.SessionID(); id != nil {
+				sessionID = *id
+			}
+			mu.Lock()
+			entries[gw.ShardID()] = OutboxEntry{
+				SessionID: sessionID,
+				Sequence:  sequenceNumber,
+				ShardID:   gw.ShardID(),
+				EventType: raw.EventType,
+				Data:      data,
+			}
+			mu.Unlock()
+
+			next(gw, eventType, sequenceNumber, gateway.EventRaw{EventType: raw.EventType, Payload: bytes.NewReader(data)})
+			return
+		}
+
+		mu.Lock()
+		entry, ok := entries[gw.ShardID()]
+		delete(entries, gw.ShardID())
+		mu.Unlock()
+		if !ok || entry.Sequence != sequenceNumber || entry.EventType != eventType {
+			next(gw, eventType, sequenceNumber, event)
+			return
+		}
+
+		appended, err := outbox.Append(entry)
+		if err != nil {
+			logger.Error("failed to append dispatch to event outbox", slog.Any("err", err), slog.Int("seq", sequenceNumber))
+		} else if !appended {
+			logger.Debug("skipping already handled dispatch", slog.String("session_id", entry.SessionID), slog.Int("seq", sequenceNumber))
+			return
+		}
+		next(gw, eventType, sequenceNumber, event)
+		if appended {
+			if err = outbox.Ack(entry.SessionID, entry.Sequence); err != nil {
+				logger.Error("failed to acknowledge dispatch in event outbox", slog.Any("err", err), slog.Int("seq", sequenceNumber))
+			}
+		}
+	}
+}
+
+// replayEventOutbox passes all pending dispatches of the EventOutbox to the EventManager and acknowledges them.
+func replayEventOutbox(client *Client, outbox EventOutbox) {
+	pending := outbox.Pending()
+	if len(pending) == 0 {
+		return
+	}
+	client.Logger.Info("replaying unacknowledged dispatches from event outbox", slog.Int("count", len(pending)))
+	for _, entry := range pending {
+		event, err := gateway.UnmarshalEventData(entry.Data, entry.EventType)
+		if err != nil {
+			client.Logger.Error("failed to decode dispatch from event outbox, dropping it", slog.Any("err", err), slog.Int("seq", entry.Sequence))
+		} else {
+			client.EventManager.HandleShardEvent(entry.ShardID, entry.EventType, entry.Sequence, event)
+		}
+		if err = outbox.Ack(entry.SessionID, entry.Sequence); err != nil {
+			client.Logger.Error("failed to acknowledge dispatch in event outbox", slog.Any("err", err), slog.Int("seq", entry.Sequence))
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package bot
+
+import (
+	"log/slog"
+)
+
+func defaultEventOutboxConfig() eventOutboxConfig {
+	return eventOutboxConfig{
+		Logger:           slog.Default(),
+		Sync:             true,
+		DedupWindow:      1000,
+		CompactThreshold: 10000,
+	}
+}
+
+type eventOutboxConfig struct {
+	Logger           *slog.Logger
+	Sync             bool
+	DedupWindow      int
+	CompactThreshold int
+}
+
+// EventOutboxConfigOpt is a functional option for configuring an EventOutbox.
+type EventOutboxConfigOpt func(config *eventOutboxConfig)
+
+func (c *eventOutboxConfig) apply(opts []EventOutboxConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.Logger = c.Logger.With(slog.String("name", "bot_event_outbox"))
+}
+
+// WithEventOutboxLogger overrides the default Logger in the eventOutboxConfig.
+func WithEventOutboxLogger(logger *slog.Logger) EventOutboxConfigOpt {
+	return func(config *eventOutboxConfig) {
+		config.Logger = logger
+	}
+}
+
+// WithEventOutboxSync sets whether the log file is synced to disk after every write. This is enabled by default.
+// Disabling it is faster, but events can be lost if the machine crashes.
+func WithEventOutboxSync(sync bool) EventOutboxConfigOpt {
+	return func(config *eventOutboxConfig) {
+		config.Sync = sync
+	}
+}
+
+// WithEventOutboxDedupWindow sets how many acknowledged events are remembered to detect duplicates. The default is 1000.
+func WithEventOutboxDedupWindow(dedupWindow int) EventOutboxConfigOpt {
+	return func(config *eventOutboxConfig) {
+		config.DedupWindow = dedupWindow
+	}
+}
+
+// WithEventOutboxCompactThreshold sets after how many written records the log file is rewritten to only contain pending events
+// and the dedup window. The default is 10000.
+func WithEventOutboxCompactThreshold(compactThreshold int) EventOutboxConfigOpt {
+	return func(config *eventOutboxConfig) {
+		config.CompactThreshold = compactThreshold
+	}
+}
@@ -0,0 +1,166 @@
+package bot
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fluxergo/fluxergo/gateway"
+)
+
+func TestFileEventOutbox(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.log")
+
+	outbox, err := NewFileEventOutbox(path, WithEventOutboxSync(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for seq := 1; seq <= 3; seq++ {
+		appended, err := outbox.Append(OutboxEntry{SessionID: "a", Sequence: seq, EventType: gateway.EventTypeMessageCreate, Data: []byte(`{}`)})
+		if err != nil || !appended {
+			t.Fatalf("expected seq %d to be appended, got %t: %v", seq, appended, err)
+		}
+	}
+	if err = outbox.Ack("a", 2); err != nil {
+		t.Fatal(err)
+	}
+	if appended, _ := outbox.Append(OutboxEntry{SessionID: "a", Sequence: 2}); appended {
+		t.Error("expected acknowledged seq 2 to be a duplicate")
+	}
+	if err = outbox.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// simulate a crash in the middle of a write
+	_, _ = f.WriteString(`{"op":"append","session_id":"a","se`)
+	_ = f.Close()
+
+	outbox, err = NewFileEventOutbox(path, WithEventOutboxSync(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending := outbox.Pending()
+	if len(pending) != 2 || pending[0].Sequence != 1 || pending[1].Sequence != 3 {
+		t.Fatalf("expected seq 1 and 3 to be pending, got %+v", pending)
+	}
+	if string(pending[0].Data) != `{}` || pending[0].EventType != gateway.EventTypeMessageCreate {
+		t.Errorf("expected entry to be restored, got %+v", pending[0])
+	}
+	if appended, _ := outbox.Append(OutboxEntry{SessionID: "a", Sequence: 2}); appended {
+		t.Error("expected seq 2 to still be a duplicate after reopening")
+	}
+	if appended, _ := outbox.Append(OutboxEntry{SessionID: "b", Sequence: 2}); !appended {
+		t.Error("expected seq 2 of another session to be appended")
+	}
+	_ = outbox.Close()
+}
+
+func TestFileEventOutbox_Compact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.log")
+	outbox, err := NewFileEventOutbox(path, WithEventOutboxSync(false), WithEventOutboxCompactThreshold(10), WithEventOutboxDedupWindow(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for seq := range 50 {
+		if _, err = outbox.Append(OutboxEntry{SessionID: "a", Sequence: seq}); err != nil {
+			t.Fatal(err)
+		}
+		if seq%5 != 0 {
+			if err = outbox.Ack("a", seq); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	_ = outbox.Close()
+
+	outbox, err = NewFileEventOutbox(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer outbox.Close()
+	if pending := outbox.Pending(); len(pending) != 10 {
+		t.Errorf("expected 10 pending entries, got %d", len(pending))
+	}
+}
+
+type outboxTestGateway struct {
+	gateway.Gateway
+}
+
+func (outboxTestGateway) ShardID() int { return 0 }
+
+func (outboxTestGateway) SessionID() *string {
+	sessionID := "a"
+	return &sessionID
+}
+
+func TestOutboxEventHandlerFunc(t *testing.T) {
+	outbox, err := NewFileEventOutbox(filepath.Join(t.TempDir(), "outbox.log"), WithEventOutboxSync(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer outbox.Close()
+
+	var handled []gateway.EventType
+	handler := newOutboxEventHandlerFunc(slog.Default(), outbox, func(_ gateway.Gateway, eventType gateway.EventType, _ int, _ gateway.EventData) {
+		handled = append(handled, eventType)
+	})
+	raw := func(eventType gateway.EventType, seq int) {
+		handler(outboxTestGateway{}, gateway.EventTypeRaw, seq, gateway.EventRaw{EventType: eventType, Payload: strings.NewReader(`{}`)})
+	}
+
+	// unknown dispatches are only passed raw
+	raw("SOME_NEW_EVENT", 1)
+	raw(gateway.EventTypeTypingStart, 2)
+	handler(outboxTestGateway{}, gateway.EventTypeTypingStart, 2, gateway.EventTypingStart{})
+
+	if pending := outbox.Pending(); len(pending) != 0 {
+		t.Errorf("expected no pending dispatches, got %+v", pending)
+	}
+	if len(handled) != 3 || handled[2] != gateway.EventTypeTypingStart {
+		t.Errorf("unexpected handled events: %v", handled)
+	}
+
+	// the dispatch was already handled
+	raw(gateway.EventTypeTypingStart, 2)
+	handler(outboxTestGateway{}, gateway.EventTypeTypingStart, 2, gateway.EventTypingStart{})
+	if len(handled) != 4 {
+		t.Errorf("expected duplicate dispatch to be skipped, got %v", handled)
+	}
+}
+
+func TestReplayEventOutbox(t *testing.T) {
+	outbox, err := NewFileEventOutbox(filepath.Join(t.TempDir(), "outbox.log"), WithEventOutboxSync(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer outbox.Close()
+
+	if _, err = outbox.Append(OutboxEntry{SessionID: "a", Sequence: 1, ShardID: 3, EventType: gateway.EventTypeTypingStart, Data: []byte(`{}`)}); err != nil {
+		t.Fatal(err)
+	}
+
+	shardIDs := make([]int, 0, 1)
+	client := &Client{Logger: slog.Default()}
+	client.EventManager = NewEventManager(client, WithGatewayHandlers(map[gateway.EventType]GatewayEventHandler{
+		gateway.EventTypeTypingStart: NewGatewayEventHandler(gateway.EventTypeTypingStart, func(_ *Client, _ int, shardID int, _ gateway.EventTypingStart) {
+			shardIDs = append(shardIDs, shardID)
+		}),
+	}))
+
+	// the gateway is not open yet, so the shard has to come from the entry
+	replayEventOutbox(client, outbox)
+
+	if len(shardIDs) != 1 || shardIDs[0] != 3 {
+		t.Errorf("expected dispatch on shard 3, got %v", shardIDs)
+	}
+	if pending := outbox.Pending(); len(pending) != 0 {
+		t.Errorf("expected replayed dispatch to be acknowledged, got %+v", pending)
+	}
+}
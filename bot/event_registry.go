@@ -0,0 +1,155 @@
+package bot
+
+import (
+	"reflect"
+)
+
+// TypedEventListener is an EventListener which only handles events of a single type.
+// The EventManager only calls it for events of this type, events implementing it if it is an interface type,
+// or events embedding it, like events.GuildMessageCreate embeds events.GenericGuildMessage.
+// In the latter case the embedded value is passed to OnEvent.
+type TypedEventListener interface {
+	EventListener
+	EventType() reflect.Type
+}
+
+// Unsubscribe removes an EventListener added via EventManager.Subscribe again. It is safe to call multiple times.
+type Unsubscribe func()
+
+// SubscribeFunc adds the given func(e E) to the EventManager and returns an Unsubscribe to remove it again.
+func SubscribeFunc[E Event](eventManager EventManager, f func(e E)) Unsubscribe {
+	return eventManager.Subscribe(NewListenerFunc(f))
+}
+
+// SubscribeChan adds the given chan<- E to the EventManager and returns an Unsubscribe to remove it again.
+func SubscribeChan[E Event](eventManager EventManager, c chan<- E) Unsubscribe {
+	return eventManager.Subscribe(NewListenerChan(c))
+}
+
+func (l *listenerFunc[E]) EventType() reflect.Type {
+	return reflect.TypeFor[E]()
+}
+
+func (l *listenerChan[E]) EventType() reflect.Type {
+	return reflect.TypeFor[E]()
+}
+
+// registeredListener is an EventListener registered in the eventRegistry.
+type registeredListener struct {
+	id       uint64
+	original EventListener
+	// wrapped is the original EventListener wrapped with all EventMiddleware(s).
+	wrapped EventListener
+	// eventType is the type of events the EventListener handles or nil for all events.
+	eventType reflect.Type
+}
+
+// eventRegistry keeps registered listeners in order and caches which of them handle a concrete event type.
+// It is not safe for concurrent use. The slices returned by listeners are never modified and can be used without holding a lock.
+type eventRegistry struct {
+	nextID    uint64
+	listeners []registeredListener
+	resolved  map[reflect.Type][]EventListener
+}
+
+func (r *eventRegistry) add(original EventListener, wrapped EventListener) uint64 {
+	r.nextID++
+	var eventType reflect.Type
+	if typed, ok := original.(TypedEventListener); ok {
+		eventType = typed.EventType()
+	}
+	r.listeners = append(r.listeners, registeredListener{
+		id:        r.nextID,
+		original:  original,
+		wrapped:   wrapped,
+		eventType: eventType,
+	})
+	r.resolved = nil
+	return r.nextID
+}
+
+func (r *eventRegistry) remove(match func(l registeredListener) bool) bool {
+	for i, l := range r.listeners {
+		if match(l) {
+			r.listeners = append(r.listeners[:i], r.listeners[i+1:]...)
+			r.resolved = nil
+			return true
+		}
+	}
+	return false
+}
+
+func (r *eventRegistry) removeID(id uint64) bool {
+	return r.remove(func(l registeredListener) bool {
+		return l.id == id
+	})
+}
+
+// removeListener removes the first registration of the given EventListener.
+// Listeners with an incomparable type can only be removed via their Unsubscribe.
+func (r *eventRegistry) removeListener(listener EventListener) bool {
+	if !reflect.TypeOf(listener).Comparable() {
+		return false
+	}
+	return r.remove(func(l registeredListener) bool {
+		return reflect.TypeOf(l.original).Comparable() && l.original == listener
+	})
+}
+
+// listenersFor returns all listeners which handle events of the given type in the order they were added.
+func (r *eventRegistry) listenersFor(eventType reflect.Type) []EventListener {
+	if listeners, ok := r.resolved[eventType]; ok {
+		return listeners
+	}
+	var listeners []EventListener
+	for _, l := range r.listeners {
+		if l.eventType == nil || eventType.AssignableTo(l.eventType) {
+			listeners = append(listeners, l.wrapped)
+			continue
+		}
+		if index := embeddedFieldIndex(eventType, l.eventType); index != nil {
+			listeners = append(listeners, &embeddedEventListener{index: index, listener: l.wrapped})
+		}
+	}
+	if r.resolved == nil {
+		r.resolved = make(map[reflect.Type][]EventListener)
+	}
+	r.resolved[eventType] = listeners
+	return listeners
+}
+
+// embeddedFieldIndex returns the index of the field of type fieldType embedded in eventType or nil if there is none.
+func embeddedFieldIndex(eventType reflect.Type, fieldType reflect.Type) []int {
+	if eventType.Kind() == reflect.Pointer {
+		eventType = eventType.Elem()
+	}
+	if eventType.Kind() != reflect.Struct {
+		return nil
+	}
+	name := fieldType
+	if name.Kind() == reflect.Pointer {
+		name = name.Elem()
+	}
+	if name.Name() == "" {
+		return nil
+	}
+	field, ok := eventType.FieldByName(name.Name())
+	if !ok || !field.Anonymous || !field.IsExported() || field.Type != fieldType || !fieldType.Implements(reflect.TypeFor[Event]()) {
+		return nil
+	}
+	return field.Index
+}
+
+// embeddedEventListener passes the embedded event at index to the listener.
+type embeddedEventListener struct {
+	index    []int
+	listener EventListener
+}
+
+func (l *embeddedEventListener) OnEvent(event Event) {
+	field, err := reflect.Indirect(reflect.ValueOf(event)).FieldByIndexErr(l.index)
+	if err != nil || (field.Kind() == reflect.Pointer && field.IsNil()) {
+		return
+	}
+	l.listener.OnEvent(field.Interface().(Event))
+}
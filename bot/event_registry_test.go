@@ -0,0 +1,122 @@
+package bot
+
+import (
+	"strconv"
+	"testing"
+)
+
+type GenericTestMessage struct {
+	*testEvent
+	content string
+}
+
+type testMessageCreate struct {
+	*GenericTestMessage
+}
+
+type testMessageDelete struct {
+	*GenericTestMessage
+}
+
+type testOtherEvent struct {
+	*testEvent
+}
+
+type testMessageEvent interface {
+	Event
+	message() *GenericTestMessage
+}
+
+func (e *testMessageCreate) message() *GenericTestMessage { return e.GenericTestMessage }
+
+func TestEventManager_Subscribe(t *testing.T) {
+	m := NewEventManager(nil)
+
+	var (
+		creates  int
+		generics []string
+		messages int
+		all      int
+	)
+	unsubscribeCreate := SubscribeFunc(m, func(e *testMessageCreate) { creates++ })
+	SubscribeFunc(m, func(e *GenericTestMessage) { generics = append(generics, e.content) })
+	SubscribeFunc(m, func(e testMessageEvent) { messages++ })
+	unsubscribeAll := m.Subscribe(EventListenerFunc(func(Event) { all++ }))
+
+	m.DispatchEvent(&testMessageCreate{GenericTestMessage: &GenericTestMessage{testEvent: &testEvent{}, content: "create"}})
+	m.DispatchEvent(&testMessageDelete{GenericTestMessage: &GenericTestMessage{testEvent: &testEvent{}, content: "delete"}})
+	m.DispatchEvent(&testOtherEvent{testEvent: &testEvent{}})
+
+	if creates != 1 || messages != 1 || all != 3 {
+		t.Errorf("expected 1 create, 1 message and 3 events, got %d, %d and %d", creates, messages, all)
+	}
+	if len(generics) != 2 || generics[0] != "create" || generics[1] != "delete" {
+		t.Errorf("expected generic listener to receive embedded events, got %v", generics)
+	}
+
+	unsubscribeCreate()
+	unsubscribeCreate()
+	unsubscribeAll()
+	m.DispatchEvent(&testMessageCreate{GenericTestMessage: &GenericTestMessage{testEvent: &testEvent{}, content: "create"}})
+	if creates != 1 || all != 3 {
+		t.Errorf("expected unsubscribed listeners to not be called, got %d creates and %d events", creates, all)
+	}
+	if len(generics) != 3 {
+		t.Errorf("expected generic listener to still be called, got %v", generics)
+	}
+}
+
+func benchmarkDispatch(b *testing.B, wrap func(EventListener) EventListener) {
+	m := NewEventManager(nil)
+	var n int
+	for i := range 300 {
+		var listener EventListener
+		switch i % 3 {
+		case 0:
+			listener = NewListenerFunc(func(e *testMessageCreate) { n++ })
+		case 1:
+			listener = NewListenerFunc(func(e *testMessageDelete) { n++ })
+		default:
+			listener = NewListenerFunc(func(e *testOtherEvent) { n++ })
+		}
+		m.AddEventListeners(wrap(listener))
+	}
+	event := &testMessageCreate{GenericTestMessage: &GenericTestMessage{testEvent: &testEvent{}}}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		m.DispatchEvent(event)
+	}
+}
+
+// BenchmarkDispatch compares dispatching to 300 listeners of which 100 handle the event,
+// once with every listener being called and doing a type assertion, and once type-indexed.
+func BenchmarkDispatch(b *testing.B) {
+	b.Run("type-assertion", func(b *testing.B) {
+		benchmarkDispatch(b, func(l EventListener) EventListener {
+			// hide the event type, so the listener is called for every event
+			return EventListenerFunc(l.OnEvent)
+		})
+	})
+	b.Run("type-indexed", func(b *testing.B) {
+		benchmarkDispatch(b, func(l EventListener) EventListener { return l })
+	})
+}
+
+func BenchmarkDispatchListeners(b *testing.B) {
+	for _, listeners := range []int{10, 100, 1000} {
+		b.Run(strconv.Itoa(listeners), func(b *testing.B) {
+			m := NewEventManager(nil)
+			for range listeners {
+				m.AddEventListeners(NewListenerFunc(func(e *testOtherEvent) {}))
+			}
+			event := &testMessageCreate{GenericTestMessage: &GenericTestMessage{testEvent: &testEvent{}}}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				m.DispatchEvent(event)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/fluxergo/fluxergo/gateway"
+)
+
+var (
+	// ErrEventSinkClosed is returned by EventSink.Send after the EventSink was closed.
+	ErrEventSinkClosed = errors.New("event sink closed")
+	// ErrEventSourceClosed is returned by EventSource.Receive after the EventSource was closed.
+	ErrEventSourceClosed = errors.New("event source closed")
+	// ErrEventSinkQueueFull is passed to the EventSinkDropFunc if a dispatch was dropped because the EventSink did not keep up.
+	ErrEventSinkQueueFull = errors.New("event sink queue full")
+)
+
+// EventSinkDropFunc is called for every dispatch which could not be passed to the EventSink, see WithEventSinkDropFunc.
+type EventSinkDropFunc func(event RemoteEvent, err error)
+
+// RemoteEvent is a gateway dispatch forwarded from the process running the gateway.Gateway to a process running the EventListener(s).
+type RemoteEvent struct {
+	ShardID   int               `json:"shard_id"`
+	Sequence  int               `json:"seq"`
+	EventType gateway.EventType `json:"t"`
+	Data      json.RawMessage   `json:"d"`
+}
+
+// EventSink receives the dispatches of a gateway.Gateway and forwards them to an EventSource.
+//
+// Implementations must deliver the RemoteEvent(s) in the order Send was called and keep them until the EventSource acknowledged them,
+// so they can be redelivered after a connection loss. This means RemoteEvent(s) which were accepted by Send are delivered at least once.
+// Dispatches the EventSink does not accept in time are dropped and reported to the EventSinkDropFunc.
+type EventSink interface {
+	// Send queues the RemoteEvent for delivery. It blocks while too many RemoteEvent(s) are unacknowledged until the context is done.
+	Send(ctx context.Context, event RemoteEvent) error
+
+	// Close waits until all queued RemoteEvent(s) are acknowledged or the context is done and closes the EventSink.
+	Close(ctx context.Context) error
+}
+
+// EventSource receives the RemoteEvent(s) of one or more EventSink(s).
+type EventSource interface {
+	// Receive blocks until the next RemoteEvent is available or the context is done.
+	// The returned func acknowledges the RemoteEvent and has to be called once it was handled.
+	// RemoteEvent(s) of the same EventSink are returned in order.
+	Receive(ctx context.Context) (RemoteEvent, func(), error)
+
+	// Close stops receiving RemoteEvent(s). Unacknowledged RemoteEvent(s) are redelivered to the next EventSource.
+	Close(ctx context.Context) error
+}
+
+// newEventSinkForwarder returns a new started eventSinkForwarder.
+func newEventSinkForwarder(logger *slog.Logger, sink EventSink, sendTimeout time.Duration, bufferSize int, dropFunc EventSinkDropFunc) *eventSinkForwarder {
+	f := &eventSinkForwarder{
+		logger:      logger,
+		sink:        sink,
+		sendTimeout: sendTimeout,
+		dropFunc:    dropFunc,
+		queue:       make(chan RemoteEvent, bufferSize),
+		done:        make(chan struct{}),
+	}
+	go f.run()
+	return f
+}
+
+// eventSinkForwarder queues the dispatches of the gateway.Gateway and sends them to the EventSink,
+// so a slow or unreachable EventSink never blocks the gateway read loop.
+type eventSinkForwarder struct {
+	logger      *slog.Logger
+	sink        EventSink
+	sendTimeout time.Duration
+	dropFunc    EventSinkDropFunc
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan RemoteEvent
+	done   chan struct{}
+}
+
+// eventHandlerFunc returns a gateway.EventHandlerFunc which queues every raw dispatch for the EventSink before passing all events to next.
+func (f *eventSinkForwarder) eventHandlerFunc(next gateway.EventHandlerFunc) gateway.EventHandlerFunc {
+	return func(gw gateway.Gateway, eventType gateway.EventType, sequenceNumber int, event gateway.EventData) {
+		if eventType != gateway.EventTypeRaw {
+			next(gw, eventType, sequenceNumber, event)
+			return
+		}
+		raw := event.(gateway.EventRaw)
+		data, err := io.ReadAll(raw.Payload)
+		if err != nil {
+			f.logger.Error("failed to read raw dispatch", slog.Any("err", err))
+			return
+		}
+
+		f.enqueue(RemoteEvent{
+			ShardID:   gw.ShardID(),
+			Sequence:  sequenceNumber,
+			EventType: raw.EventType,
+			Data:      data,
+		})
+		next(gw, eventType, sequenceNumber, gateway.EventRaw{EventType: raw.EventType, Payload: bytes.NewReader(data)})
+	}
+}
+
+func (f *eventSinkForwarder) enqueue(event RemoteEvent) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.closed {
+		f.drop(event, ErrEventSinkClosed)
+		return
+	}
+	select {
+	case f.queue <- event:
+	default:
+		f.drop(event, ErrEventSinkQueueFull)
+	}
+}
+
+func (f *eventSinkForwarder) run() {
+	defer close(f.done)
+	for event := range f.queue {
+		if err := f.send(event); err != nil {
+			f.drop(event, err)
+		}
+	}
+}
+
+func (f *eventSinkForwarder) send(event RemoteEvent) error {
+	ctx := context.Background()
+	if f.sendTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.sendTimeout)
+		defer cancel()
+	}
+	return f.sink.Send(ctx, event)
+}
+
+func (f *eventSinkForwarder) drop(event RemoteEvent, err error) {
+	f.logger.Error("dropped dispatch for event sink", slog.Any("err", err), slog.Any("event_type", event.EventType), slog.Int("seq", event.Sequence))
+	if f.dropFunc != nil {
+		f.dropFunc(event, err)
+	}
+}
+
+// Close stops queueing dispatches and waits until the queued ones were passed to the EventSink or the context is done.
+func (f *eventSinkForwarder) Close(ctx context.Context) {
+	f.mu.Lock()
+	if !f.closed {
+		f.closed = true
+		close(f.queue)
+	}
+	f.mu.Unlock()
+
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+	}
+}
+
+const (
+	eventSourceRetryDelay    = 100 * time.Millisecond
+	eventSourceMaxRetryDelay = 10 * time.Second
+)
+
+// receiveRemoteEvents passes all RemoteEvent(s) of the EventSource to the EventManager and acknowledges them once HandleShardEvent returned.
+// With async events or an EventWorkerPool the listeners may still be running then, so RemoteEvent(s) are only delivered at least once with synchronous dispatch.
+// Failed receives are retried with an exponential backoff.
+func receiveRemoteEvents(client *Client, source EventSource, done chan<- struct{}) {
+	defer close(done)
+	ctx := client.Context()
+	delay := eventSourceRetryDelay
+	for {
+		remoteEvent, ack, err := source.Receive(ctx)
+		if err != nil {
+			if errors.Is(err, ErrEventSourceClosed) || ctx.Err() != nil {
+				return
+			}
+			client.Logger.Error("failed to receive event from event source", slog.Any("err", err), slog.Duration("retry_in", delay))
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return
+			}
+			delay = min(delay*2, eventSourceMaxRetryDelay)
+			continue
+		}
+		delay = eventSourceRetryDelay
+		event, err := gateway.UnmarshalEventData(remoteEvent.Data, remoteEvent.EventType)
+		if err != nil {
+			client.Logger.Error("failed to decode event from event source, dropping it", slog.Any("err", err), slog.Int("seq", remoteEvent.Sequence))
+		} else {
+			client.EventManager.HandleShardEvent(remoteEvent.ShardID, remoteEvent.EventType, remoteEvent.Sequence, event)
+		}
+		ack()
+	}
+}
@@ -0,0 +1,103 @@
+package bot
+
+import (
+	"log/slog"
+	"time"
+)
+
+func defaultNetEventSinkConfig() netEventSinkConfig {
+	return netEventSinkConfig{
+		Logger:            slog.Default(),
+		MaxPending:        1024,
+		DialTimeout:       5 * time.Second,
+		ReconnectDelay:    time.Second,
+		MaxReconnectDelay: 30 * time.Second,
+	}
+}
+
+type netEventSinkConfig struct {
+	Logger            *slog.Logger
+	MaxPending        int
+	DialTimeout       time.Duration
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+}
+
+// NetEventSinkConfigOpt is a functional option for configuring an EventSink created by NewNetEventSink.
+type NetEventSinkConfigOpt func(config *netEventSinkConfig)
+
+func (c *netEventSinkConfig) apply(opts []NetEventSinkConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.MaxPending <= 0 {
+		c.MaxPending = 1
+	}
+	c.Logger = c.Logger.With(slog.String("name", "bot_event_sink"))
+}
+
+// WithNetEventSinkLogger overrides the default Logger in the netEventSinkConfig.
+func WithNetEventSinkLogger(logger *slog.Logger) NetEventSinkConfigOpt {
+	return func(config *netEventSinkConfig) {
+		config.Logger = logger
+	}
+}
+
+// WithNetEventSinkMaxPending sets how many events can be unacknowledged before EventSink.Send blocks. The default is 1024.
+func WithNetEventSinkMaxPending(maxPending int) NetEventSinkConfigOpt {
+	return func(config *netEventSinkConfig) {
+		config.MaxPending = maxPending
+	}
+}
+
+// WithNetEventSinkDialTimeout sets the timeout for connecting to the EventSource. The default is 5s.
+func WithNetEventSinkDialTimeout(dialTimeout time.Duration) NetEventSinkConfigOpt {
+	return func(config *netEventSinkConfig) {
+		config.DialTimeout = dialTimeout
+	}
+}
+
+// WithNetEventSinkReconnectDelay sets the initial and maximum delay between reconnect attempts. The delay doubles after every failed attempt.
+// The defaults are 1s and 30s.
+func WithNetEventSinkReconnectDelay(delay time.Duration, maxDelay time.Duration) NetEventSinkConfigOpt {
+	return func(config *netEventSinkConfig) {
+		config.ReconnectDelay = delay
+		config.MaxReconnectDelay = maxDelay
+	}
+}
+
+func defaultNetEventSourceConfig() netEventSourceConfig {
+	return netEventSourceConfig{
+		Logger:     slog.Default(),
+		BufferSize: 64,
+	}
+}
+
+type netEventSourceConfig struct {
+	Logger     *slog.Logger
+	BufferSize int
+}
+
+// NetEventSourceConfigOpt is a functional option for configuring an EventSource created by NewNetEventSource.
+type NetEventSourceConfigOpt func(config *netEventSourceConfig)
+
+func (c *netEventSourceConfig) apply(opts []NetEventSourceConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.Logger = c.Logger.With(slog.String("name", "bot_event_source"))
+}
+
+// WithNetEventSourceLogger overrides the default Logger in the netEventSourceConfig.
+func WithNetEventSourceLogger(logger *slog.Logger) NetEventSourceConfigOpt {
+	return func(config *netEventSourceConfig) {
+		config.Logger = logger
+	}
+}
+
+// WithNetEventSourceBufferSize sets how many received events are buffered until they are returned by EventSource.Receive. The default is 64.
+func WithNetEventSourceBufferSize(bufferSize int) NetEventSourceConfigOpt {
+	return func(config *netEventSourceConfig) {
+		config.BufferSize = bufferSize
+	}
+}
@@ -0,0 +1,410 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/fluxergo/fluxergo/internal/insecurerandstr"
+)
+
+const (
+	remoteOpHello = "hello"
+	remoteOpEvent = "event"
+	remoteOpAck   = "ack"
+)
+
+// remoteFrame is a single JSON line sent between a netEventSink and a netEventSource.
+// The EventSink sends a hello frame with its ID followed by event frames with increasing IDs.
+// The EventSource answers with ack frames containing the ID of the last handled event.
+type remoteFrame struct {
+	Op     string       `json:"op"`
+	SinkID string       `json:"sink_id,omitempty"`
+	ID     uint64       `json:"id,omitempty"`
+	Event  *RemoteEvent `json:"event,omitempty"`
+}
+
+var _ EventSink = (*netEventSink)(nil)
+
+// NewNetEventSink returns a new EventSink which connects to a NewNetEventSource listening on the given network and address,
+// for example "unix" and "/tmp/bot.sock" or "tcp" and "localhost:9000".
+// It reconnects if the connection is lost and resends all unacknowledged events in order.
+func NewNetEventSink(network string, address string, opts ...NetEventSinkConfigOpt) EventSink {
+	cfg := defaultNetEventSinkConfig()
+	cfg.apply(opts)
+
+	s := &netEventSink{
+		config:  cfg,
+		network: network,
+		address: address,
+		sinkID:  insecurerandstr.RandStr(16),
+		space:   make(chan struct{}, cfg.MaxPending),
+		wake:    make(chan struct{}, 1),
+		closed:  make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+type netEventSink struct {
+	config  netEventSinkConfig
+	network string
+	address string
+	sinkID  string
+
+	// space limits the number of pending events
+	space chan struct{}
+	wake  chan struct{}
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending []remoteFrame
+	closing bool
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	done      chan struct{}
+}
+
+func (s *netEventSink) Send(ctx context.Context, event RemoteEvent) error {
+	select {
+	case s.space <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.closed:
+		return ErrEventSinkClosed
+	}
+
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		<-s.space
+		return ErrEventSinkClosed
+	}
+	s.nextID++
+	s.pending = append(s.pending, remoteFrame{Op: remoteOpEvent, ID: s.nextID, Event: &event})
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (s *netEventSink) Close(ctx context.Context) error {
+	s.mu.Lock()
+	s.closing = true
+	s.mu.Unlock()
+
+	var err error
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for err == nil && s.pendingCount() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			err = fmt.Errorf("closing event sink with %d unacknowledged events: %w", s.pendingCount(), ctx.Err())
+		}
+	}
+
+	s.closeOnce.Do(func() {
+		close(s.closed)
+	})
+	<-s.done
+	return err
+}
+
+func (s *netEventSink) pendingCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending)
+}
+
+func (s *netEventSink) run() {
+	defer close(s.done)
+	delay := s.config.ReconnectDelay
+	for {
+		conn, err := net.DialTimeout(s.network, s.address, s.config.DialTimeout)
+		if err != nil {
+			s.config.Logger.Warn("failed to connect to event source", slog.Any("err", err), slog.Duration("retry_in", delay))
+			select {
+			case <-time.After(delay):
+				delay = min(delay*2, s.config.MaxReconnectDelay)
+				continue
+			case <-s.closed:
+				return
+			}
+		}
+		delay = s.config.ReconnectDelay
+
+		s.config.Logger.Debug("connected to event source", slog.String("address", s.address))
+		err = s.serve(conn)
+		_ = conn.Close()
+		select {
+		case <-s.closed:
+			return
+		default:
+		}
+		s.config.Logger.Warn("lost connection to event source", slog.Any("err", err))
+	}
+}
+
+// serve sends all pending events over the connection until it fails or the EventSink is closed.
+func (s *netEventSink) serve(conn net.Conn) error {
+	encoder := json.NewEncoder(conn)
+	if err := encoder.Encode(remoteFrame{Op: remoteOpHello, SinkID: s.sinkID}); err != nil {
+		return err
+	}
+
+	readErr := make(chan error, 1)
+	go func() {
+		readErr <- s.readAcks(conn)
+	}()
+
+	var lastSent uint64
+	for {
+		s.mu.Lock()
+		var frames []remoteFrame
+		for _, frame := range s.pending {
+			if frame.ID > lastSent {
+				frames = append(frames, frame)
+			}
+		}
+		s.mu.Unlock()
+
+		for _, frame := range frames {
+			if err := encoder.Encode(frame); err != nil {
+				return err
+			}
+			lastSent = frame.ID
+		}
+
+		select {
+		case <-s.wake:
+		case err := <-readErr:
+			return err
+		case <-s.closed:
+			return nil
+		}
+	}
+}
+
+func (s *netEventSink) readAcks(conn net.Conn) error {
+	decoder := json.NewDecoder(conn)
+	for {
+		var frame remoteFrame
+		if err := decoder.Decode(&frame); err != nil {
+			return err
+		}
+		if frame.Op != remoteOpAck {
+			continue
+		}
+
+		s.mu.Lock()
+		var n int
+		for n < len(s.pending) && s.pending[n].ID <= frame.ID {
+			n++
+		}
+		s.pending = s.pending[n:]
+		s.mu.Unlock()
+
+		for range n {
+			<-s.space
+		}
+	}
+}
+
+var _ EventSource = (*netEventSource)(nil)
+
+// NewNetEventSource returns a new EventSource which accepts connections of NewNetEventSink(s) on the given net.Listener.
+// Events which were already received from an EventSink are not returned again, even if the EventSink resends them after a reconnect.
+// The events of one EventSink have to be acknowledged in order.
+func NewNetEventSource(listener net.Listener, opts ...NetEventSourceConfigOpt) EventSource {
+	cfg := defaultNetEventSourceConfig()
+	cfg.apply(opts)
+
+	s := &netEventSource{
+		config:     cfg,
+		listener:   listener,
+		deliveries: make(chan remoteDelivery, cfg.BufferSize),
+		sinks:      make(map[string]*remoteSinkState),
+		conns:      make(map[*netSourceConn]struct{}),
+		closed:     make(chan struct{}),
+	}
+	go s.accept()
+	return s
+}
+
+type remoteDelivery struct {
+	event RemoteEvent
+	ack   func()
+}
+
+// remoteSinkState tracks the delivered and acknowledged events of an EventSink across reconnects.
+type remoteSinkState struct {
+	delivered uint64
+	acked     uint64
+	conn      *netSourceConn
+}
+
+type netSourceConn struct {
+	conn    net.Conn
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func (c *netSourceConn) writeAck(id uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.encoder.Encode(remoteFrame{Op: remoteOpAck, ID: id})
+}
+
+type netEventSource struct {
+	config     netEventSourceConfig
+	listener   net.Listener
+	deliveries chan remoteDelivery
+
+	mu    sync.Mutex
+	sinks map[string]*remoteSinkState
+	conns map[*netSourceConn]struct{}
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func (s *netEventSource) Receive(ctx context.Context) (RemoteEvent, func(), error) {
+	select {
+	case delivery := <-s.deliveries:
+		return delivery.event, delivery.ack, nil
+	case <-ctx.Done():
+		return RemoteEvent{}, nil, ctx.Err()
+	case <-s.closed:
+		return RemoteEvent{}, nil, ErrEventSourceClosed
+	}
+}
+
+func (s *netEventSource) Close(_ context.Context) error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		err = s.listener.Close()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for conn := range s.conns {
+			_ = conn.conn.Close()
+		}
+	})
+	return err
+}
+
+func (s *netEventSource) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-s.closed:
+				return
+			default:
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			s.config.Logger.Error("failed to accept event sink connection", slog.Any("err", err))
+			continue
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *netEventSource) handle(conn net.Conn) {
+	sourceConn := &netSourceConn{conn: conn, encoder: json.NewEncoder(conn)}
+	s.mu.Lock()
+	s.conns[sourceConn] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, sourceConn)
+		s.mu.Unlock()
+		_ = conn.Close()
+	}()
+
+	decoder := json.NewDecoder(conn)
+	var hello remoteFrame
+	if err := decoder.Decode(&hello); err != nil || hello.Op != remoteOpHello || hello.SinkID == "" {
+		s.config.Logger.Error("received invalid hello from event sink", slog.Any("err", err))
+		return
+	}
+
+	s.mu.Lock()
+	state, ok := s.sinks[hello.SinkID]
+	if !ok {
+		state = &remoteSinkState{}
+		s.sinks[hello.SinkID] = state
+	}
+	state.conn = sourceConn
+	acked := state.acked
+	s.mu.Unlock()
+
+	// acknowledge events which were handled while the EventSink was disconnected
+	if acked > 0 {
+		if err := sourceConn.writeAck(acked); err != nil {
+			return
+		}
+	}
+
+	for {
+		var frame remoteFrame
+		if err := decoder.Decode(&frame); err != nil {
+			select {
+			case <-s.closed:
+			default:
+				s.config.Logger.Debug("event sink connection closed", slog.Any("err", err))
+			}
+			return
+		}
+		if frame.Op != remoteOpEvent || frame.Event == nil {
+			continue
+		}
+
+		s.mu.Lock()
+		if frame.ID <= state.delivered {
+			acked = state.acked
+			s.mu.Unlock()
+			if frame.ID <= acked {
+				_ = sourceConn.writeAck(acked)
+			}
+			continue
+		}
+		state.delivered = frame.ID
+		s.mu.Unlock()
+
+		id := frame.ID
+		select {
+		case s.deliveries <- remoteDelivery{event: *frame.Event, ack: func() { s.ack(state, id) }}:
+		case <-s.closed:
+			return
+		}
+	}
+}
+
+func (s *netEventSource) ack(state *remoteSinkState, id uint64) {
+	s.mu.Lock()
+	if id > state.acked {
+		state.acked = id
+	}
+	acked := state.acked
+	conn := state.conn
+	s.mu.Unlock()
+
+	if err := conn.writeAck(acked); err != nil {
+		s.config.Logger.Debug("failed to acknowledge event, it is acknowledged after the event sink reconnected", slog.Any("err", err))
+	}
+}
@@ -0,0 +1,153 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fluxergo/fluxergo/gateway"
+)
+
+func TestNetEventSinkSource(t *testing.T) {
+	address := filepath.Join(t.TempDir(), "events.sock")
+	listener, err := net.Listen("unix", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := NewNetEventSource(listener)
+	sink := NewNetEventSink("unix", address, WithNetEventSinkReconnectDelay(10*time.Millisecond, 10*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for i := range 10 {
+		if err = sink.Send(ctx, RemoteEvent{ShardID: 1, Sequence: i, EventType: gateway.EventTypeMessageCreate, Data: []byte(`{}`)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// acknowledge the first 5 events and restart the source to test redelivery of the rest
+	for i := range 5 {
+		event, ack, err := source.Receive(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event.Sequence != i || event.ShardID != 1 {
+			t.Fatalf("expected sequence %d of shard 1, got %d of shard %d", i, event.Sequence, event.ShardID)
+		}
+		ack()
+	}
+	// wait for the acks to arrive, otherwise the events are redelivered as well
+	for sink.(*netEventSink).pendingCount() > 5 {
+		time.Sleep(time.Millisecond)
+	}
+	_ = source.Close(ctx)
+
+	listener, err = net.Listen("unix", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source = NewNetEventSource(listener)
+	defer source.Close(ctx)
+
+	for i := 5; i < 10; i++ {
+		event, ack, err := source.Receive(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event.Sequence != i {
+			t.Fatalf("expected sequence %d, got %d", i, event.Sequence)
+		}
+		ack()
+	}
+
+	if err = sink.Close(ctx); err != nil {
+		t.Fatalf("expected all events to be acknowledged, got %v", err)
+	}
+	if err = sink.Send(ctx, RemoteEvent{}); err != ErrEventSinkClosed {
+		t.Fatalf("expected ErrEventSinkClosed, got %v", err)
+	}
+}
+
+// blockingEventSink blocks every Send until its context is done.
+type blockingEventSink struct{}
+
+func (blockingEventSink) Send(ctx context.Context, _ RemoteEvent) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (blockingEventSink) Close(context.Context) error { return nil }
+
+func TestEventSinkForwarder(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		dropped = map[int]error{}
+	)
+	forwarder := newEventSinkForwarder(slog.Default(), blockingEventSink{}, 20*time.Millisecond, 1, func(event RemoteEvent, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		dropped[event.Sequence] = err
+	})
+
+	var handled int
+	handler := forwarder.eventHandlerFunc(func(gateway.Gateway, gateway.EventType, int, gateway.EventData) {
+		handled++
+	})
+	start := time.Now()
+	for seq := range 3 {
+		handler(outboxTestGateway{}, gateway.EventTypeRaw, seq, gateway.EventRaw{EventType: gateway.EventTypeTypingStart, Payload: strings.NewReader(`{}`)})
+	}
+	if took := time.Since(start); took >= 20*time.Millisecond || handled != 3 {
+		t.Fatalf("expected the gateway to not be blocked by the event sink, took %s", took)
+	}
+
+	forwarder.Close(context.Background())
+	mu.Lock()
+	defer mu.Unlock()
+	if len(dropped) != 3 || !errors.Is(dropped[2], ErrEventSinkQueueFull) && !errors.Is(dropped[1], ErrEventSinkQueueFull) {
+		t.Errorf("expected all dispatches to be dropped and one because of the full queue, got %v", dropped)
+	}
+	for seq, err := range dropped {
+		if !errors.Is(err, ErrEventSinkQueueFull) && !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("unexpected error for seq %d: %v", seq, err)
+		}
+	}
+}
+
+type failingEventSource struct {
+	calls chan struct{}
+}
+
+func (s failingEventSource) Receive(context.Context) (RemoteEvent, func(), error) {
+	s.calls <- struct{}{}
+	return RemoteEvent{}, nil, errors.New("connection reset")
+}
+
+func (failingEventSource) Close(context.Context) error { return nil }
+
+func TestReceiveRemoteEvents_Backoff(t *testing.T) {
+	client := &Client{Logger: slog.New(slog.DiscardHandler)}
+	client.ctx, client.cancel = context.WithCancel(context.Background())
+	source := failingEventSource{calls: make(chan struct{}, 16)}
+
+	done := make(chan struct{})
+	go receiveRemoteEvents(client, source, done)
+
+	time.Sleep(eventSourceRetryDelay / 2)
+	client.cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected receiving to stop once the context is done")
+	}
+	if calls := len(source.calls); calls != 1 {
+		t.Errorf("expected failed receive to be retried after a delay, got %d calls", calls)
+	}
+}
@@ -0,0 +1,253 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+var _ EventWorkerPool = (*eventWorkerPoolImpl)(nil)
+
+// NewEventWorkerPool returns a new EventWorkerPool with the EventWorkerPoolConfigOpt(s) applied.
+func NewEventWorkerPool(opts ...EventWorkerPoolConfigOpt) EventWorkerPool {
+	cfg := defaultEventWorkerPoolConfig()
+	cfg.apply(opts)
+
+	p := &eventWorkerPoolImpl{
+		config:  cfg,
+		queues:  make([]chan poolTask, cfg.Workers),
+		closing: make(chan struct{}),
+		drain:   make(chan struct{}),
+	}
+	for i := range p.queues {
+		p.queues[i] = make(chan poolTask, cfg.QueueSize)
+		p.wg.Add(1)
+		go p.work(p.queues[i])
+	}
+	return p
+}
+
+// EventWorkerPool dispatches events to a fixed number of workers with bounded queues.
+// Events with the same key (see EventKeyFunc) are always handled by the same worker and therefore in the order they were dispatched.
+type EventWorkerPool interface {
+	// Dispatch queues the Event to be passed to the given EventListener(s).
+	// Panics are not recovered, the default EventManager wraps its EventListener(s) with NewRecoverMiddleware.
+	// Depending on the configured enqueue timeout this blocks until there is space in the queue or drops the Event.
+	Dispatch(event Event, listeners []EventListener)
+
+	// Stats returns the current EventWorkerPoolStats.
+	Stats() EventWorkerPoolStats
+
+	// Close stops accepting new events and waits until all queued events are handled or the context is done.
+	// Dispatch calls which are blocked on a full queue drop their Event.
+	Close(ctx context.Context)
+}
+
+// EventWorkerPoolStats contains metrics about an EventWorkerPool.
+type EventWorkerPoolStats struct {
+	// Dispatched is the number of events which got queued.
+	Dispatched uint64 `json:"dispatched"`
+	// Dropped is the number of events which got dropped because their queue was full or the pool was closed.
+	Dropped uint64 `json:"dropped"`
+	// SlowListeners is the number of listener calls which took longer than the slow listener threshold.
+	SlowListeners uint64 `json:"slow_listeners"`
+	// QueueLengths contains the number of queued events per worker.
+	QueueLengths []int `json:"queue_lengths"`
+}
+
+// EventKeyFunc returns the key of an Event. Events with the same key are handled in order.
+// If false is returned the Event has no key and is handled by any worker.
+type EventKeyFunc func(event Event) (snowflake.ID, bool)
+
+// eventKeyFields are the fields DefaultEventKey looks for, in order of preference.
+var eventKeyFields = []string{"GuildID", "ChannelID", "UserID"}
+
+// DefaultEventKey returns the guild ID, channel ID or user ID of the Event, whichever is found first.
+// The fields are looked up by name including embedded structs.
+func DefaultEventKey(event Event) (snowflake.ID, bool) {
+	for _, name := range eventKeyFields {
+		if id, ok := eventSnowflakeField(event, name); ok {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+type poolTask struct {
+	event     Event
+	listeners []EventListener
+}
+
+type eventWorkerPoolImpl struct {
+	config eventWorkerPoolConfig
+
+	// mu guards closed, so no Dispatch starts sending after Close.
+	mu     sync.RWMutex
+	closed bool
+	// closing is closed by Close to release blocked senders.
+	closing chan struct{}
+	// sending tracks the Dispatch calls which are sending to a queue.
+	sending sync.WaitGroup
+	// drain is closed once no Dispatch is sending anymore, so the workers can handle the remaining events and stop.
+	drain  chan struct{}
+	queues []chan poolTask
+	wg     sync.WaitGroup
+
+	next          atomic.Uint64
+	dispatched    atomic.Uint64
+	dropped       atomic.Uint64
+	slowListeners atomic.Uint64
+}
+
+func (p *eventWorkerPoolImpl) Dispatch(event Event, listeners []EventListener) {
+	if len(listeners) == 0 {
+		return
+	}
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		p.drop(event)
+		return
+	}
+	p.sending.Add(1)
+	p.mu.RUnlock()
+	defer p.sending.Done()
+
+	queue := p.queues[p.queueIndex(event)]
+	task := poolTask{event: event, listeners: listeners}
+
+	switch {
+	case p.config.EnqueueTimeout < 0:
+		select {
+		case queue <- task:
+		default:
+			p.drop(event)
+			return
+		}
+	case p.config.EnqueueTimeout > 0:
+		select {
+		case queue <- task:
+		default:
+			timer := time.NewTimer(p.config.EnqueueTimeout)
+			defer timer.Stop()
+			select {
+			case queue <- task:
+			case <-timer.C:
+				p.drop(event)
+				return
+			case <-p.closing:
+				p.drop(event)
+				return
+			}
+		}
+	default:
+		select {
+		case queue <- task:
+		case <-p.closing:
+			p.drop(event)
+			return
+		}
+	}
+	p.dispatched.Add(1)
+}
+
+func (p *eventWorkerPoolImpl) queueIndex(event Event) int {
+	n := uint64(len(p.queues))
+	if p.config.KeyFunc != nil {
+		if key, ok := p.config.KeyFunc(event); ok {
+			return int((uint64(key) * 0x9E3779B97F4A7C15 >> 32) % n)
+		}
+	}
+	return int(p.next.Add(1) % n)
+}
+
+func (p *eventWorkerPoolImpl) drop(event Event) {
+	p.dropped.Add(1)
+	p.config.Logger.Warn("dropped event", slog.String("event", reflect.TypeOf(event).String()))
+	if p.config.OnDrop != nil {
+		p.config.OnDrop(event)
+	}
+}
+
+func (p *eventWorkerPoolImpl) work(queue <-chan poolTask) {
+	defer p.wg.Done()
+	for {
+		select {
+		case task := <-queue:
+			p.handleTask(task)
+		case <-p.drain:
+			for {
+				select {
+				case task := <-queue:
+					p.handleTask(task)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *eventWorkerPoolImpl) handleTask(task poolTask) {
+	for _, listener := range task.listeners {
+		p.handle(task.event, listener)
+	}
+}
+
+func (p *eventWorkerPoolImpl) handle(event Event, listener EventListener) {
+	start := time.Now()
+	defer func() {
+		took := time.Since(start)
+		if p.config.SlowListenerThreshold <= 0 || took < p.config.SlowListenerThreshold {
+			return
+		}
+		p.slowListeners.Add(1)
+		p.config.Logger.Warn("slow event listener", slog.String("event", reflect.TypeOf(event).String()), slog.Duration("took", took))
+		if p.config.OnSlowListener != nil {
+			p.config.OnSlowListener(event, listener, took)
+		}
+	}()
+	listener.OnEvent(event)
+}
+
+func (p *eventWorkerPoolImpl) Stats() EventWorkerPoolStats {
+	queueLengths := make([]int, len(p.queues))
+	for i, queue := range p.queues {
+		queueLengths[i] = len(queue)
+	}
+	return EventWorkerPoolStats{
+		Dispatched:    p.dispatched.Load(),
+		Dropped:       p.dropped.Load(),
+		SlowListeners: p.slowListeners.Load(),
+		QueueLengths:  queueLengths,
+	}
+}
+
+func (p *eventWorkerPoolImpl) Close(ctx context.Context) {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.closing)
+		go func() {
+			p.sending.Wait()
+			close(p.drain)
+		}()
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		p.config.Logger.Warn("event worker pool did not drain before the context was done", slog.Any("err", ctx.Err()))
+	}
+}
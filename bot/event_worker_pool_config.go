@@ -0,0 +1,103 @@
+package bot
+
+import (
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+func defaultEventWorkerPoolConfig() eventWorkerPoolConfig {
+	return eventWorkerPoolConfig{
+		Logger:                slog.Default(),
+		Workers:               runtime.NumCPU(),
+		QueueSize:             256,
+		SlowListenerThreshold: 5 * time.Second,
+		KeyFunc:               DefaultEventKey,
+	}
+}
+
+type eventWorkerPoolConfig struct {
+	Logger                *slog.Logger
+	Workers               int
+	QueueSize             int
+	EnqueueTimeout        time.Duration
+	SlowListenerThreshold time.Duration
+	KeyFunc               EventKeyFunc
+	OnDrop                func(event Event)
+	OnSlowListener        func(event Event, listener EventListener, took time.Duration)
+}
+
+// EventWorkerPoolConfigOpt is a functional option for configuring an EventWorkerPool.
+type EventWorkerPoolConfigOpt func(config *eventWorkerPoolConfig)
+
+func (c *eventWorkerPoolConfig) apply(opts []EventWorkerPoolConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.Workers < 1 {
+		c.Workers = 1
+	}
+	if c.QueueSize < 1 {
+		c.QueueSize = 1
+	}
+	c.Logger = c.Logger.With(slog.String("name", "bot_event_worker_pool"))
+}
+
+// WithWorkerPoolLogger overrides the default Logger in the eventWorkerPoolConfig.
+func WithWorkerPoolLogger(logger *slog.Logger) EventWorkerPoolConfigOpt {
+	return func(config *eventWorkerPoolConfig) {
+		config.Logger = logger
+	}
+}
+
+// WithWorkerPoolWorkers sets the number of workers. Each worker processes its events one after another.
+// The default is runtime.NumCPU.
+func WithWorkerPoolWorkers(workers int) EventWorkerPoolConfigOpt {
+	return func(config *eventWorkerPoolConfig) {
+		config.Workers = workers
+	}
+}
+
+// WithWorkerPoolQueueSize sets the number of events which can be queued per worker. The default is 256.
+func WithWorkerPoolQueueSize(queueSize int) EventWorkerPoolConfigOpt {
+	return func(config *eventWorkerPoolConfig) {
+		config.QueueSize = queueSize
+	}
+}
+
+// WithWorkerPoolEnqueueTimeout sets how long to wait for space in a full queue before the event is dropped.
+// 0 waits until there is space, which applies backpressure to the gateway. A negative timeout drops events immediately if the queue is full.
+func WithWorkerPoolEnqueueTimeout(timeout time.Duration) EventWorkerPoolConfigOpt {
+	return func(config *eventWorkerPoolConfig) {
+		config.EnqueueTimeout = timeout
+	}
+}
+
+// WithWorkerPoolSlowListenerThreshold sets after which duration a listener is reported as slow. 0 disables slow listener reporting.
+// The default is 5 seconds.
+func WithWorkerPoolSlowListenerThreshold(threshold time.Duration) EventWorkerPoolConfigOpt {
+	return func(config *eventWorkerPoolConfig) {
+		config.SlowListenerThreshold = threshold
+	}
+}
+
+// WithWorkerPoolKeyFunc sets the EventKeyFunc used to decide which events need to be processed in order. The default is DefaultEventKey.
+func WithWorkerPoolKeyFunc(keyFunc EventKeyFunc) EventWorkerPoolConfigOpt {
+	return func(config *eventWorkerPoolConfig) {
+		config.KeyFunc = keyFunc
+	}
+}
+
+// WithWorkerPoolOnDrop sets a function which is called for every event dropped because its queue was full.
+func WithWorkerPoolOnDrop(onDrop func(event Event)) EventWorkerPoolConfigOpt {
+	return func(config *eventWorkerPoolConfig) {
+		config.OnDrop = onDrop
+	}
+}
+
+// WithWorkerPoolOnSlowListener sets a function which is called for every listener which took longer than the slow listener threshold.
+func WithWorkerPoolOnSlowListener(onSlowListener func(event Event, listener EventListener, took time.Duration)) EventWorkerPoolConfigOpt {
+	return func(config *eventWorkerPoolConfig) {
+		config.OnSlowListener = onSlowListener
+	}
+}
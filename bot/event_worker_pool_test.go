@@ -0,0 +1,126 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+type testEvent struct {
+	GuildID snowflake.ID
+	n       int
+}
+
+func (e *testEvent) Client() *Client          { return nil }
+func (e *testEvent) SequenceNumber() int      { return e.n }
+func (e *testEvent) Context() context.Context { return context.Background() }
+
+func TestDefaultEventKey(t *testing.T) {
+	if key, ok := DefaultEventKey(&testEvent{GuildID: 5}); !ok || key != 5 {
+		t.Errorf("expected key 5, got %d (%t)", key, ok)
+	}
+	if _, ok := DefaultEventKey(&testEvent{}); ok {
+		t.Error("expected no key for zero guild id")
+	}
+}
+
+func TestEventWorkerPool_Order(t *testing.T) {
+	pool := NewEventWorkerPool(WithWorkerPoolWorkers(4))
+
+	var (
+		mu   sync.Mutex
+		seen = map[snowflake.ID][]int{}
+	)
+	listener := NewListenerFunc(func(e *testEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen[e.GuildID] = append(seen[e.GuildID], e.n)
+	})
+
+	for i := range 1000 {
+		pool.Dispatch(&testEvent{GuildID: snowflake.ID(i%7 + 1), n: i}, []EventListener{listener})
+	}
+	pool.Close(context.Background())
+
+	for guildID, ns := range seen {
+		for i := 1; i < len(ns); i++ {
+			if ns[i] < ns[i-1] {
+				t.Fatalf("events of guild %d handled out of order: %v", guildID, ns)
+			}
+		}
+	}
+	if stats := pool.Stats(); stats.Dispatched != 1000 || stats.Dropped != 0 {
+		t.Errorf("expected 1000 dispatched and 0 dropped events, got %+v", stats)
+	}
+}
+
+func TestEventWorkerPool_Drop(t *testing.T) {
+	var dropped int
+	pool := NewEventWorkerPool(
+		WithWorkerPoolWorkers(1),
+		WithWorkerPoolQueueSize(1),
+		WithWorkerPoolEnqueueTimeout(-1),
+		WithWorkerPoolOnDrop(func(Event) { dropped++ }),
+	)
+
+	block := make(chan struct{})
+	listener := NewListenerFunc(func(*testEvent) { <-block })
+	for i := range 5 {
+		pool.Dispatch(&testEvent{GuildID: 1, n: i}, []EventListener{listener})
+	}
+	close(block)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	pool.Close(ctx)
+
+	// one event is handled by the worker, one is queued
+	if stats := pool.Stats(); stats.Dropped < 3 || int(stats.Dropped) != dropped {
+		t.Errorf("expected at least 3 dropped events, got %+v (callback %d)", stats, dropped)
+	}
+}
+
+func TestEventWorkerPool_CloseFullQueue(t *testing.T) {
+	pool := NewEventWorkerPool(
+		WithWorkerPoolWorkers(1),
+		WithWorkerPoolQueueSize(1),
+	)
+
+	block := make(chan struct{})
+	defer close(block)
+	started := make(chan struct{}, 1)
+	listener := NewListenerFunc(func(*testEvent) {
+		started <- struct{}{}
+		<-block
+	})
+	pool.Dispatch(&testEvent{n: 0}, []EventListener{listener})
+	<-started
+	pool.Dispatch(&testEvent{n: 1}, []EventListener{listener})
+
+	// the queue is full, so this blocks until the pool is closed
+	dispatched := make(chan struct{})
+	go func() {
+		pool.Dispatch(&testEvent{n: 2}, []EventListener{listener})
+		close(dispatched)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	pool.Close(ctx)
+	if took := time.Since(start); took > time.Second {
+		t.Fatalf("expected Close to return after the context deadline, took %s", took)
+	}
+
+	select {
+	case <-dispatched:
+	case <-time.After(time.Second):
+		t.Fatal("expected blocked Dispatch to be released by Close")
+	}
+	if stats := pool.Stats(); stats.Dropped != 1 {
+		t.Errorf("expected 1 dropped event, got %+v", stats)
+	}
+}
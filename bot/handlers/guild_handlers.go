@@ -4,7 +4,10 @@ import (
 	"context"
 	"log/slog"
 
+	"github.com/disgoorg/snowflake/v2"
+
 	"github.com/fluxergo/fluxergo/bot"
+	"github.com/fluxergo/fluxergo/cache"
 	"github.com/fluxergo/fluxergo/events"
 	"github.com/fluxergo/fluxergo/fluxer"
 	"github.com/fluxergo/fluxergo/gateway"
@@ -14,6 +17,11 @@ func gatewayHandlerGuildCreate(client *bot.Client, sequenceNumber int, shardID i
 	wasUnready := client.Caches.IsGuildUnready(event.ID)
 	wasUnavailable := client.Caches.IsGuildUnavailable(event.ID)
 
+	var reconciled cache.ReconcileResult
+	if (wasUnready || wasUnavailable) && client.Caches.ReconcileMode().Evict() {
+		reconciled = client.Caches.ReconcileGuild(event.GatewayGuild)
+	}
+
 	client.Caches.AddGuild(event.Guild)
 
 	for _, channel := range event.Channels {
@@ -55,6 +63,14 @@ func gatewayHandlerGuildCreate(client *bot.Client, sequenceNumber int, shardID i
 		client.Caches.AddPresence(presence)
 	}
 
+	if client.Caches.CacheFlags().Has(cache.FlagInvites) {
+		fetchGuildInvites(client, event.ID)
+	}
+
+	if client.Caches.ReconcileMode().Dispatch() && !reconciled.Empty() {
+		dispatchReconciledEvents(client, sequenceNumber, shardID, event.ID, reconciled)
+	}
+
 	genericGuildEvent := &events.GenericGuild{
 		GenericEvent: events.NewGenericEvent(client, sequenceNumber, shardID),
 		GuildID:      event.ID,
@@ -95,6 +111,77 @@ func gatewayHandlerGuildCreate(client *bot.Client, sequenceNumber int, shardID i
 	}
 }
 
+// dispatchReconciledEvents dispatches synthetic delete events for all entities which got evicted while reconciling the cache.
+func dispatchReconciledEvents(client *bot.Client, sequenceNumber int, shardID int, guildID snowflake.ID, reconciled cache.ReconcileResult) {
+	for _, channel := range reconciled.Channels {
+		client.EventManager.DispatchEvent(&events.GuildChannelDelete{
+			GenericGuildChannel: &events.GenericGuildChannel{
+				GenericEvent: events.NewGenericEvent(client, sequenceNumber, shardID),
+				ChannelID:    channel.ID(),
+				Channel:      channel,
+				GuildID:      guildID,
+			},
+		})
+	}
+
+	for _, threadMember := range reconciled.ThreadMembers {
+		client.EventManager.DispatchEvent(&events.ThreadMemberRemove{
+			GenericThreadMember: &events.GenericThreadMember{
+				GenericEvent: events.NewGenericEvent(client, sequenceNumber, shardID),
+				GuildID:      guildID,
+				ThreadID:     threadMember.ThreadID,
+				UserID:       threadMember.UserID,
+				ThreadMember: threadMember,
+			},
+		})
+	}
+
+	for _, role := range reconciled.Roles {
+		client.EventManager.DispatchEvent(&events.RoleDelete{
+			GenericRole: &events.GenericRole{
+				GenericEvent: events.NewGenericEvent(client, sequenceNumber, shardID),
+				GuildID:      guildID,
+				RoleID:       role.ID,
+				Role:         role,
+			},
+		})
+	}
+
+	for _, emoji := range reconciled.Emojis {
+		client.EventManager.DispatchEvent(&events.EmojiDelete{
+			GenericEmoji: &events.GenericEmoji{
+				GenericEvent: events.NewGenericEvent(client, sequenceNumber, shardID),
+				GuildID:      guildID,
+				Emoji:        emoji,
+			},
+		})
+	}
+
+	for _, sticker := range reconciled.Stickers {
+		client.EventManager.DispatchEvent(&events.StickerDelete{
+			GenericSticker: &events.GenericSticker{
+				GenericEvent: events.NewGenericEvent(client, sequenceNumber, shardID),
+				GuildID:      guildID,
+				Sticker:      sticker,
+			},
+		})
+	}
+
+	for _, oldVoiceState := range reconciled.VoiceStates {
+		voiceState := oldVoiceState
+		voiceState.ChannelID = nil
+		member, _ := client.Caches.Member(guildID, oldVoiceState.UserID)
+		client.EventManager.DispatchEvent(&events.GuildVoiceLeave{
+			GenericGuildVoiceState: &events.GenericGuildVoiceState{
+				GenericEvent: events.NewGenericEvent(client, sequenceNumber, shardID),
+				VoiceState:   voiceState,
+				Member:       member,
+			},
+			OldVoiceState: oldVoiceState,
+		})
+	}
+}
+
 func gatewayHandlerGuildUpdate(client *bot.Client, sequenceNumber int, shardID int, event gateway.EventGuildUpdate) {
 	oldGuild, _ := client.Caches.Guild(event.ID)
 	client.Caches.AddGuild(event.Guild)
@@ -115,15 +202,20 @@ func gatewayHandlerGuildDelete(client *bot.Client, sequenceNumber int, shardID i
 	}
 
 	guild, _ := client.Caches.RemoveGuild(event.ID)
-	client.Caches.RemoveVoiceStatesByGuildID(event.ID)
-	client.Caches.RemovePresencesByGuildID(event.ID)
-	client.Caches.RemoveChannelsByGuildID(event.ID)
-	client.Caches.RemoveEmojisByGuildID(event.ID)
-	client.Caches.RemoveStickersByGuildID(event.ID)
-	client.Caches.RemoveRolesByGuildID(event.ID)
-	client.Caches.RemoveMembersByGuildID(event.ID)
-	client.Caches.RemoveGuildScheduledEventsByGuildID(event.ID)
-	client.Caches.RemoveMessagesByGuildID(event.ID)
+	// when reconciling we keep the entities of unavailable guilds around, so they can be diffed once the guild is available again
+	if !event.Unavailable || !client.Caches.ReconcileMode().Evict() {
+		client.Caches.RemoveVoiceStatesByGuildID(event.ID)
+		client.Caches.RemovePresencesByGuildID(event.ID)
+		client.Caches.RemoveChannelsByGuildID(event.ID)
+		client.Caches.RemoveEmojisByGuildID(event.ID)
+		client.Caches.RemoveStickersByGuildID(event.ID)
+		client.Caches.RemoveRolesByGuildID(event.ID)
+		client.Caches.RemoveMembersByGuildID(event.ID)
+		client.Caches.RemoveThreadMembersByGuildID(event.ID)
+		client.Caches.RemoveGuildScheduledEventsByGuildID(event.ID)
+		client.Caches.RemoveMessagesByGuildID(event.ID)
+		client.Caches.RemoveInvitesByGuildID(event.ID)
+	}
 
 	genericGuildEvent := &events.GenericGuild{
 		GenericEvent: events.NewGenericEvent(client, sequenceNumber, shardID),
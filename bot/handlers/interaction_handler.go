@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"github.com/fluxergo/fluxergo/bot"
+	"github.com/fluxergo/fluxergo/events"
+	"github.com/fluxergo/fluxergo/gateway"
+)
+
+func gatewayHandlerInteractionCreate(client *bot.Client, sequenceNumber int, shardID int, event gateway.EventInteractionCreate) {
+	client.EventManager.DispatchEvent(&events.InteractionCreate{
+		GenericEvent: events.NewGenericEvent(client, sequenceNumber, shardID),
+		Interaction:  event.Interaction,
+	})
+}
@@ -1,12 +1,23 @@
 package handlers
 
 import (
+	"log/slog"
+	"sync"
+
+	"github.com/disgoorg/snowflake/v2"
+
 	"github.com/fluxergo/fluxergo/bot"
 	"github.com/fluxergo/fluxergo/events"
+	"github.com/fluxergo/fluxergo/fluxer"
 	"github.com/fluxergo/fluxergo/gateway"
+	"github.com/fluxergo/fluxergo/rest"
 )
 
 func gatewayHandlerInviteCreate(client *bot.Client, sequenceNumber int, shardID int, event gateway.EventInviteCreate) {
+	if event.GuildID != nil {
+		client.Caches.AddInvite(*event.GuildID, extendedInviteFromEvent(event))
+	}
+
 	client.EventManager.DispatchEvent(&events.InviteCreate{
 		GenericEvent:      events.NewGenericEvent(client, sequenceNumber, shardID),
 		EventInviteCreate: event,
@@ -14,6 +25,10 @@ func gatewayHandlerInviteCreate(client *bot.Client, sequenceNumber int, shardID
 }
 
 func gatewayHandlerInviteDelete(client *bot.Client, sequenceNumber int, shardID int, event gateway.EventInviteDelete) {
+	if event.GuildID != nil {
+		client.Caches.RemoveInvite(*event.GuildID, event.Code)
+	}
+
 	client.EventManager.DispatchEvent(&events.InviteDelete{
 		GenericEvent: events.NewGenericEvent(client, sequenceNumber, shardID),
 		GuildID:      event.GuildID,
@@ -21,3 +36,76 @@ func gatewayHandlerInviteDelete(client *bot.Client, sequenceNumber int, shardID
 		Code:         event.Code,
 	})
 }
+
+func extendedInviteFromEvent(event gateway.EventInviteCreate) fluxer.ExtendedInvite {
+	return fluxer.ExtendedInvite{
+		Invite: fluxer.Invite{
+			Type:       fluxer.InviteTypeGuild,
+			Code:       event.Code,
+			Channel:    &fluxer.InviteChannel{ID: event.ChannelID},
+			Inviter:    event.Inviter,
+			TargetUser: event.TargetUser,
+			TargetType: event.TargetType,
+			ExpiresAt:  event.ExpiresAt,
+		},
+		Uses:      event.Uses,
+		MaxUses:   event.MaxUses,
+		MaxAge:    event.MaxAge,
+		Temporary: event.Temporary,
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+// inviteFetchWorkers is the maximum number of guild invites fetched at the same time per client.
+const inviteFetchWorkers = 2
+
+// inviteFetchers holds the inviteFetcher of each bot.Client.
+var inviteFetchers sync.Map
+
+// inviteFetcher fetches the invites of queued guilds with at most inviteFetchWorkers concurrent requests.
+type inviteFetcher struct {
+	mu      sync.Mutex
+	queue   []snowflake.ID
+	workers int
+}
+
+// fetchGuildInvites queues fetching the invites of the guild and merges them into the cache once fetched.
+func fetchGuildInvites(client *bot.Client, guildID snowflake.ID) {
+	v, _ := inviteFetchers.LoadOrStore(client, &inviteFetcher{})
+	f := v.(*inviteFetcher)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queue = append(f.queue, guildID)
+	if f.workers < inviteFetchWorkers {
+		f.workers++
+		go f.work(client)
+	}
+}
+
+func (f *inviteFetcher) work(client *bot.Client) {
+	ctx := client.Context()
+	for {
+		f.mu.Lock()
+		if len(f.queue) == 0 || ctx.Err() != nil {
+			f.queue = nil
+			f.workers--
+			if f.workers == 0 {
+				inviteFetchers.CompareAndDelete(client, f)
+			}
+			f.mu.Unlock()
+			return
+		}
+		guildID := f.queue[0]
+		f.queue = f.queue[1:]
+		f.mu.Unlock()
+
+		invites, err := client.Rest.GetGuildInvites(guildID, rest.WithCtx(ctx))
+		if err != nil {
+			client.Logger.Debug("failed to fetch guild invites on guild_create", slog.Any("err", err), slog.String("guild_id", guildID.String()))
+			continue
+		}
+		// INVITE_CREATE and INVITE_DELETE may have been received while fetching, so the invites are merged instead of replaced
+		client.Caches.MergeInvites(guildID, invites)
+	}
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/bot"
+	"github.com/fluxergo/fluxergo/cache"
+	"github.com/fluxergo/fluxergo/fluxer"
+	"github.com/fluxergo/fluxergo/rest"
+)
+
+type inviteTestRest struct {
+	rest.Rest
+	running    atomic.Int32
+	maxRunning atomic.Int32
+}
+
+func (r *inviteTestRest) GetGuildInvites(guildID snowflake.ID, _ ...rest.RequestOpt) ([]fluxer.ExtendedInvite, error) {
+	running := r.running.Add(1)
+	defer r.running.Add(-1)
+	for {
+		current := r.maxRunning.Load()
+		if running <= current || r.maxRunning.CompareAndSwap(current, running) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	return []fluxer.ExtendedInvite{{Invite: fluxer.Invite{Code: guildID.String()}}}, nil
+}
+
+func TestFetchGuildInvites(t *testing.T) {
+	const guilds = 10
+	restClient := &inviteTestRest{}
+	client := &bot.Client{
+		Logger: slog.Default(),
+		Rest:   restClient,
+		Caches: cache.New(cache.WithCaches(cache.FlagInvites)),
+	}
+
+	for guildID := snowflake.ID(1); guildID <= guilds; guildID++ {
+		fetchGuildInvites(client, guildID)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for client.Caches.InvitesAllLen() < guilds {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected invites of %d guilds, got %d", guilds, client.Caches.InvitesAllLen())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if maxRunning := restClient.maxRunning.Load(); maxRunning > inviteFetchWorkers {
+		t.Errorf("expected at most %d concurrent fetches, got %d", inviteFetchWorkers, maxRunning)
+	}
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"github.com/fluxergo/fluxergo/bot"
+	"github.com/fluxergo/fluxergo/events"
+	"github.com/fluxergo/fluxergo/gateway"
+)
+
+func gatewayHandlerThreadMemberUpdate(client *bot.Client, sequenceNumber int, shardID int, event gateway.EventThreadMemberUpdate) {
+	oldThreadMember, _ := client.Caches.ThreadMember(event.ThreadID, event.UserID)
+	client.Caches.AddThreadMember(event.ThreadMember)
+
+	client.EventManager.DispatchEvent(&events.ThreadMemberUpdate{
+		GenericThreadMember: &events.GenericThreadMember{
+			GenericEvent: events.NewGenericEvent(client, sequenceNumber, shardID),
+			GuildID:      event.GuildID,
+			ThreadID:     event.ThreadID,
+			UserID:       event.UserID,
+			ThreadMember: event.ThreadMember,
+		},
+		OldThreadMember: oldThreadMember,
+	})
+}
+
+func gatewayHandlerThreadMembersUpdate(client *bot.Client, sequenceNumber int, shardID int, event gateway.EventThreadMembersUpdate) {
+	for _, addedMember := range event.AddedMembers {
+		addedMember.GuildID = event.GuildID // populate unset field
+		addedMember.Member.GuildID = event.GuildID
+		client.Caches.AddThreadMember(addedMember.ThreadMember)
+		client.Caches.AddMember(addedMember.Member)
+		if addedMember.Presence != nil {
+			addedMember.Presence.GuildID = event.GuildID
+			client.Caches.AddPresence(*addedMember.Presence)
+		}
+
+		client.EventManager.DispatchEvent(&events.ThreadMemberAdd{
+			GenericThreadMember: &events.GenericThreadMember{
+				GenericEvent: events.NewGenericEvent(client, sequenceNumber, shardID),
+				GuildID:      event.GuildID,
+				ThreadID:     event.ID,
+				UserID:       addedMember.UserID,
+				ThreadMember: addedMember.ThreadMember,
+			},
+			Member:   addedMember.Member,
+			Presence: addedMember.Presence,
+		})
+	}
+
+	for _, userID := range event.RemovedMemberIDs {
+		threadMember, _ := client.Caches.RemoveThreadMember(event.ID, userID)
+
+		client.EventManager.DispatchEvent(&events.ThreadMemberRemove{
+			GenericThreadMember: &events.GenericThreadMember{
+				GenericEvent: events.NewGenericEvent(client, sequenceNumber, shardID),
+				GuildID:      event.GuildID,
+				ThreadID:     event.ID,
+				UserID:       userID,
+				ThreadMember: threadMember,
+			},
+		})
+	}
+}
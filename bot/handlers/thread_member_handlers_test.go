@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/bot"
+	"github.com/fluxergo/fluxergo/cache"
+	"github.com/fluxergo/fluxergo/events"
+	"github.com/fluxergo/fluxergo/fluxer"
+	"github.com/fluxergo/fluxergo/gateway"
+)
+
+func TestThreadMembersUpdate(t *testing.T) {
+	const (
+		guildID  snowflake.ID = 100
+		threadID snowflake.ID = 1
+	)
+
+	var (
+		added   []*events.ThreadMemberAdd
+		removed []*events.ThreadMemberRemove
+	)
+	client := &bot.Client{Caches: cache.New(cache.WithCaches(cache.FlagThreadMembers | cache.FlagMembers))}
+	client.EventManager = bot.NewEventManager(client,
+		bot.WithListenerFunc(func(e *events.ThreadMemberAdd) { added = append(added, e) }),
+		bot.WithListenerFunc(func(e *events.ThreadMemberRemove) { removed = append(removed, e) }),
+	)
+
+	gatewayHandlerThreadMembersUpdate(client, 1, 0, gateway.EventThreadMembersUpdate{
+		ID:      threadID,
+		GuildID: guildID,
+		AddedMembers: []fluxer.AddedThreadMember{
+			{ThreadMember: fluxer.ThreadMember{ThreadID: threadID, UserID: 10}, Member: fluxer.Member{User: fluxer.User{ID: 10}}},
+			{ThreadMember: fluxer.ThreadMember{ThreadID: threadID, UserID: 11}, Member: fluxer.Member{User: fluxer.User{ID: 11}}},
+		},
+	})
+	if len(added) != 2 || added[0].UserID != 10 || added[0].GuildID != guildID {
+		t.Fatalf("unexpected thread member add events: %v", added)
+	}
+	if member, ok := client.Caches.ThreadMember(threadID, 10); !ok || member.GuildID != guildID {
+		t.Errorf("expected thread member with guild to be cached, got %v", member)
+	}
+	if _, ok := client.Caches.Member(guildID, 11); !ok {
+		t.Error("expected member to be cached")
+	}
+
+	gatewayHandlerThreadMembersUpdate(client, 2, 0, gateway.EventThreadMembersUpdate{
+		ID:               threadID,
+		GuildID:          guildID,
+		RemovedMemberIDs: []snowflake.ID{10},
+	})
+	if len(removed) != 1 || removed[0].UserID != 10 || removed[0].ThreadMember.UserID != 10 {
+		t.Fatalf("unexpected thread member remove events: %v", removed)
+	}
+	if _, ok := client.Caches.ThreadMember(threadID, 10); ok {
+		t.Error("expected removed thread member to be uncached")
+	}
+	if l := client.Caches.ThreadMembersLen(threadID); l != 1 {
+		t.Errorf("expected 1 thread member, got %d", l)
+	}
+}
@@ -4,9 +4,18 @@ import (
 	"github.com/fluxergo/fluxergo/bot"
 	"github.com/fluxergo/fluxergo/events"
 	"github.com/fluxergo/fluxergo/gateway"
+	"github.com/fluxergo/fluxergo/voice"
 )
 
 func gatewayHandlerVoiceStateUpdate(client *bot.Client, sequenceNumber int, shardID int, event gateway.EventVoiceStateUpdate) {
+	// voice states of calls in private channels have no guild, so they are neither cached nor dispatched as guild voice events
+	if event.GuildID == 0 {
+		if event.UserID == client.ID() && client.VoiceManager != nil {
+			client.VoiceManager.HandleVoiceStateUpdate(event)
+		}
+		return
+	}
+
 	member := event.Member
 
 	oldVoiceState, oldOk := client.Caches.VoiceState(event.GuildID, event.UserID)
@@ -61,3 +70,45 @@ func gatewayHandlerVoiceServerUpdate(client *bot.Client, sequenceNumber int, sha
 		EventVoiceServerUpdate: event,
 	})
 }
+
+// HandleVoiceConnEvent dispatches the voice.ConnEvent(s) of a voice.Conn as VoiceConnStatusChange, VoiceSpeakingStart, VoiceSpeakingStop, VoiceParticipantJoin and VoiceParticipantLeave through the bot.EventManager.
+func HandleVoiceConnEvent(client *bot.Client, conn voice.Conn, event voice.ConnEvent) {
+	var shardID int
+	if client.HasGateway() {
+		shardID = client.Gateway.ShardID()
+	}
+	genericEvent := &events.GenericVoiceConn{
+		GenericEvent: events.NewGenericEvent(client, -1, shardID),
+		Conn:         conn,
+	}
+
+	switch e := event.(type) {
+	case voice.ConnStatusChange:
+		client.EventManager.DispatchEvent(&events.VoiceConnStatusChange{
+			GenericVoiceConn: genericEvent,
+			OldStatus:        e.OldStatus,
+			NewStatus:        e.NewStatus,
+		})
+	case voice.SpeakingStart:
+		client.EventManager.DispatchEvent(&events.VoiceSpeakingStart{
+			GenericVoiceConn: genericEvent,
+			UserID:           e.UserID,
+			Level:            e.Level,
+		})
+	case voice.SpeakingStop:
+		client.EventManager.DispatchEvent(&events.VoiceSpeakingStop{
+			GenericVoiceConn: genericEvent,
+			UserID:           e.UserID,
+		})
+	case voice.ParticipantJoin:
+		client.EventManager.DispatchEvent(&events.VoiceParticipantJoin{
+			GenericVoiceConn: genericEvent,
+			UserID:           e.UserID,
+		})
+	case voice.ParticipantLeave:
+		client.EventManager.DispatchEvent(&events.VoiceParticipantLeave{
+			GenericVoiceConn: genericEvent,
+			UserID:           e.UserID,
+		})
+	}
+}
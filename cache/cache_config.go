@@ -13,9 +13,11 @@ func defaultConfig() config {
 		GuildScheduledEventCachePolicy: PolicyAll[fluxer.GuildScheduledEvent],
 		RoleCachePolicy:                PolicyAll[fluxer.Role],
 		MemberCachePolicy:              PolicyAll[fluxer.Member],
+		ThreadMemberCachePolicy:        PolicyAll[fluxer.ThreadMember],
 		PresenceCachePolicy:            PolicyAll[fluxer.Presence],
 		VoiceStateCachePolicy:          PolicyAll[fluxer.VoiceState],
 		MessageCachePolicy:             PolicyAll[fluxer.Message],
+		InviteCachePolicy:              PolicyAll[fluxer.ExtendedInvite],
 		EmojiCachePolicy:               PolicyAll[fluxer.Emoji],
 		StickerCachePolicy:             PolicyAll[fluxer.Sticker],
 	}
@@ -24,6 +26,13 @@ func defaultConfig() config {
 type config struct {
 	CacheFlags Flags
 
+	// ShardCount is the number of shards the default caches are split into. 0 means the default unsharded caches are used.
+	ShardCount int
+
+	ReconcileMode ReconcileMode
+
+	StatsDisabled bool
+
 	SelfUserCache SelfUserCache
 
 	GuildCache       GuildCache
@@ -41,6 +50,9 @@ type config struct {
 	MemberCache       MemberCache
 	MemberCachePolicy Policy[fluxer.Member]
 
+	ThreadMemberCache       ThreadMemberCache
+	ThreadMemberCachePolicy Policy[fluxer.ThreadMember]
+
 	PresenceCache       PresenceCache
 	PresenceCachePolicy Policy[fluxer.Presence]
 
@@ -50,6 +62,9 @@ type config struct {
 	MessageCache       MessageCache
 	MessageCachePolicy Policy[fluxer.Message]
 
+	InviteCache       InviteCache
+	InviteCachePolicy Policy[fluxer.ExtendedInvite]
+
 	EmojiCache       EmojiCache
 	EmojiCachePolicy Policy[fluxer.Emoji]
 
@@ -68,35 +83,71 @@ func (c *config) apply(opts []ConfigOpt) {
 		c.SelfUserCache = NewSelfUserCache()
 	}
 	if c.GuildCache == nil {
-		c.GuildCache = NewGuildCache(NewCache[fluxer.Guild](c.CacheFlags, FlagGuilds, c.GuildCachePolicy), NewSet[snowflake.ID](), NewSet[snowflake.ID]())
+		c.GuildCache = NewGuildCache(newCache[fluxer.Guild](c, FlagGuilds, c.GuildCachePolicy), NewSet[snowflake.ID](), NewSet[snowflake.ID]())
 	}
 	if c.ChannelCache == nil {
-		c.ChannelCache = NewChannelCache(NewCache[fluxer.GuildChannel](c.CacheFlags, FlagChannels, c.ChannelCachePolicy))
+		c.ChannelCache = NewChannelCache(newCache[fluxer.GuildChannel](c, FlagChannels, c.ChannelCachePolicy))
 	}
 	if c.GuildScheduledEventCache == nil {
-		c.GuildScheduledEventCache = NewGuildScheduledEventCache(NewGroupedCache[fluxer.GuildScheduledEvent](c.CacheFlags, FlagGuildScheduledEvents, c.GuildScheduledEventCachePolicy))
+		c.GuildScheduledEventCache = NewGuildScheduledEventCache(newGroupedCache[fluxer.GuildScheduledEvent](c, FlagGuildScheduledEvents, c.GuildScheduledEventCachePolicy))
 	}
 	if c.RoleCache == nil {
-		c.RoleCache = NewRoleCache(NewGroupedCache[fluxer.Role](c.CacheFlags, FlagRoles, c.RoleCachePolicy))
+		c.RoleCache = NewRoleCache(newGroupedCache[fluxer.Role](c, FlagRoles, c.RoleCachePolicy))
 	}
 	if c.MemberCache == nil {
-		c.MemberCache = NewMemberCache(NewGroupedCache[fluxer.Member](c.CacheFlags, FlagMembers, c.MemberCachePolicy))
+		c.MemberCache = NewMemberCache(newGroupedCache[fluxer.Member](c, FlagMembers, c.MemberCachePolicy))
+	}
+	if c.ThreadMemberCache == nil {
+		c.ThreadMemberCache = NewThreadMemberCache(newGroupedCache[fluxer.ThreadMember](c, FlagThreadMembers, c.ThreadMemberCachePolicy))
 	}
 	if c.PresenceCache == nil {
-		c.PresenceCache = NewPresenceCache(NewGroupedCache[fluxer.Presence](c.CacheFlags, FlagPresences, c.PresenceCachePolicy))
+		c.PresenceCache = NewPresenceCache(newGroupedCache[fluxer.Presence](c, FlagPresences, c.PresenceCachePolicy))
 	}
 	if c.VoiceStateCache == nil {
-		c.VoiceStateCache = NewVoiceStateCache(NewGroupedCache[fluxer.VoiceState](c.CacheFlags, FlagVoiceStates, c.VoiceStateCachePolicy))
+		c.VoiceStateCache = NewVoiceStateCache(newGroupedCache[fluxer.VoiceState](c, FlagVoiceStates, c.VoiceStateCachePolicy))
 	}
 	if c.MessageCache == nil {
-		c.MessageCache = NewMessageCache(NewGroupedCache[fluxer.Message](c.CacheFlags, FlagMessages, c.MessageCachePolicy))
+		c.MessageCache = NewMessageCache(newGroupedCache[fluxer.Message](c, FlagMessages, c.MessageCachePolicy))
+	}
+	if c.InviteCache == nil {
+		c.InviteCache = NewInviteCache(newGroupedCache[fluxer.ExtendedInvite](c, FlagInvites, c.InviteCachePolicy))
 	}
 	if c.EmojiCache == nil {
-		c.EmojiCache = NewEmojiCache(NewGroupedCache[fluxer.Emoji](c.CacheFlags, FlagEmojis, c.EmojiCachePolicy))
+		c.EmojiCache = NewEmojiCache(newGroupedCache[fluxer.Emoji](c, FlagEmojis, c.EmojiCachePolicy))
 	}
 	if c.StickerCache == nil {
-		c.StickerCache = NewStickerCache(NewGroupedCache[fluxer.Sticker](c.CacheFlags, FlagStickers, c.StickerCachePolicy))
+		c.StickerCache = NewStickerCache(newGroupedCache[fluxer.Sticker](c, FlagStickers, c.StickerCachePolicy))
+	}
+}
+
+// newCache returns a ShardedCache if sharding is enabled in the config and a DefaultCache otherwise.
+// Unless stats are disabled, the cache is wrapped to count hits and misses.
+func newCache[T any](c *config, neededFlags Flags, policy Policy[T]) Cache[T] {
+	var cache Cache[T]
+	if c.ShardCount > 0 {
+		cache = NewShardedCache[T](c.CacheFlags, neededFlags, policy, c.ShardCount)
+	} else {
+		cache = NewCache[T](c.CacheFlags, neededFlags, policy)
+	}
+	if c.StatsDisabled {
+		return cache
 	}
+	return NewStatsCache(cache)
+}
+
+// newGroupedCache returns a sharded GroupedCache if sharding is enabled in the config and the default GroupedCache otherwise.
+// Unless stats are disabled, the cache is wrapped to count hits and misses.
+func newGroupedCache[T any](c *config, neededFlags Flags, policy Policy[T]) GroupedCache[T] {
+	var cache GroupedCache[T]
+	if c.ShardCount > 0 {
+		cache = NewShardedGroupedCache[T](c.CacheFlags, neededFlags, policy, c.ShardCount)
+	} else {
+		cache = NewGroupedCache[T](c.CacheFlags, neededFlags, policy)
+	}
+	if c.StatsDisabled {
+		return cache
+	}
+	return NewStatsGroupedCache(cache)
 }
 
 // WithCaches sets the Flags of the config.
@@ -106,6 +157,33 @@ func WithCaches(flags ...Flags) ConfigOpt {
 	}
 }
 
+// WithShardedCaches makes the default caches partition their entities into shardCount independently locked shards.
+// This reduces lock contention for bots with many concurrent writers, for example with bot.WithAsyncEventsEnabled.
+// If shardCount is less than 1, DefaultShardCount is used.
+func WithShardedCaches(shardCount int) ConfigOpt {
+	return func(config *config) {
+		if shardCount < 1 {
+			shardCount = DefaultShardCount
+		}
+		config.ShardCount = shardCount
+	}
+}
+
+// WithReconcileMode sets the ReconcileMode of the config.
+func WithReconcileMode(mode ReconcileMode) ConfigOpt {
+	return func(config *config) {
+		config.ReconcileMode = mode
+	}
+}
+
+// WithStatsDisabled disables counting hits and misses of the default caches.
+// Caches.Stats still reports the entries, groups and estimated size of each cache.
+func WithStatsDisabled() ConfigOpt {
+	return func(config *config) {
+		config.StatsDisabled = true
+	}
+}
+
 // WithSelfUserCache sets the SelfUserCache of the config.
 func WithSelfUserCache(cache SelfUserCache) ConfigOpt {
 	return func(config *config) {
@@ -183,6 +261,20 @@ func WithMemberCache(memberCache MemberCache) ConfigOpt {
 	}
 }
 
+// WithThreadMemberCachePolicy sets the Policy[fluxer.ThreadMember] of the config.
+func WithThreadMemberCachePolicy(policy Policy[fluxer.ThreadMember]) ConfigOpt {
+	return func(config *config) {
+		config.ThreadMemberCachePolicy = policy
+	}
+}
+
+// WithThreadMemberCache sets the ThreadMemberCache of the config.
+func WithThreadMemberCache(threadMemberCache ThreadMemberCache) ConfigOpt {
+	return func(config *config) {
+		config.ThreadMemberCache = threadMemberCache
+	}
+}
+
 // WithPresenceCachePolicy sets the Policy[fluxer.Presence] of the config.
 func WithPresenceCachePolicy(policy Policy[fluxer.Presence]) ConfigOpt {
 	return func(config *config) {
@@ -225,6 +317,20 @@ func WithMessageCache(messageCache MessageCache) ConfigOpt {
 	}
 }
 
+// WithInviteCachePolicy sets the Policy[fluxer.ExtendedInvite] of the config.
+func WithInviteCachePolicy(policy Policy[fluxer.ExtendedInvite]) ConfigOpt {
+	return func(config *config) {
+		config.InviteCachePolicy = policy
+	}
+}
+
+// WithInviteCache sets the InviteCache of the config.
+func WithInviteCache(inviteCache InviteCache) ConfigOpt {
+	return func(config *config) {
+		config.InviteCache = inviteCache
+	}
+}
+
 // WithEmojiCachePolicy sets the Policy[fluxer.Emoji] of the config.
 func WithEmojiCachePolicy(policy Policy[fluxer.Emoji]) ConfigOpt {
 	return func(config *config) {
@@ -21,6 +21,9 @@ const (
 	FlagStageInstances
 	FlagGuildSoundboardSounds
 
+	// FlagInvites enables the InviteCache. It is not part of FlagsAll, as populating it requires fetching the invites of every guild via the REST API.
+	FlagInvites
+
 	FlagsNone Flags = 0
 	FlagsAll        = FlagGuilds |
 		FlagGuildScheduledEvents |
@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/fluxer"
+)
+
+// ReconcileMode defines how the cache is reconciled with the full guild state received in a gateway.EventTypeGuildCreate.
+type ReconcileMode int
+
+const (
+	// ReconcileModeNone does not reconcile the cache. Entities which got deleted while the guild was not received stay cached.
+	ReconcileModeNone ReconcileMode = iota
+
+	// ReconcileModeEvict removes all cached channels, roles, emojis, stickers and voice states of a guild which are no longer part of the received guild.
+	// The thread members of removed channels are removed as well.
+	// Additionally, entities of unavailable guilds are kept until the guild becomes available again, so they can be reconciled.
+	ReconcileModeEvict
+
+	// ReconcileModeEvictAndDispatch works like ReconcileModeEvict, but additionally dispatches synthetic delete events for all evicted entities.
+	ReconcileModeEvictAndDispatch
+)
+
+// Evict returns whether stale entities should be evicted from the cache.
+func (m ReconcileMode) Evict() bool {
+	return m >= ReconcileModeEvict
+}
+
+// Dispatch returns whether delete events should be dispatched for evicted entities.
+func (m ReconcileMode) Dispatch() bool {
+	return m == ReconcileModeEvictAndDispatch
+}
+
+// ReconcileResult contains all entities which got evicted by Caches.ReconcileGuild.
+type ReconcileResult struct {
+	Channels      []fluxer.GuildChannel
+	ThreadMembers []fluxer.ThreadMember
+	Roles         []fluxer.Role
+	Emojis        []fluxer.Emoji
+	Stickers      []fluxer.Sticker
+	VoiceStates   []fluxer.VoiceState
+}
+
+// Empty returns whether no entities got evicted.
+func (r ReconcileResult) Empty() bool {
+	return len(r.Channels) == 0 && len(r.ThreadMembers) == 0 && len(r.Roles) == 0 && len(r.Emojis) == 0 && len(r.Stickers) == 0 && len(r.VoiceStates) == 0
+}
+
+func (c *cachesImpl) ReconcileMode() ReconcileMode {
+	return c.config.ReconcileMode
+}
+
+func (c *cachesImpl) ReconcileGuild(guild fluxer.GatewayGuild) ReconcileResult {
+	var result ReconcileResult
+
+	channelIDs := make(map[snowflake.ID]struct{}, len(guild.Channels))
+	for _, channel := range guild.Channels {
+		channelIDs[channel.ID()] = struct{}{}
+	}
+	var staleChannelIDs []snowflake.ID
+	for channel := range c.ChannelsForGuild(guild.ID) {
+		if _, ok := channelIDs[channel.ID()]; !ok {
+			staleChannelIDs = append(staleChannelIDs, channel.ID())
+		}
+	}
+	for _, channelID := range staleChannelIDs {
+		if channel, ok := c.RemoveChannel(channelID); ok {
+			c.RemoveMessagesByChannelID(channelID)
+			result.Channels = append(result.Channels, channel)
+			for threadMember := range c.ThreadMembers(channelID) {
+				result.ThreadMembers = append(result.ThreadMembers, threadMember)
+			}
+			c.RemoveThreadMembersByThreadID(channelID)
+		}
+	}
+
+	roleIDs := make(map[snowflake.ID]struct{}, len(guild.Roles))
+	for _, role := range guild.Roles {
+		roleIDs[role.ID] = struct{}{}
+	}
+	result.Roles = reconcileGroup(c.RoleCache(), guild.ID, func(role fluxer.Role) bool {
+		_, ok := roleIDs[role.ID]
+		return !ok
+	}, func(role fluxer.Role) snowflake.ID { return role.ID })
+
+	emojiIDs := make(map[snowflake.ID]struct{}, len(guild.Emojis))
+	for _, emoji := range guild.Emojis {
+		emojiIDs[emoji.ID] = struct{}{}
+	}
+	result.Emojis = reconcileGroup(c.EmojiCache(), guild.ID, func(emoji fluxer.Emoji) bool {
+		_, ok := emojiIDs[emoji.ID]
+		return !ok
+	}, func(emoji fluxer.Emoji) snowflake.ID { return emoji.ID })
+
+	stickerIDs := make(map[snowflake.ID]struct{}, len(guild.Stickers))
+	for _, sticker := range guild.Stickers {
+		stickerIDs[sticker.ID] = struct{}{}
+	}
+	result.Stickers = reconcileGroup(c.StickerCache(), guild.ID, func(sticker fluxer.Sticker) bool {
+		_, ok := stickerIDs[sticker.ID]
+		return !ok
+	}, func(sticker fluxer.Sticker) snowflake.ID { return sticker.ID })
+
+	userIDs := make(map[snowflake.ID]struct{}, len(guild.VoiceStates))
+	for _, voiceState := range guild.VoiceStates {
+		userIDs[voiceState.UserID] = struct{}{}
+	}
+	result.VoiceStates = reconcileGroup(c.VoiceStateCache(), guild.ID, func(voiceState fluxer.VoiceState) bool {
+		_, ok := userIDs[voiceState.UserID]
+		return !ok
+	}, func(voiceState fluxer.VoiceState) snowflake.ID { return voiceState.UserID })
+
+	return result
+}
+
+// reconcileGroup removes all entities of the given group which are stale and returns them.
+// The stale entities are collected first, as the GroupedCache can't be modified while iterating it.
+func reconcileGroup[T any](cache GroupedCache[T], groupID snowflake.ID, stale func(T) bool, id func(T) snowflake.ID) []T {
+	var staleIDs []snowflake.ID
+	for entity := range cache.GroupAll(groupID) {
+		if stale(entity) {
+			staleIDs = append(staleIDs, id(entity))
+		}
+	}
+
+	var removed []T
+	for _, entityID := range staleIDs {
+		if entity, ok := cache.Remove(groupID, entityID); ok {
+			removed = append(removed, entity)
+		}
+	}
+	return removed
+}
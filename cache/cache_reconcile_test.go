@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/fluxer"
+)
+
+func testGuildChannel(t *testing.T, id snowflake.ID, guildID snowflake.ID) fluxer.GuildChannel {
+	t.Helper()
+	var v fluxer.UnmarshalChannel
+	data := `{"id":"` + id.String() + `","guild_id":"` + guildID.String() + `","type":0}`
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatalf("failed to unmarshal channel: %v", err)
+	}
+	return v.Channel.(fluxer.GuildChannel)
+}
+
+func TestCaches_ReconcileGuild(t *testing.T) {
+	const guildID snowflake.ID = 10
+	caches := New(WithCaches(FlagsAll), WithReconcileMode(ReconcileModeEvict))
+
+	for _, id := range []snowflake.ID{1, 2, 3} {
+		caches.AddChannel(testGuildChannel(t, id, guildID))
+		caches.AddRole(fluxer.Role{ID: id, GuildID: guildID})
+		caches.AddEmoji(fluxer.Emoji{PartialEmoji: fluxer.PartialEmoji{ID: id}, GuildID: guildID})
+		caches.AddVoiceState(fluxer.VoiceState{UserID: id, GuildID: guildID})
+	}
+	caches.AddThreadMember(fluxer.ThreadMember{ThreadID: 2, UserID: 5, GuildID: guildID})
+	caches.AddThreadMember(fluxer.ThreadMember{ThreadID: 1, UserID: 5, GuildID: guildID})
+	// entities of other guilds must not be touched
+	caches.AddChannel(testGuildChannel(t, 4, 20))
+	caches.AddRole(fluxer.Role{ID: 4, GuildID: 20})
+
+	guild := fluxer.GatewayGuild{
+		RestGuild: fluxer.RestGuild{
+			Guild:  fluxer.Guild{ID: guildID},
+			Roles:  []fluxer.Role{{ID: 1}, {ID: 3}},
+			Emojis: []fluxer.Emoji{{PartialEmoji: fluxer.PartialEmoji{ID: 1}}, {PartialEmoji: fluxer.PartialEmoji{ID: 2}}, {PartialEmoji: fluxer.PartialEmoji{ID: 3}}},
+		},
+		Channels:    []fluxer.GuildChannel{testGuildChannel(t, 1, guildID)},
+		VoiceStates: []fluxer.VoiceState{{UserID: 2}},
+	}
+
+	result := caches.ReconcileGuild(guild)
+
+	if len(result.Channels) != 2 || caches.ChannelsLen() != 2 {
+		t.Errorf("expected 2 evicted channels and 2 cached, got %d evicted and %d cached", len(result.Channels), caches.ChannelsLen())
+	}
+	if len(result.ThreadMembers) != 1 || result.ThreadMembers[0].ThreadID != 2 || caches.ThreadMembersLen(2) != 0 {
+		t.Errorf("expected thread member of evicted channel 2 to be evicted, got %v", result.ThreadMembers)
+	}
+	if caches.ThreadMembersLen(1) != 1 {
+		t.Error("expected thread member of kept channel to be kept")
+	}
+	if len(result.Roles) != 1 || result.Roles[0].ID != 2 {
+		t.Errorf("expected role 2 to be evicted, got %v", result.Roles)
+	}
+	if caches.RolesLen(20) != 1 {
+		t.Error("expected role of other guild to be kept")
+	}
+	if len(result.Emojis) != 0 {
+		t.Errorf("expected no evicted emojis, got %v", result.Emojis)
+	}
+	if len(result.VoiceStates) != 2 || caches.VoiceStatesLen(guildID) != 1 {
+		t.Errorf("expected 2 evicted voice states, got %v", result.VoiceStates)
+	}
+	if result.Empty() {
+		t.Error("expected result to not be empty")
+	}
+}
@@ -0,0 +1,263 @@
+package cache
+
+import (
+	"iter"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// statsSampleSize is the number of entities which are inspected to estimate the memory usage of a cache.
+const statsSampleSize = 64
+
+// Stats contains information about the memory usage and efficiency of a single cache.
+type Stats struct {
+	// Name is the name of the cache, for example "members".
+	Name string `json:"name"`
+	// Entries is the number of entities in the cache.
+	Entries int `json:"entries"`
+	// Groups is the number of groups in the cache. This is always 0 for caches which are not grouped.
+	Groups int `json:"groups"`
+	// EstimatedBytes is a rough estimation of the memory used by the entities in the cache, extrapolated from a sample of entities.
+	EstimatedBytes int64 `json:"estimated_bytes"`
+	// Hits is the number of Get calls which found an entity. This is always 0 if stats are disabled.
+	Hits uint64 `json:"hits"`
+	// Misses is the number of Get calls which did not find an entity. This is always 0 if stats are disabled.
+	Misses uint64 `json:"misses"`
+}
+
+// HitRatio returns the ratio of Get calls which found an entity or 0 if there were no Get calls.
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+type statsCounter struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func (c *statsCounter) record(ok bool) {
+	if ok {
+		c.hits.Add(1)
+		return
+	}
+	c.misses.Add(1)
+}
+
+var _ Cache[any] = (*statsCache[any])(nil)
+
+// NewStatsCache wraps the given Cache and counts hits and misses of Get calls.
+func NewStatsCache[T any](cache Cache[T]) Cache[T] {
+	return &statsCache[T]{Cache: cache}
+}
+
+type statsCache[T any] struct {
+	Cache[T]
+	counter statsCounter
+}
+
+func (c *statsCache[T]) Get(id snowflake.ID) (T, bool) {
+	entity, ok := c.Cache.Get(id)
+	c.counter.record(ok)
+	return entity, ok
+}
+
+var _ GroupedCache[any] = (*statsGroupedCache[any])(nil)
+
+// NewStatsGroupedCache wraps the given GroupedCache and counts hits and misses of Get calls.
+func NewStatsGroupedCache[T any](cache GroupedCache[T]) GroupedCache[T] {
+	return &statsGroupedCache[T]{GroupedCache: cache}
+}
+
+type statsGroupedCache[T any] struct {
+	GroupedCache[T]
+	counter statsCounter
+}
+
+func (c *statsGroupedCache[T]) Get(groupID snowflake.ID, id snowflake.ID) (T, bool) {
+	entity, ok := c.GroupedCache.Get(groupID, id)
+	c.counter.record(ok)
+	return entity, ok
+}
+
+func (c *statsGroupedCache[T]) groupsLen() int {
+	return groupsLen(c.GroupedCache)
+}
+
+func (c *defaultGroupedCache[T]) groupsLen() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.cache)
+}
+
+func (c *shardedGroupedCache[T]) groupsLen() int {
+	c.rLockAll()
+	defer c.rUnlockAll()
+	var groups int
+	for i := range c.shards {
+		groups += len(c.shards[i].cache)
+	}
+	return groups
+}
+
+// groupsLen returns the number of groups in the given GroupedCache.
+// Custom implementations are iterated to count the distinct groups.
+func groupsLen[T any](cache GroupedCache[T]) int {
+	if c, ok := cache.(interface{ groupsLen() int }); ok {
+		return c.groupsLen()
+	}
+	groups := make(map[snowflake.ID]struct{})
+	for groupID := range cache.All() {
+		groups[groupID] = struct{}{}
+	}
+	return len(groups)
+}
+
+// counterOf returns the statsCounter of the given cache or nil if it doesn't count hits and misses.
+func counterOf(cache any) *statsCounter {
+	if c, ok := cache.(interface{ statsCounter() *statsCounter }); ok {
+		return c.statsCounter()
+	}
+	return nil
+}
+
+func (c *statsCache[T]) statsCounter() *statsCounter {
+	return &c.counter
+}
+
+func (c *statsGroupedCache[T]) statsCounter() *statsCounter {
+	return &c.counter
+}
+
+func newStats[T any](name string, entries int, all iter.Seq[T], counter *statsCounter) Stats {
+	stats := Stats{
+		Name:           name,
+		Entries:        entries,
+		EstimatedBytes: estimateBytes(all, entries),
+	}
+	if counter != nil {
+		stats.Hits = counter.hits.Load()
+		stats.Misses = counter.misses.Load()
+	}
+	return stats
+}
+
+func cacheStats[T any](name string, cache Cache[T]) Stats {
+	return newStats(name, cache.Len(), cache.All(), counterOf(cache))
+}
+
+func groupedCacheStats[T any](name string, cache GroupedCache[T]) Stats {
+	stats := newStats(name, cache.Len(), func(yield func(T) bool) {
+		for _, entity := range cache.All() {
+			if !yield(entity) {
+				return
+			}
+		}
+	}, counterOf(cache))
+	stats.Groups = groupsLen(cache)
+	return stats
+}
+
+// estimateBytes estimates the memory used by all entities by measuring up to statsSampleSize entities and extrapolating the result.
+func estimateBytes[T any](all iter.Seq[T], entries int) int64 {
+	if entries == 0 {
+		return 0
+	}
+	var (
+		sampled int
+		size    int64
+	)
+	for entity := range all {
+		size += sizeOf(reflect.ValueOf(&entity).Elem(), 0)
+		sampled++
+		if sampled >= statsSampleSize {
+			break
+		}
+	}
+	if sampled == 0 {
+		return 0
+	}
+	return size / int64(sampled) * int64(entries)
+}
+
+// sizeOf returns the approximate amount of bytes used by the given value including everything it references.
+func sizeOf(v reflect.Value, depth int) int64 {
+	size := int64(v.Type().Size())
+	return size + referencedSizeOf(v, depth)
+}
+
+// referencedSizeOf returns the approximate amount of bytes referenced by the given value, excluding the value itself.
+func referencedSizeOf(v reflect.Value, depth int) int64 {
+	// guard against reference cycles
+	if depth > 8 {
+		return 0
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return 0
+		}
+		return sizeOf(v.Elem(), depth+1)
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		return sizeOf(v.Elem(), depth+1)
+	case reflect.String:
+		return int64(v.Len())
+	case reflect.Slice:
+		if v.IsNil() {
+			return 0
+		}
+		size := int64(v.Cap()) * int64(v.Type().Elem().Size())
+		for i := range v.Len() {
+			size += referencedSizeOf(v.Index(i), depth+1)
+		}
+		return size
+	case reflect.Array:
+		var size int64
+		for i := range v.Len() {
+			size += referencedSizeOf(v.Index(i), depth+1)
+		}
+		return size
+	case reflect.Map:
+		if v.IsNil() {
+			return 0
+		}
+		var size int64
+		iter := v.MapRange()
+		for iter.Next() {
+			size += sizeOf(iter.Key(), depth+1) + sizeOf(iter.Value(), depth+1)
+		}
+		return size
+	case reflect.Struct:
+		var size int64
+		for i := range v.NumField() {
+			size += referencedSizeOf(v.Field(i), depth+1)
+		}
+		return size
+	}
+	return 0
+}
+
+func (c *cachesImpl) Stats() []Stats {
+	return []Stats{
+		cacheStats("guilds", c.GuildCache()),
+		cacheStats("channels", c.ChannelCache()),
+		groupedCacheStats("guild_scheduled_events", c.GuildScheduledEventCache()),
+		groupedCacheStats("roles", c.RoleCache()),
+		groupedCacheStats("members", c.MemberCache()),
+		groupedCacheStats("thread_members", c.ThreadMemberCache()),
+		groupedCacheStats("presences", c.PresenceCache()),
+		groupedCacheStats("voice_states", c.VoiceStateCache()),
+		groupedCacheStats("messages", c.MessageCache()),
+		groupedCacheStats("invites", c.InviteCache()),
+		groupedCacheStats("emojis", c.EmojiCache()),
+		groupedCacheStats("stickers", c.StickerCache()),
+	}
+}
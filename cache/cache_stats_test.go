@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/fluxergo/fluxergo/fluxer"
+)
+
+func TestCaches_Stats(t *testing.T) {
+	caches := New(WithCaches(FlagsAll))
+
+	caches.AddRole(fluxer.Role{ID: 1, GuildID: 10, Name: "role"})
+	caches.AddRole(fluxer.Role{ID: 2, GuildID: 10, Name: "role"})
+	caches.AddRole(fluxer.Role{ID: 3, GuildID: 20, Name: "role"})
+
+	caches.Role(10, 1)
+	caches.Role(10, 4)
+
+	var stats Stats
+	for _, s := range caches.Stats() {
+		if s.Name == "roles" {
+			stats = s
+		}
+	}
+
+	if stats.Entries != 3 || stats.Groups != 2 {
+		t.Errorf("expected 3 entries in 2 groups, got %d in %d", stats.Entries, stats.Groups)
+	}
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("expected 1 hit and 1 miss, got %d and %d", stats.Hits, stats.Misses)
+	}
+	if stats.EstimatedBytes < 3*int64(len("role")) {
+		t.Errorf("expected estimated bytes to be at least the size of the names, got %d", stats.EstimatedBytes)
+	}
+}
+
+func TestCaches_StatsDisabled(t *testing.T) {
+	caches := New(WithCaches(FlagsAll), WithStatsDisabled())
+
+	caches.AddRole(fluxer.Role{ID: 1, GuildID: 10})
+	caches.Role(10, 1)
+
+	for _, s := range caches.Stats() {
+		if s.Hits != 0 || s.Misses != 0 {
+			t.Errorf("expected no hits or misses for %s, got %d and %d", s.Name, s.Hits, s.Misses)
+		}
+	}
+}
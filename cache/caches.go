@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"hash/fnv"
 	"iter"
 	"slices"
 	"sync"
@@ -364,6 +365,67 @@ func (c *memberCacheImpl) RemoveMembersByGuildID(guildID snowflake.ID) {
 	c.cache.GroupRemove(guildID)
 }
 
+type ThreadMemberCache interface {
+	ThreadMemberCache() GroupedCache[fluxer.ThreadMember]
+
+	ThreadMember(threadID snowflake.ID, userID snowflake.ID) (fluxer.ThreadMember, bool)
+	ThreadMembers(threadID snowflake.ID) iter.Seq[fluxer.ThreadMember]
+	ThreadMembersAllLen() int
+	ThreadMembersLen(threadID snowflake.ID) int
+	AddThreadMember(threadMember fluxer.ThreadMember)
+	RemoveThreadMember(threadID snowflake.ID, userID snowflake.ID) (fluxer.ThreadMember, bool)
+	RemoveThreadMembersByThreadID(threadID snowflake.ID)
+	RemoveThreadMembersByGuildID(guildID snowflake.ID)
+}
+
+func NewThreadMemberCache(cache GroupedCache[fluxer.ThreadMember]) ThreadMemberCache {
+	return &threadMemberCacheImpl{
+		cache: cache,
+	}
+}
+
+type threadMemberCacheImpl struct {
+	cache GroupedCache[fluxer.ThreadMember]
+}
+
+func (c *threadMemberCacheImpl) ThreadMemberCache() GroupedCache[fluxer.ThreadMember] {
+	return c.cache
+}
+
+func (c *threadMemberCacheImpl) ThreadMember(threadID snowflake.ID, userID snowflake.ID) (fluxer.ThreadMember, bool) {
+	return c.cache.Get(threadID, userID)
+}
+
+func (c *threadMemberCacheImpl) ThreadMembers(threadID snowflake.ID) iter.Seq[fluxer.ThreadMember] {
+	return c.cache.GroupAll(threadID)
+}
+
+func (c *threadMemberCacheImpl) ThreadMembersAllLen() int {
+	return c.cache.Len()
+}
+
+func (c *threadMemberCacheImpl) ThreadMembersLen(threadID snowflake.ID) int {
+	return c.cache.GroupLen(threadID)
+}
+
+func (c *threadMemberCacheImpl) AddThreadMember(threadMember fluxer.ThreadMember) {
+	c.cache.Put(threadMember.ThreadID, threadMember.UserID, threadMember)
+}
+
+func (c *threadMemberCacheImpl) RemoveThreadMember(threadID snowflake.ID, userID snowflake.ID) (fluxer.ThreadMember, bool) {
+	return c.cache.Remove(threadID, userID)
+}
+
+func (c *threadMemberCacheImpl) RemoveThreadMembersByThreadID(threadID snowflake.ID) {
+	c.cache.GroupRemove(threadID)
+}
+
+func (c *threadMemberCacheImpl) RemoveThreadMembersByGuildID(guildID snowflake.ID) {
+	c.cache.RemoveIf(func(_ snowflake.ID, threadMember fluxer.ThreadMember) bool {
+		return threadMember.GuildID == guildID
+	})
+}
+
 type PresenceCache interface {
 	PresenceCache() GroupedCache[fluxer.Presence]
 
@@ -533,6 +595,97 @@ func (c *messageCacheImpl) RemoveMessagesByGuildID(guildID snowflake.ID) {
 	})
 }
 
+type InviteCache interface {
+	InviteCache() GroupedCache[fluxer.ExtendedInvite]
+
+	Invite(guildID snowflake.ID, code string) (fluxer.ExtendedInvite, bool)
+	Invites(guildID snowflake.ID) iter.Seq[fluxer.ExtendedInvite]
+	InvitesAllLen() int
+	InvitesLen(guildID snowflake.ID) int
+	AddInvite(guildID snowflake.ID, invite fluxer.ExtendedInvite)
+	// SetInvites replaces all cached invites of the guild.
+	SetInvites(guildID snowflake.ID, invites []fluxer.ExtendedInvite)
+	// MergeInvites adds the invites to the guild and only updates cached invites which have fewer uses.
+	MergeInvites(guildID snowflake.ID, invites []fluxer.ExtendedInvite)
+	RemoveInvite(guildID snowflake.ID, code string) (fluxer.ExtendedInvite, bool)
+	RemoveInvitesByGuildID(guildID snowflake.ID)
+}
+
+// NewInviteCache returns a new InviteCache backed by the given GroupedCache.
+// Invites are keyed by their code instead of a snowflake, so the GroupedCache stores them under a hash of the code, see InviteID.
+func NewInviteCache(cache GroupedCache[fluxer.ExtendedInvite]) InviteCache {
+	return &inviteCacheImpl{
+		cache: cache,
+	}
+}
+
+// InviteID returns the ID an invite with the given code is stored under in the GroupedCache of an InviteCache.
+func InviteID(code string) snowflake.ID {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(code))
+	return snowflake.ID(h.Sum64())
+}
+
+type inviteCacheImpl struct {
+	cache GroupedCache[fluxer.ExtendedInvite]
+}
+
+func (c *inviteCacheImpl) InviteCache() GroupedCache[fluxer.ExtendedInvite] {
+	return c.cache
+}
+
+func (c *inviteCacheImpl) Invite(guildID snowflake.ID, code string) (fluxer.ExtendedInvite, bool) {
+	invite, ok := c.cache.Get(guildID, InviteID(code))
+	if !ok || invite.Code != code {
+		return fluxer.ExtendedInvite{}, false
+	}
+	return invite, true
+}
+
+func (c *inviteCacheImpl) Invites(guildID snowflake.ID) iter.Seq[fluxer.ExtendedInvite] {
+	return c.cache.GroupAll(guildID)
+}
+
+func (c *inviteCacheImpl) InvitesAllLen() int {
+	return c.cache.Len()
+}
+
+func (c *inviteCacheImpl) InvitesLen(guildID snowflake.ID) int {
+	return c.cache.GroupLen(guildID)
+}
+
+func (c *inviteCacheImpl) AddInvite(guildID snowflake.ID, invite fluxer.ExtendedInvite) {
+	c.cache.Put(guildID, InviteID(invite.Code), invite)
+}
+
+func (c *inviteCacheImpl) SetInvites(guildID snowflake.ID, invites []fluxer.ExtendedInvite) {
+	c.cache.GroupRemove(guildID)
+	for _, invite := range invites {
+		c.AddInvite(guildID, invite)
+	}
+}
+
+func (c *inviteCacheImpl) MergeInvites(guildID snowflake.ID, invites []fluxer.ExtendedInvite) {
+	for _, invite := range invites {
+		// uses only grow, so a cached invite with more uses came from a newer event
+		if cached, ok := c.Invite(guildID, invite.Code); ok && cached.Uses >= invite.Uses {
+			continue
+		}
+		c.AddInvite(guildID, invite)
+	}
+}
+
+func (c *inviteCacheImpl) RemoveInvite(guildID snowflake.ID, code string) (fluxer.ExtendedInvite, bool) {
+	if _, ok := c.Invite(guildID, code); !ok {
+		return fluxer.ExtendedInvite{}, false
+	}
+	return c.cache.Remove(guildID, InviteID(code))
+}
+
+func (c *inviteCacheImpl) RemoveInvitesByGuildID(guildID snowflake.ID) {
+	c.cache.GroupRemove(guildID)
+}
+
 type EmojiCache interface {
 	EmojiCache() GroupedCache[fluxer.Emoji]
 
@@ -652,15 +805,27 @@ type Caches interface {
 	GuildScheduledEventCache
 	RoleCache
 	MemberCache
+	ThreadMemberCache
 	PresenceCache
 	VoiceStateCache
 	MessageCache
+	InviteCache
 	EmojiCache
 	StickerCache
 
 	// CacheFlags returns the current configured FLags of the caches.
 	CacheFlags() Flags
 
+	// ReconcileMode returns the current configured ReconcileMode of the caches.
+	ReconcileMode() ReconcileMode
+
+	// ReconcileGuild removes all cached channels, roles, emojis, stickers and voice states of the given guild which are not part of it anymore and returns them.
+	// This is used to get rid of stale entities after a failed resume or a guild outage.
+	ReconcileGuild(guild fluxer.GatewayGuild) ReconcileResult
+
+	// Stats returns the Stats of each cache. Computing the stats iterates parts of each cache, so this should not be called too often.
+	Stats() []Stats
+
 	// MemberPermissions returns the calculated permissions of the given member.
 	// This requires the FlagRoles to be set.
 	MemberPermissions(member fluxer.Member) fluxer.Permissions
@@ -710,9 +875,11 @@ func New(opts ...ConfigOpt) Caches {
 		guildScheduledEventCache: cfg.GuildScheduledEventCache,
 		roleCache:                cfg.RoleCache,
 		memberCache:              cfg.MemberCache,
+		threadMemberCache:        cfg.ThreadMemberCache,
 		presenceCache:            cfg.PresenceCache,
 		voiceStateCache:          cfg.VoiceStateCache,
 		messageCache:             cfg.MessageCache,
+		inviteCache:              cfg.InviteCache,
 		emojiCache:               cfg.EmojiCache,
 		stickerCache:             cfg.StickerCache,
 	}
@@ -725,9 +892,11 @@ type (
 	guildScheduledEventCache = GuildScheduledEventCache
 	roleCache                = RoleCache
 	memberCache              = MemberCache
+	threadMemberCache        = ThreadMemberCache
 	presenceCache            = PresenceCache
 	voiceStateCache          = VoiceStateCache
 	messageCache             = MessageCache
+	inviteCache              = InviteCache
 	emojiCache               = EmojiCache
 	stickerCache             = StickerCache
 	selfUserCache            = SelfUserCache
@@ -741,9 +910,11 @@ type cachesImpl struct {
 	guildScheduledEventCache
 	roleCache
 	memberCache
+	threadMemberCache
 	presenceCache
 	voiceStateCache
 	messageCache
+	inviteCache
 	emojiCache
 	stickerCache
 	selfUserCache
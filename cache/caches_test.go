@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/fluxer"
+)
+
+func TestThreadMemberCache(t *testing.T) {
+	members := []fluxer.ThreadMember{
+		{ThreadID: 1, UserID: 10, GuildID: 100},
+		{ThreadID: 1, UserID: 11, GuildID: 100},
+		{ThreadID: 2, UserID: 10, GuildID: 100},
+		{ThreadID: 3, UserID: 10, GuildID: 200},
+	}
+
+	tests := []struct {
+		name     string
+		remove   func(caches Caches)
+		expected map[snowflake.ID]int
+	}{
+		{
+			name:     "add",
+			remove:   func(Caches) {},
+			expected: map[snowflake.ID]int{1: 2, 2: 1, 3: 1},
+		},
+		{
+			name: "remove",
+			remove: func(caches Caches) {
+				if member, ok := caches.RemoveThreadMember(1, 11); !ok || member.UserID != 11 {
+					t.Errorf("expected thread member 11 to be removed, got %v", member)
+				}
+				if _, ok := caches.RemoveThreadMember(1, 12); ok {
+					t.Error("expected unknown thread member to not be removed")
+				}
+			},
+			expected: map[snowflake.ID]int{1: 1, 2: 1, 3: 1},
+		},
+		{
+			name:     "remove by thread",
+			remove:   func(caches Caches) { caches.RemoveThreadMembersByThreadID(1) },
+			expected: map[snowflake.ID]int{1: 0, 2: 1, 3: 1},
+		},
+		{
+			name:     "remove by guild",
+			remove:   func(caches Caches) { caches.RemoveThreadMembersByGuildID(100) },
+			expected: map[snowflake.ID]int{1: 0, 2: 0, 3: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caches := New(WithCaches(FlagThreadMembers))
+			for _, member := range members {
+				caches.AddThreadMember(member)
+			}
+
+			tt.remove(caches)
+
+			var all int
+			for threadID, expected := range tt.expected {
+				if l := caches.ThreadMembersLen(threadID); l != expected {
+					t.Errorf("expected %d members in thread %d, got %d", expected, threadID, l)
+				}
+				all += expected
+			}
+			if l := caches.ThreadMembersAllLen(); l != all {
+				t.Errorf("expected %d thread members, got %d", all, l)
+			}
+		})
+	}
+
+	caches := New(WithCaches(FlagThreadMembers))
+	caches.AddThreadMember(members[0])
+	if member, ok := caches.ThreadMember(1, 10); !ok || member != members[0] {
+		t.Errorf("expected thread member %v, got %v", members[0], member)
+	}
+}
+
+func TestInviteCache_MergeInvites(t *testing.T) {
+	caches := New(WithCaches(FlagInvites), WithShardedCaches(4))
+	if _, ok := caches.InviteCache().(*statsGroupedCache[fluxer.ExtendedInvite]); !ok {
+		t.Fatalf("expected invite cache to count stats, got %T", caches.InviteCache())
+	}
+
+	invite := func(code string, uses int) fluxer.ExtendedInvite {
+		return fluxer.ExtendedInvite{Invite: fluxer.Invite{Code: code}, Uses: uses}
+	}
+	// newer state from INVITE_CREATE and a previous fetch
+	caches.AddInvite(1, invite("created", 0))
+	caches.AddInvite(1, invite("used", 5))
+
+	caches.MergeInvites(1, []fluxer.ExtendedInvite{invite("fetched", 1), invite("used", 3)})
+
+	expected := map[string]int{"created": 0, "used": 5, "fetched": 1}
+	if l := caches.InvitesLen(1); l != len(expected) {
+		t.Errorf("expected %d invites, got %d", len(expected), l)
+	}
+	for code, uses := range expected {
+		if cached, ok := caches.Invite(1, code); !ok || cached.Uses != uses {
+			t.Errorf("expected invite %s with %d uses, got %+v", code, uses, cached)
+		}
+	}
+
+	if _, ok := caches.RemoveInvite(1, "created"); !ok {
+		t.Error("expected invite to be removed")
+	}
+	if _, ok := caches.Invite(1, "created"); ok {
+		t.Error("expected removed invite to be gone")
+	}
+}
@@ -0,0 +1,34 @@
+// Package cachestats exposes the cache.Stats of a cache.Caches via expvar or a http.Handler.
+// It is a separate package, so importing the cache package does not register the expvar handler on the http.DefaultServeMux.
+package cachestats
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+
+	"github.com/fluxergo/fluxergo/cache"
+)
+
+// Publish publishes the cache.Stats of the given cache.Caches as expvar with the given name.
+// Like expvar.Publish, this panics if the name is already registered.
+func Publish(name string, caches cache.Caches) {
+	expvar.Publish(name, Var(caches))
+}
+
+// Var returns an expvar.Var which reports the cache.Stats of the given cache.Caches.
+func Var(caches cache.Caches) expvar.Var {
+	return expvar.Func(func() any {
+		return caches.Stats()
+	})
+}
+
+// Handler returns a http.Handler which writes the cache.Stats of the given cache.Caches as JSON.
+func Handler(caches cache.Caches) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(caches.Stats()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,331 @@
+package cache
+
+import (
+	"iter"
+	"sync"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// DefaultShardCount is the number of shards used by NewShardedCache and NewShardedGroupedCache when a non-positive shard count is passed.
+const DefaultShardCount = 32
+
+// shardIndex returns the shard an ID belongs to. The ID is mixed first as the lower bits of a snowflake are a per-process increment which would otherwise distribute poorly.
+func shardIndex(id snowflake.ID, shardCount int) int {
+	h := uint64(id) * 0x9E3779B97F4A7C15
+	return int((h >> 32) % uint64(shardCount))
+}
+
+var _ Cache[any] = (*ShardedCache[any])(nil)
+
+// NewShardedCache returns a new ShardedCache implementation which filter the entities after the gives Flags and Policy.
+// The entities are partitioned by their snowflake into shardCount shards each guarded by their own lock, which reduces lock contention when many goroutines write to the cache at once.
+// If shardCount is less than 1, DefaultShardCount is used.
+func NewShardedCache[T any](flags Flags, neededFlags Flags, policy Policy[T], shardCount int) Cache[T] {
+	if shardCount < 1 {
+		shardCount = DefaultShardCount
+	}
+	shards := make([]cacheShard[T], shardCount)
+	for i := range shards {
+		shards[i].cache = make(map[snowflake.ID]T)
+	}
+	return &ShardedCache[T]{
+		flags:       flags,
+		neededFlags: neededFlags,
+		policy:      policy,
+		shards:      shards,
+	}
+}
+
+type cacheShard[T any] struct {
+	mu    sync.RWMutex
+	cache map[snowflake.ID]T
+}
+
+// ShardedCache is a thread safe cache key value store which partitions its entities by snowflake into multiple independently locked shards.
+type ShardedCache[T any] struct {
+	flags       Flags
+	neededFlags Flags
+	policy      Policy[T]
+	shards      []cacheShard[T]
+}
+
+func (c *ShardedCache[T]) shard(id snowflake.ID) *cacheShard[T] {
+	return &c.shards[shardIndex(id, len(c.shards))]
+}
+
+// rLockAll read locks all shards in order. Writers only ever hold a single shard lock, so this can't deadlock.
+func (c *ShardedCache[T]) rLockAll() {
+	for i := range c.shards {
+		c.shards[i].mu.RLock()
+	}
+}
+
+func (c *ShardedCache[T]) rUnlockAll() {
+	for i := range c.shards {
+		c.shards[i].mu.RUnlock()
+	}
+}
+
+func (c *ShardedCache[T]) Get(id snowflake.ID) (T, bool) {
+	s := c.shard(id)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entity, ok := s.cache[id]
+	return entity, ok
+}
+
+func (c *ShardedCache[T]) Put(id snowflake.ID, entity T) {
+	if c.flags.Missing(c.neededFlags) {
+		return
+	}
+	if c.policy != nil && !c.policy(entity) {
+		return
+	}
+	s := c.shard(id)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache[id] = entity
+}
+
+func (c *ShardedCache[T]) Remove(id snowflake.ID) (T, bool) {
+	s := c.shard(id)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entity, ok := s.cache[id]
+	if ok {
+		delete(s.cache, id)
+	}
+	return entity, ok
+}
+
+func (c *ShardedCache[T]) RemoveIf(filterFunc FilterFunc[T]) {
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mu.Lock()
+		for id, entity := range s.cache {
+			if filterFunc(entity) {
+				delete(s.cache, id)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (c *ShardedCache[T]) Len() int {
+	c.rLockAll()
+	defer c.rUnlockAll()
+	var totalLen int
+	for i := range c.shards {
+		totalLen += len(c.shards[i].cache)
+	}
+	return totalLen
+}
+
+// All returns an [iter.Seq] of all entities in the cache.
+// All shards are read locked for the duration of the iteration, so it sees a consistent view of the cache like DefaultCache does.
+func (c *ShardedCache[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		c.rLockAll()
+		defer c.rUnlockAll()
+		for i := range c.shards {
+			for _, entity := range c.shards[i].cache {
+				if !yield(entity) {
+					return
+				}
+			}
+		}
+	}
+}
+
+var _ GroupedCache[any] = (*shardedGroupedCache[any])(nil)
+
+// NewShardedGroupedCache returns a new GroupedCache with the provided flags, neededFlags and policy which partitions its groups into shardCount independently locked shards.
+// All entities of a group live in the same shard, so group operations like GroupAll or GroupRemove only lock a single shard.
+// If shardCount is less than 1, DefaultShardCount is used.
+func NewShardedGroupedCache[T any](flags Flags, neededFlags Flags, policy Policy[T], shardCount int) GroupedCache[T] {
+	if shardCount < 1 {
+		shardCount = DefaultShardCount
+	}
+	shards := make([]groupedCacheShard[T], shardCount)
+	for i := range shards {
+		shards[i].cache = make(map[snowflake.ID]map[snowflake.ID]T)
+	}
+	return &shardedGroupedCache[T]{
+		flags:       flags,
+		neededFlags: neededFlags,
+		policy:      policy,
+		shards:      shards,
+	}
+}
+
+type groupedCacheShard[T any] struct {
+	mu    sync.RWMutex
+	cache map[snowflake.ID]map[snowflake.ID]T
+}
+
+type shardedGroupedCache[T any] struct {
+	flags       Flags
+	neededFlags Flags
+	policy      Policy[T]
+	shards      []groupedCacheShard[T]
+}
+
+func (c *shardedGroupedCache[T]) shard(groupID snowflake.ID) *groupedCacheShard[T] {
+	return &c.shards[shardIndex(groupID, len(c.shards))]
+}
+
+func (c *shardedGroupedCache[T]) rLockAll() {
+	for i := range c.shards {
+		c.shards[i].mu.RLock()
+	}
+}
+
+func (c *shardedGroupedCache[T]) rUnlockAll() {
+	for i := range c.shards {
+		c.shards[i].mu.RUnlock()
+	}
+}
+
+func (c *shardedGroupedCache[T]) Get(groupID snowflake.ID, id snowflake.ID) (T, bool) {
+	s := c.shard(groupID)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if groupEntities, ok := s.cache[groupID]; ok {
+		if entity, ok := groupEntities[id]; ok {
+			return entity, true
+		}
+	}
+
+	var entity T
+	return entity, false
+}
+
+func (c *shardedGroupedCache[T]) Put(groupID snowflake.ID, id snowflake.ID, entity T) {
+	if c.flags.Missing(c.neededFlags) {
+		return
+	}
+	if c.policy != nil && !c.policy(entity) {
+		return
+	}
+	s := c.shard(groupID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if groupEntities, ok := s.cache[groupID]; ok {
+		groupEntities[id] = entity
+	} else {
+		groupEntities = make(map[snowflake.ID]T)
+		groupEntities[id] = entity
+		s.cache[groupID] = groupEntities
+	}
+}
+
+func (c *shardedGroupedCache[T]) Remove(groupID snowflake.ID, id snowflake.ID) (entity T, ok bool) {
+	s := c.shard(groupID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if groupEntities, ok := s.cache[groupID]; ok {
+		if entity, ok := groupEntities[id]; ok {
+			delete(groupEntities, id)
+			return entity, ok
+		}
+	}
+	ok = false
+	return
+}
+
+func (c *shardedGroupedCache[T]) GroupRemove(groupID snowflake.ID) {
+	s := c.shard(groupID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.cache, groupID)
+}
+
+func (c *shardedGroupedCache[T]) RemoveIf(filterFunc GroupedFilterFunc[T]) {
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mu.Lock()
+		for groupID, groupEntities := range s.cache {
+			for id, entity := range groupEntities {
+				if filterFunc(groupID, entity) {
+					delete(groupEntities, id)
+				}
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (c *shardedGroupedCache[T]) GroupRemoveIf(groupID snowflake.ID, filterFunc GroupedFilterFunc[T]) {
+	s := c.shard(groupID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if groupEntities, ok := s.cache[groupID]; ok {
+		for id, entity := range groupEntities {
+			if filterFunc(groupID, entity) {
+				delete(groupEntities, id)
+			}
+		}
+	}
+}
+
+func (c *shardedGroupedCache[T]) Len() int {
+	c.rLockAll()
+	defer c.rUnlockAll()
+
+	var totalLen int
+	for i := range c.shards {
+		for _, groupEntities := range c.shards[i].cache {
+			totalLen += len(groupEntities)
+		}
+	}
+	return totalLen
+}
+
+func (c *shardedGroupedCache[T]) GroupLen(groupID snowflake.ID) int {
+	s := c.shard(groupID)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if groupEntities, ok := s.cache[groupID]; ok {
+		return len(groupEntities)
+	}
+	return 0
+}
+
+func (c *shardedGroupedCache[T]) All() iter.Seq2[snowflake.ID, T] {
+	return func(yield func(snowflake.ID, T) bool) {
+		c.rLockAll()
+		defer c.rUnlockAll()
+
+		for i := range c.shards {
+			for groupID, groupEntities := range c.shards[i].cache {
+				for _, entity := range groupEntities {
+					if !yield(groupID, entity) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+func (c *shardedGroupedCache[T]) GroupAll(groupID snowflake.ID) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s := c.shard(groupID)
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+
+		if groupEntities, ok := s.cache[groupID]; ok {
+			for _, entity := range groupEntities {
+				if !yield(entity) {
+					return
+				}
+			}
+		}
+	}
+}
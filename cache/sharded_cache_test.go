@@ -0,0 +1,173 @@
+package cache
+
+import (
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+func TestShardedCache(t *testing.T) {
+	c := NewShardedCache[int](FlagsAll, FlagGuilds, nil, 8)
+
+	for i := range 1000 {
+		c.Put(snowflake.ID(i), i)
+	}
+	if c.Len() != 1000 {
+		t.Fatalf("expected 1000 entities, got %d", c.Len())
+	}
+
+	if v, ok := c.Get(42); !ok || v != 42 {
+		t.Errorf("expected 42, got %d (%t)", v, ok)
+	}
+
+	if v, ok := c.Remove(42); !ok || v != 42 {
+		t.Errorf("expected to remove 42, got %d (%t)", v, ok)
+	}
+	if _, ok := c.Get(42); ok {
+		t.Error("expected 42 to be removed")
+	}
+
+	c.RemoveIf(func(v int) bool { return v%2 == 0 })
+	var count int
+	for v := range c.All() {
+		if v%2 == 0 {
+			t.Errorf("expected %d to be removed", v)
+		}
+		count++
+	}
+	if count != 500 || c.Len() != 500 {
+		t.Errorf("expected 500 entities, got %d (len %d)", count, c.Len())
+	}
+}
+
+func TestShardedCache_Policy(t *testing.T) {
+	c := NewShardedCache[int](FlagsAll, FlagGuilds, func(v int) bool { return v > 10 }, 4)
+	c.Put(1, 1)
+	c.Put(20, 20)
+	if c.Len() != 1 {
+		t.Errorf("expected 1 entity, got %d", c.Len())
+	}
+
+	c = NewShardedCache[int](FlagsNone, FlagGuilds, nil, 4)
+	c.Put(1, 1)
+	if c.Len() != 0 {
+		t.Errorf("expected 0 entities, got %d", c.Len())
+	}
+}
+
+func TestShardedGroupedCache(t *testing.T) {
+	c := NewShardedGroupedCache[int](FlagsAll, FlagMembers, nil, 8)
+
+	for groupID := range 10 {
+		for id := range 100 {
+			c.Put(snowflake.ID(groupID), snowflake.ID(id), groupID*1000+id)
+		}
+	}
+	if c.Len() != 1000 {
+		t.Fatalf("expected 1000 entities, got %d", c.Len())
+	}
+	if c.GroupLen(3) != 100 {
+		t.Errorf("expected 100 entities in group 3, got %d", c.GroupLen(3))
+	}
+
+	if v, ok := c.Get(3, 7); !ok || v != 3007 {
+		t.Errorf("expected 3007, got %d (%t)", v, ok)
+	}
+	if _, ok := c.Remove(3, 7); !ok {
+		t.Error("expected to remove 3/7")
+	}
+
+	c.GroupRemove(4)
+	if c.GroupLen(4) != 0 {
+		t.Errorf("expected group 4 to be empty, got %d", c.GroupLen(4))
+	}
+
+	c.GroupRemoveIf(5, func(_ snowflake.ID, v int) bool { return v%2 == 0 })
+	if c.GroupLen(5) != 50 {
+		t.Errorf("expected 50 entities in group 5, got %d", c.GroupLen(5))
+	}
+
+	c.RemoveIf(func(groupID snowflake.ID, _ int) bool { return groupID == 6 })
+	if c.GroupLen(6) != 0 {
+		t.Errorf("expected group 6 to be empty, got %d", c.GroupLen(6))
+	}
+
+	var count int
+	for groupID, v := range c.All() {
+		if v/1000 != int(groupID) {
+			t.Errorf("entity %d yielded with wrong group %d", v, groupID)
+		}
+		count++
+	}
+	if count != c.Len() {
+		t.Errorf("expected All to yield %d entities, got %d", c.Len(), count)
+	}
+
+	count = 0
+	for range c.GroupAll(3) {
+		count++
+	}
+	if count != 99 {
+		t.Errorf("expected 99 entities in group 3, got %d", count)
+	}
+}
+
+func benchmarkCacheContention(b *testing.B, c Cache[int]) {
+	for i := range 10_000 {
+		c.Put(snowflake.ID(i), i)
+	}
+	var n atomic.Uint64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			i := n.Add(1)
+			id := snowflake.ID(i % 10_000)
+			if i%4 == 0 {
+				c.Get(id)
+				continue
+			}
+			c.Put(id, int(i))
+		}
+	})
+}
+
+func benchmarkGroupedCacheContention(b *testing.B, c GroupedCache[int]) {
+	var n atomic.Uint64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			i := n.Add(1)
+			groupID := snowflake.ID(i % 500)
+			id := snowflake.ID(i % 10_000)
+			if i%4 == 0 {
+				c.Get(groupID, id)
+				continue
+			}
+			c.Put(groupID, id, int(i))
+		}
+	})
+}
+
+func BenchmarkCacheContention(b *testing.B) {
+	b.Run("default", func(b *testing.B) {
+		benchmarkCacheContention(b, NewCache[int](FlagsAll, FlagGuilds, nil))
+	})
+	for _, shards := range []int{8, 32, 128} {
+		b.Run("sharded-"+strconv.Itoa(shards), func(b *testing.B) {
+			benchmarkCacheContention(b, NewShardedCache[int](FlagsAll, FlagGuilds, nil, shards))
+		})
+	}
+}
+
+func BenchmarkGroupedCacheContention(b *testing.B) {
+	b.Run("default", func(b *testing.B) {
+		benchmarkGroupedCacheContention(b, NewGroupedCache[int](FlagsAll, FlagPresences, nil))
+	})
+	for _, shards := range []int{8, 32, 128} {
+		b.Run("sharded-"+strconv.Itoa(shards), func(b *testing.B) {
+			benchmarkGroupedCacheContention(b, NewShardedGroupedCache[int](FlagsAll, FlagPresences, nil, shards))
+		})
+	}
+}
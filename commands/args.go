@@ -0,0 +1,259 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/fluxer"
+	"github.com/fluxergo/fluxergo/rest"
+)
+
+var (
+	// ErrNotInGuild is returned by ArgType(s) which can only be parsed in a guild.
+	ErrNotInGuild = errors.New("only available in guilds")
+	// ErrNotFound is returned by ArgType(s) if the referenced entity could not be found.
+	ErrNotFound = errors.New("not found")
+)
+
+// Arg is an argument of a Command.
+type Arg struct {
+	// Name is used to get the parsed value from the Event and is shown in the help.
+	Name string
+	// Description is shown in the help.
+	Description string
+	// Type parses the argument. The default is ArgTypeString.
+	Type ArgType
+	// Optional Arg(s) can be omitted. Only the last Arg(s) can be optional.
+	Optional bool
+	// Rest consumes all remaining arguments joined by a space. Only the last Arg can be Rest.
+	Rest bool
+}
+
+// Usage returns the usage of the Arg, for example "<user>" or "[reason...]".
+func (a Arg) Usage() string {
+	name := a.Name
+	if a.Rest {
+		name += "..."
+	}
+	if a.Optional {
+		return "[" + name + "]"
+	}
+	return "<" + name + ">"
+}
+
+func (a Arg) argType() ArgType {
+	if a.Type == nil {
+		return ArgTypeString
+	}
+	return a.Type
+}
+
+// ArgError is returned if an Arg is missing or could not be parsed.
+type ArgError struct {
+	Arg   Arg
+	Value string
+	Err   error
+}
+
+func (e *ArgError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("missing argument %s", e.Arg.Usage())
+	}
+	return fmt.Sprintf("invalid argument %s %q: %s", e.Arg.Usage(), e.Value, e.Err)
+}
+
+func (e *ArgError) Unwrap() error {
+	return e.Err
+}
+
+// ArgType parses a raw argument into a typed value.
+type ArgType interface {
+	// Name returns a human-readable name of the type, for example "user".
+	Name() string
+	// Parse parses the raw argument.
+	Parse(e *Event, raw string) (any, error)
+}
+
+// NewArgType returns a new ArgType with the given name and parse function.
+func NewArgType[T any](name string, parse func(e *Event, raw string) (T, error)) ArgType {
+	return &argType[T]{name: name, parse: parse}
+}
+
+type argType[T any] struct {
+	name  string
+	parse func(e *Event, raw string) (T, error)
+}
+
+func (t *argType[T]) Name() string {
+	return t.name
+}
+
+func (t *argType[T]) Parse(e *Event, raw string) (any, error) {
+	return t.parse(e, raw)
+}
+
+var (
+	// ArgTypeString passes the argument as is.
+	ArgTypeString = NewArgType("text", func(_ *Event, raw string) (string, error) {
+		return raw, nil
+	})
+
+	// ArgTypeInt parses an int.
+	ArgTypeInt = NewArgType("number", func(_ *Event, raw string) (int, error) {
+		return strconv.Atoi(raw)
+	})
+
+	// ArgTypeFloat parses a float64.
+	ArgTypeFloat = NewArgType("decimal", func(_ *Event, raw string) (float64, error) {
+		return strconv.ParseFloat(raw, 64)
+	})
+
+	// ArgTypeBool parses a bool. Besides the values accepted by strconv.ParseBool, "yes", "no", "on" and "off" are accepted.
+	ArgTypeBool = NewArgType("yes/no", func(_ *Event, raw string) (bool, error) {
+		switch strings.ToLower(raw) {
+		case "yes", "y", "on":
+			return true, nil
+		case "no", "n", "off":
+			return false, nil
+		}
+		return strconv.ParseBool(raw)
+	})
+
+	// ArgTypeSnowflake parses a snowflake.ID. Mentions of users, roles and channels are accepted as well.
+	ArgTypeSnowflake = NewArgType("id", func(_ *Event, raw string) (snowflake.ID, error) {
+		return parseMentionOrID(raw, fluxer.MentionTypeUser, fluxer.MentionTypeRole, fluxer.MentionTypeChannel)
+	})
+
+	// ArgTypeDuration parses a time.Duration like "1h30m". Additionally, "d" and "w" are accepted as days and weeks.
+	ArgTypeDuration = NewArgType("duration", func(_ *Event, raw string) (time.Duration, error) {
+		return ParseDuration(raw)
+	})
+
+	// ArgTypeUser parses a user mention or ID into a fluxer.User.
+	// The user is looked up in the mentions of the message, the member cache and finally via rest.
+	ArgTypeUser = NewArgType("user", func(e *Event, raw string) (fluxer.User, error) {
+		userID, err := parseMentionOrID(raw, fluxer.MentionTypeUser)
+		if err != nil {
+			return fluxer.User{}, err
+		}
+		for _, user := range e.Message.Mentions {
+			if user.ID == userID {
+				return user, nil
+			}
+		}
+		if e.GuildID != nil {
+			if member, ok := e.Client().Caches.Member(*e.GuildID, userID); ok {
+				return member.User, nil
+			}
+		}
+		user, err := e.Client().Rest.GetUser(userID, rest.WithCtx(e.Context()))
+		if err != nil {
+			return fluxer.User{}, fmt.Errorf("%w: %w", ErrNotFound, err)
+		}
+		return *user, nil
+	})
+
+	// ArgTypeMember parses a user mention or ID into a fluxer.Member of the guild. It is looked up in the member cache and then via rest.
+	ArgTypeMember = NewArgType("member", func(e *Event, raw string) (fluxer.Member, error) {
+		if e.GuildID == nil {
+			return fluxer.Member{}, ErrNotInGuild
+		}
+		userID, err := parseMentionOrID(raw, fluxer.MentionTypeUser)
+		if err != nil {
+			return fluxer.Member{}, err
+		}
+		return e.member(userID)
+	})
+
+	// ArgTypeChannel parses a channel mention or ID into a fluxer.GuildChannel of the guild using the channel cache.
+	ArgTypeChannel = NewArgType("channel", func(e *Event, raw string) (fluxer.GuildChannel, error) {
+		if e.GuildID == nil {
+			return nil, ErrNotInGuild
+		}
+		channelID, err := parseMentionOrID(raw, fluxer.MentionTypeChannel)
+		if err != nil {
+			return nil, err
+		}
+		channel, ok := e.Client().Caches.Channel(channelID)
+		if !ok || channel.GuildID() != *e.GuildID {
+			return nil, ErrNotFound
+		}
+		return channel, nil
+	})
+
+	// ArgTypeRole parses a role mention, ID or name into a fluxer.Role of the guild using the role cache.
+	ArgTypeRole = NewArgType("role", func(e *Event, raw string) (fluxer.Role, error) {
+		if e.GuildID == nil {
+			return fluxer.Role{}, ErrNotInGuild
+		}
+		if roleID, err := parseMentionOrID(raw, fluxer.MentionTypeRole); err == nil {
+			if role, ok := e.Client().Caches.Role(*e.GuildID, roleID); ok {
+				return role, nil
+			}
+			return fluxer.Role{}, ErrNotFound
+		}
+		for role := range e.Client().Caches.Roles(*e.GuildID) {
+			if strings.EqualFold(role.Name, raw) {
+				return role, nil
+			}
+		}
+		return fluxer.Role{}, ErrNotFound
+	})
+)
+
+// parseMentionOrID parses a raw snowflake.ID or a mention of one of the given types.
+func parseMentionOrID(raw string, mentionTypes ...fluxer.MentionType) (snowflake.ID, error) {
+	for _, mentionType := range mentionTypes {
+		if matches := mentionType.FindStringSubmatch(raw); len(matches) == 2 && matches[0] == raw {
+			return snowflake.Parse(matches[1])
+		}
+	}
+	return snowflake.Parse(raw)
+}
+
+// ParseDuration parses a duration like time.ParseDuration, but additionally accepts "d" for days and "w" for weeks,
+// which have to come before smaller units, for example "1w2d3h".
+func ParseDuration(raw string) (time.Duration, error) {
+	var total time.Duration
+	remaining := raw
+	for remaining != "" {
+		i := strings.IndexAny(remaining, "dw")
+		if i == -1 {
+			break
+		}
+		// only handle the unit if it is preceded by a number and not part of a longer unit
+		start := i
+		for start > 0 && (remaining[start-1] >= '0' && remaining[start-1] <= '9' || remaining[start-1] == '.') {
+			start--
+		}
+		if start == i || start != 0 {
+			break
+		}
+		n, err := strconv.ParseFloat(remaining[:i], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", raw)
+		}
+		unit := 24 * time.Hour
+		if remaining[i] == 'w' {
+			unit *= 7
+		}
+		total += time.Duration(n * float64(unit))
+		remaining = remaining[i+1:]
+	}
+	if remaining == "" {
+		if total == 0 {
+			return 0, fmt.Errorf("invalid duration %q", raw)
+		}
+		return total, nil
+	}
+	d, err := time.ParseDuration(remaining)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", raw)
+	}
+	return total + d, nil
+}
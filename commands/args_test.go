@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"90s":    90 * time.Second,
+		"1h30m":  90 * time.Minute,
+		"2d":     48 * time.Hour,
+		"1w1d2h": 8*24*time.Hour + 2*time.Hour,
+	}
+	for input, want := range tests {
+		if got, err := ParseDuration(input); err != nil || got != want {
+			t.Errorf("ParseDuration(%q) = %s, %v, want %s", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "d", "abc", "2h1d"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("ParseDuration(%q) expected error", input)
+		}
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	args := []Arg{
+		{Name: "target", Type: ArgTypeSnowflake},
+		{Name: "count", Type: ArgTypeInt, Optional: true},
+		{Name: "reason", Optional: true, Rest: true},
+	}
+
+	values, err := parseArgs(nil, args, []string{"<@123>", "5", "being", "rude"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["target"] != snowflake.ID(123) || values["count"] != 5 || values["reason"] != "being rude" {
+		t.Errorf("unexpected values %v", values)
+	}
+
+	values, err = parseArgs(nil, args, []string{"<#123>"})
+	if err != nil || len(values) != 1 {
+		t.Errorf("expected optional args to be omitted, got %v, %v", values, err)
+	}
+
+	var argErr *ArgError
+	if _, err = parseArgs(nil, args, nil); !errors.As(err, &argErr) || argErr.Arg.Name != "target" {
+		t.Errorf("expected missing target error, got %v", err)
+	}
+	if _, err = parseArgs(nil, args, []string{"1", "five"}); !errors.As(err, &argErr) || argErr.Arg.Name != "count" {
+		t.Errorf("expected invalid count error, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"strings"
+	"time"
+
+	"github.com/fluxergo/fluxergo/fluxer"
+)
+
+// HandlerFunc handles the invocation of a Command. Returned errors are passed to the ErrorHandlerFunc of the Router.
+type HandlerFunc func(e *Event) error
+
+// Command is a prefix command which can have Arg(s) and nested Subcommands.
+type Command struct {
+	// Name is the name used to invoke the Command.
+	Name string
+	// Aliases are alternative names used to invoke the Command.
+	Aliases []string
+	// Description is shown in the help.
+	Description string
+	// Args are parsed in order from the arguments following the Command name.
+	Args []Arg
+	// Subcommands are matched before the Args are parsed.
+	Subcommands []*Command
+	// Permissions are required by the author in the channel the Command is invoked in. This implies GuildOnly.
+	Permissions fluxer.Permissions
+	// BotPermissions are required by the bot in the channel the Command is invoked in. This implies GuildOnly.
+	BotPermissions fluxer.Permissions
+	// GuildOnly prevents the Command from being invoked in direct messages.
+	GuildOnly bool
+	// Cooldown limits how often the Command can be invoked.
+	Cooldown *Cooldown
+	// Hidden hides the Command from the help.
+	Hidden bool
+	// Handler is called when the Command is invoked. Commands without a Handler show their help.
+	Handler HandlerFunc
+}
+
+// Matches returns whether the given name matches the Command's Name or one of its Aliases, ignoring case.
+func (c *Command) Matches(name string) bool {
+	if strings.EqualFold(c.Name, name) {
+		return true
+	}
+	for _, alias := range c.Aliases {
+		if strings.EqualFold(alias, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Subcommand returns the Subcommand matching the given name.
+func (c *Command) Subcommand(name string) (*Command, bool) {
+	return findCommand(c.Subcommands, name)
+}
+
+// Usage returns the usage of the Command's Args, for example "<user> [reason...]".
+func (c *Command) Usage() string {
+	usages := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		usages[i] = arg.Usage()
+	}
+	return strings.Join(usages, " ")
+}
+
+func (c *Command) guildOnly() bool {
+	return c.GuildOnly || c.Permissions != 0 || c.BotPermissions != 0
+}
+
+func findCommand(commands []*Command, name string) (*Command, bool) {
+	for _, command := range commands {
+		if command.Matches(name) {
+			return command, true
+		}
+	}
+	return nil, false
+}
+
+// CooldownScope defines what a Cooldown applies to.
+type CooldownScope int
+
+const (
+	// CooldownScopeUser applies the Cooldown to each user.
+	CooldownScopeUser CooldownScope = iota
+	// CooldownScopeChannel applies the Cooldown to each channel.
+	CooldownScopeChannel
+	// CooldownScopeGuild applies the Cooldown to each guild, or each channel in direct messages.
+	CooldownScopeGuild
+)
+
+// Cooldown limits how often a Command can be invoked within its Scope.
+type Cooldown struct {
+	Scope    CooldownScope
+	Duration time.Duration
+}
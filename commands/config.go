@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"log/slog"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+func defaultConfig() config {
+	return config{
+		Logger:        slog.Default(),
+		Prefixes:      []string{"!"},
+		MentionPrefix: true,
+		IgnoreBots:    true,
+		HelpCommand:   "help",
+	}
+}
+
+type config struct {
+	Logger        *slog.Logger
+	Prefixes      []string
+	PrefixFunc    PrefixFunc
+	MentionPrefix bool
+	IgnoreBots    bool
+	HelpCommand   string
+	ErrorHandler  ErrorHandlerFunc
+}
+
+// ConfigOpt is a functional option for configuring a Router.
+type ConfigOpt func(config *config)
+
+func (c *config) apply(opts []ConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.Logger = c.Logger.With(slog.String("name", "commands"))
+}
+
+// PrefixFunc returns the prefixes of a guild. If false is returned the default prefixes are used.
+type PrefixFunc func(guildID snowflake.ID) ([]string, bool)
+
+// ErrorHandlerFunc is called with errors returned by a HandlerFunc or occurred while parsing a Command.
+type ErrorHandlerFunc func(e *Event, err error)
+
+// WithLogger overrides the default Logger in the config.
+func WithLogger(logger *slog.Logger) ConfigOpt {
+	return func(config *config) {
+		config.Logger = logger
+	}
+}
+
+// WithPrefixes sets the default prefixes. The default is "!".
+func WithPrefixes(prefixes ...string) ConfigOpt {
+	return func(config *config) {
+		config.Prefixes = prefixes
+	}
+}
+
+// WithPrefixFunc sets a PrefixFunc to look up per-guild prefixes.
+func WithPrefixFunc(prefixFunc PrefixFunc) ConfigOpt {
+	return func(config *config) {
+		config.PrefixFunc = prefixFunc
+	}
+}
+
+// WithMentionPrefix sets whether mentioning the bot can be used as prefix. This is enabled by default.
+func WithMentionPrefix(mentionPrefix bool) ConfigOpt {
+	return func(config *config) {
+		config.MentionPrefix = mentionPrefix
+	}
+}
+
+// WithIgnoreBots sets whether messages of bots are ignored. This is enabled by default.
+func WithIgnoreBots(ignoreBots bool) ConfigOpt {
+	return func(config *config) {
+		config.IgnoreBots = ignoreBots
+	}
+}
+
+// WithHelpCommand sets the name of the auto-generated help Command. An empty name disables it. The default is "help".
+func WithHelpCommand(name string) ConfigOpt {
+	return func(config *config) {
+		config.HelpCommand = name
+	}
+}
+
+// WithErrorHandler overrides the default ErrorHandlerFunc, which replies with user errors and logs all other errors.
+func WithErrorHandler(errorHandler ErrorHandlerFunc) ConfigOpt {
+	return func(config *config) {
+		config.ErrorHandler = errorHandler
+	}
+}
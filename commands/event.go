@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/events"
+	"github.com/fluxergo/fluxergo/fluxer"
+	"github.com/fluxergo/fluxergo/rest"
+)
+
+// Event is passed to the HandlerFunc of an invoked Command.
+type Event struct {
+	*events.MessageCreate
+	// Router is the Router which invoked the Command.
+	Router Router
+	// Prefix is the prefix the Command was invoked with.
+	Prefix string
+	// Command is the invoked Command.
+	Command *Command
+	// Path contains the names of the invoked Command and its parents, for example ["config", "set"].
+	Path []string
+	// RawArgs contains the arguments following the Command name.
+	RawArgs []string
+
+	args map[string]any
+}
+
+// Arg returns the parsed value of the Arg with the given name.
+func (e *Event) Arg(name string) (any, bool) {
+	v, ok := e.args[name]
+	return v, ok
+}
+
+// Has returns whether the Arg with the given name was passed.
+func (e *Event) Has(name string) bool {
+	_, ok := e.args[name]
+	return ok
+}
+
+// ArgValue returns the parsed value of the Arg with the given name or the zero value of T if it was not passed or has a different type.
+func ArgValue[T any](e *Event, name string) T {
+	v, _ := e.args[name].(T)
+	return v
+}
+
+// String returns the value of an ArgTypeString Arg.
+func (e *Event) String(name string) string {
+	return ArgValue[string](e, name)
+}
+
+// Int returns the value of an ArgTypeInt Arg.
+func (e *Event) Int(name string) int {
+	return ArgValue[int](e, name)
+}
+
+// Float returns the value of an ArgTypeFloat Arg.
+func (e *Event) Float(name string) float64 {
+	return ArgValue[float64](e, name)
+}
+
+// Bool returns the value of an ArgTypeBool Arg.
+func (e *Event) Bool(name string) bool {
+	return ArgValue[bool](e, name)
+}
+
+// Snowflake returns the value of an ArgTypeSnowflake Arg.
+func (e *Event) Snowflake(name string) snowflake.ID {
+	return ArgValue[snowflake.ID](e, name)
+}
+
+// Duration returns the value of an ArgTypeDuration Arg.
+func (e *Event) Duration(name string) time.Duration {
+	return ArgValue[time.Duration](e, name)
+}
+
+// User returns the value of an ArgTypeUser Arg.
+func (e *Event) User(name string) fluxer.User {
+	return ArgValue[fluxer.User](e, name)
+}
+
+// Member returns the value of an ArgTypeMember Arg.
+func (e *Event) Member(name string) fluxer.Member {
+	return ArgValue[fluxer.Member](e, name)
+}
+
+// Channel returns the value of an ArgTypeChannel Arg.
+func (e *Event) Channel(name string) fluxer.GuildChannel {
+	return ArgValue[fluxer.GuildChannel](e, name)
+}
+
+// Role returns the value of an ArgTypeRole Arg.
+func (e *Event) Role(name string) fluxer.Role {
+	return ArgValue[fluxer.Role](e, name)
+}
+
+// Reply sends a message replying to the message which invoked the Command.
+func (e *Event) Reply(content string, a ...any) (*fluxer.Message, error) {
+	if len(a) > 0 {
+		content = fmt.Sprintf(content, a...)
+	}
+	messageID := e.MessageID
+	return e.Client().Rest.CreateMessage(e.ChannelID, fluxer.MessageCreate{
+		Content: content,
+		MessageReference: &fluxer.MessageReference{
+			MessageID:       &messageID,
+			FailIfNotExists: false,
+		},
+		AllowedMentions: &fluxer.AllowedMentions{RepliedUser: false},
+	}, rest.WithCtx(e.Context()))
+}
+
+// member returns the fluxer.Member of the user in the guild of the Event from the cache or via rest.
+func (e *Event) member(userID snowflake.ID) (fluxer.Member, error) {
+	if member, ok := e.Client().Caches.Member(*e.GuildID, userID); ok {
+		return member, nil
+	}
+	member, err := e.Client().Rest.GetMember(*e.GuildID, userID, rest.WithCtx(e.Context()))
+	if err != nil {
+		return fluxer.Member{}, fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	member.GuildID = *e.GuildID
+	return *member, nil
+}
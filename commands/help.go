@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"strings"
+)
+
+func (r *routerImpl) Help(prefix string, path ...string) string {
+	commands := r.Commands()
+	if len(path) == 0 {
+		return commandList(prefix, nil, commands, r.config.HelpCommand)
+	}
+
+	command, ok := findCommand(commands, path[0])
+	if !ok || command.Hidden {
+		return "Unknown command `" + path[0] + "`."
+	}
+	names := []string{command.Name}
+	for _, name := range path[1:] {
+		subcommand, ok := command.Subcommand(name)
+		if !ok || subcommand.Hidden {
+			break
+		}
+		command = subcommand
+		names = append(names, command.Name)
+	}
+	return commandHelp(prefix, names, command)
+}
+
+// commandList returns a list of all visible Command(s) with their usage and description.
+func commandList(prefix string, parents []string, commands []*Command, helpCommand string) string {
+	var sb strings.Builder
+	if parents == nil {
+		sb.WriteString("**Commands**\n")
+	} else {
+		sb.WriteString("**Subcommands**\n")
+	}
+	for _, command := range commands {
+		if command.Hidden {
+			continue
+		}
+		sb.WriteString("`")
+		sb.WriteString(invocation(prefix, append(parents, command.Name), command))
+		sb.WriteString("`")
+		if command.Description != "" {
+			sb.WriteString(" - ")
+			sb.WriteString(command.Description)
+		}
+		sb.WriteString("\n")
+	}
+	if helpCommand != "" {
+		sb.WriteString("\nUse `")
+		sb.WriteString(prefix)
+		sb.WriteString(helpCommand)
+		sb.WriteString(" <command>` for more information about a command.")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// commandHelp returns the detailed help of a Command.
+func commandHelp(prefix string, names []string, command *Command) string {
+	var sb strings.Builder
+	sb.WriteString("`")
+	sb.WriteString(invocation(prefix, names, command))
+	sb.WriteString("`\n")
+	if command.Description != "" {
+		sb.WriteString(command.Description)
+		sb.WriteString("\n")
+	}
+	if len(command.Aliases) > 0 {
+		sb.WriteString("**Aliases:** ")
+		sb.WriteString(strings.Join(command.Aliases, ", "))
+		sb.WriteString("\n")
+	}
+	if len(command.Args) > 0 {
+		sb.WriteString("**Arguments**\n")
+		for _, arg := range command.Args {
+			sb.WriteString("`")
+			sb.WriteString(arg.Usage())
+			sb.WriteString("` (")
+			sb.WriteString(arg.argType().Name())
+			sb.WriteString(")")
+			if arg.Description != "" {
+				sb.WriteString(" - ")
+				sb.WriteString(arg.Description)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	if command.Cooldown != nil && command.Cooldown.Duration > 0 {
+		sb.WriteString("**Cooldown:** ")
+		sb.WriteString(command.Cooldown.Duration.String())
+		sb.WriteString("\n")
+	}
+	if len(command.Subcommands) > 0 {
+		sb.WriteString(commandList(prefix, names, command.Subcommands, ""))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func invocation(prefix string, names []string, command *Command) string {
+	s := prefix + strings.Join(names, " ")
+	if usage := command.Usage(); usage != "" {
+		s += " " + usage
+	}
+	return s
+}
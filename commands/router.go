@@ -0,0 +1,331 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/bot"
+	"github.com/fluxergo/fluxergo/events"
+	"github.com/fluxergo/fluxergo/fluxer"
+)
+
+var (
+	// ErrGuildOnly is returned if a Command which is only available in guilds is invoked in direct messages.
+	ErrGuildOnly = errors.New("this command can only be used in servers")
+)
+
+// PermissionsError is returned if the author or the bot lacks permissions required by a Command.
+type PermissionsError struct {
+	Missing fluxer.Permissions
+	// Bot is true if the bot lacks the permissions.
+	Bot bool
+}
+
+func (e *PermissionsError) Error() string {
+	if e.Bot {
+		return fmt.Sprintf("I am missing the permissions %s", e.Missing)
+	}
+	return fmt.Sprintf("you are missing the permissions %s", e.Missing)
+}
+
+// CooldownError is returned if a Command is invoked while it is on Cooldown.
+type CooldownError struct {
+	Remaining time.Duration
+}
+
+func (e *CooldownError) Error() string {
+	return fmt.Sprintf("this command is on cooldown, try again in %s", e.Remaining.Round(time.Second))
+}
+
+// IsUserError returns whether the error is caused by the invocation of the user, like missing arguments or permissions.
+// These errors are meant to be shown to the user.
+func IsUserError(err error) bool {
+	var (
+		argErr         *ArgError
+		permissionsErr *PermissionsError
+		cooldownErr    *CooldownError
+	)
+	return errors.As(err, &argErr) || errors.As(err, &permissionsErr) || errors.As(err, &cooldownErr) ||
+		errors.Is(err, ErrGuildOnly) || errors.Is(err, ErrUnclosedQuote)
+}
+
+var _ Router = (*routerImpl)(nil)
+
+// New returns a new Router with the ConfigOpt(s) applied.
+// The Router needs to be added as bot.EventListener and handles events.MessageCreate.
+// Commands are handled in the goroutine dispatching the event, so consider enabling async events or an EventWorkerPool.
+func New(opts ...ConfigOpt) Router {
+	cfg := defaultConfig()
+	cfg.apply(opts)
+
+	r := &routerImpl{
+		config:    cfg,
+		cooldowns: make(map[cooldownKey]time.Time),
+	}
+	if r.config.ErrorHandler == nil {
+		r.config.ErrorHandler = r.defaultErrorHandler
+	}
+	return r
+}
+
+// Router parses messages and invokes the matching Command(s).
+type Router interface {
+	bot.EventListener
+
+	// AddCommands adds the given Command(s) to the Router.
+	AddCommands(commands ...*Command)
+
+	// Commands returns all Command(s) of the Router.
+	Commands() []*Command
+
+	// Help returns the help of all Command(s) or the Command at the given path using the given prefix.
+	Help(prefix string, path ...string) string
+}
+
+type cooldownKey struct {
+	command *Command
+	id      snowflake.ID
+}
+
+type routerImpl struct {
+	config config
+
+	mu       sync.RWMutex
+	commands []*Command
+
+	cooldownsMu sync.Mutex
+	cooldowns   map[cooldownKey]time.Time
+}
+
+func (r *routerImpl) AddCommands(commands ...*Command) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands = append(r.commands, commands...)
+}
+
+func (r *routerImpl) Commands() []*Command {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	commands := make([]*Command, len(r.commands))
+	copy(commands, r.commands)
+	return commands
+}
+
+func (r *routerImpl) OnEvent(event bot.Event) {
+	e, ok := event.(*events.MessageCreate)
+	if !ok {
+		return
+	}
+	if r.config.IgnoreBots && e.Message.Author.Bot {
+		return
+	}
+
+	prefix, content, ok := r.matchPrefix(e)
+	if !ok {
+		return
+	}
+	cmdEvent := &Event{
+		MessageCreate: e,
+		Router:        r,
+		Prefix:        prefix,
+	}
+
+	tokens, err := Tokenize(content)
+	if err != nil {
+		r.config.ErrorHandler(cmdEvent, err)
+		return
+	}
+	if len(tokens) == 0 {
+		return
+	}
+
+	if r.config.HelpCommand != "" && strings.EqualFold(tokens[0], r.config.HelpCommand) {
+		cmdEvent.Path = tokens[:1]
+		if _, err = cmdEvent.Reply("%s", r.Help(prefix, tokens[1:]...)); err != nil {
+			r.config.Logger.Error("failed to send help", slog.Any("err", err))
+		}
+		return
+	}
+
+	command, ok := findCommand(r.Commands(), tokens[0])
+	if !ok {
+		return
+	}
+	path := []string{command.Name}
+	tokens = tokens[1:]
+	for len(tokens) > 0 {
+		subcommand, ok := command.Subcommand(tokens[0])
+		if !ok {
+			break
+		}
+		command = subcommand
+		path = append(path, command.Name)
+		tokens = tokens[1:]
+	}
+	cmdEvent.Command = command
+	cmdEvent.Path = path
+	cmdEvent.RawArgs = tokens
+
+	if err = r.invoke(cmdEvent); err != nil {
+		r.config.ErrorHandler(cmdEvent, err)
+	}
+}
+
+// matchPrefix returns the prefix the message starts with and the content following it.
+func (r *routerImpl) matchPrefix(e *events.MessageCreate) (string, string, bool) {
+	content := e.Message.Content
+	if r.config.MentionPrefix {
+		if selfID := e.Client().ID(); selfID != 0 {
+			for _, mention := range []string{"<@" + selfID.String() + ">", "<@!" + selfID.String() + ">"} {
+				if strings.HasPrefix(content, mention) {
+					return mention + " ", strings.TrimPrefix(content, mention), true
+				}
+			}
+		}
+	}
+
+	prefixes := r.config.Prefixes
+	if e.GuildID != nil && r.config.PrefixFunc != nil {
+		if guildPrefixes, ok := r.config.PrefixFunc(*e.GuildID); ok {
+			prefixes = guildPrefixes
+		}
+	}
+	for _, prefix := range prefixes {
+		if prefix != "" && strings.HasPrefix(content, prefix) {
+			return prefix, strings.TrimPrefix(content, prefix), true
+		}
+	}
+	return "", "", false
+}
+
+func (r *routerImpl) invoke(e *Event) error {
+	command := e.Command
+	if command.guildOnly() && e.GuildID == nil {
+		return ErrGuildOnly
+	}
+	if command.Handler == nil {
+		_, err := e.Reply("%s", r.Help(e.Prefix, e.Path...))
+		return err
+	}
+	if err := r.checkPermissions(e); err != nil {
+		return err
+	}
+	args, err := parseArgs(e, command.Args, e.RawArgs)
+	if err != nil {
+		return err
+	}
+	e.args = args
+	if err = r.checkCooldown(e); err != nil {
+		return err
+	}
+	return command.Handler(e)
+}
+
+func (r *routerImpl) checkPermissions(e *Event) error {
+	command := e.Command
+	if command.Permissions == 0 && command.BotPermissions == 0 {
+		return nil
+	}
+	caches := e.Client().Caches
+	channel, ok := caches.Channel(e.ChannelID)
+	if !ok {
+		return fmt.Errorf("channel %s is not cached", e.ChannelID)
+	}
+
+	if command.Permissions != 0 {
+		member, err := e.member(e.Message.Author.ID)
+		if err != nil {
+			return err
+		}
+		if permissions := caches.MemberPermissionsInChannel(channel, member); !permissions.Has(command.Permissions) {
+			return &PermissionsError{Missing: command.Permissions.Remove(permissions)}
+		}
+	}
+	if command.BotPermissions != 0 {
+		selfMember, ok := caches.SelfMember(*e.GuildID)
+		if !ok {
+			return fmt.Errorf("self member of guild %s is not cached", *e.GuildID)
+		}
+		if permissions := caches.MemberPermissionsInChannel(channel, selfMember); !permissions.Has(command.BotPermissions) {
+			return &PermissionsError{Missing: command.BotPermissions.Remove(permissions), Bot: true}
+		}
+	}
+	return nil
+}
+
+func (r *routerImpl) checkCooldown(e *Event) error {
+	cooldown := e.Command.Cooldown
+	if cooldown == nil || cooldown.Duration <= 0 {
+		return nil
+	}
+	key := cooldownKey{command: e.Command}
+	switch cooldown.Scope {
+	case CooldownScopeUser:
+		key.id = e.Message.Author.ID
+	case CooldownScopeChannel:
+		key.id = e.ChannelID
+	case CooldownScopeGuild:
+		key.id = e.ChannelID
+		if e.GuildID != nil {
+			key.id = *e.GuildID
+		}
+	}
+
+	r.cooldownsMu.Lock()
+	defer r.cooldownsMu.Unlock()
+	now := time.Now()
+	if until, ok := r.cooldowns[key]; ok && now.Before(until) {
+		return &CooldownError{Remaining: until.Sub(now)}
+	}
+	if len(r.cooldowns) >= 1000 {
+		for k, until := range r.cooldowns {
+			if !now.Before(until) {
+				delete(r.cooldowns, k)
+			}
+		}
+	}
+	r.cooldowns[key] = now.Add(cooldown.Duration)
+	return nil
+}
+
+func (r *routerImpl) defaultErrorHandler(e *Event, err error) {
+	if IsUserError(err) {
+		if _, replyErr := e.Reply("%s", err); replyErr != nil {
+			r.config.Logger.Error("failed to reply with command error", slog.Any("err", replyErr))
+		}
+		return
+	}
+	r.config.Logger.Error("error while handling command", slog.String("command", strings.Join(e.Path, " ")), slog.Any("err", err))
+}
+
+// parseArgs parses the raw arguments using the given Arg(s).
+func parseArgs(e *Event, args []Arg, raw []string) (map[string]any, error) {
+	values := make(map[string]any, len(args))
+	for i, arg := range args {
+		if i >= len(raw) {
+			if arg.Optional {
+				break
+			}
+			return nil, &ArgError{Arg: arg}
+		}
+		value := raw[i]
+		if arg.Rest {
+			value = strings.Join(raw[i:], " ")
+		}
+		parsed, err := arg.argType().Parse(e, value)
+		if err != nil {
+			return nil, &ArgError{Arg: arg, Value: value, Err: err}
+		}
+		values[arg.Name] = parsed
+		if arg.Rest {
+			break
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/events"
+	"github.com/fluxergo/fluxergo/fluxer"
+)
+
+func testRouter() *routerImpl {
+	r := New().(*routerImpl)
+	r.AddCommands(
+		&Command{
+			Name:        "config",
+			Description: "Manage the config",
+			Subcommands: []*Command{
+				{
+					Name:        "set",
+					Aliases:     []string{"s"},
+					Description: "Set a value",
+					Args:        []Arg{{Name: "key"}, {Name: "value", Rest: true}},
+					Handler:     func(e *Event) error { return nil },
+				},
+			},
+		},
+		&Command{Name: "secret", Hidden: true, Handler: func(e *Event) error { return nil }},
+	)
+	return r
+}
+
+func TestRouter_Help(t *testing.T) {
+	r := testRouter()
+
+	help := r.Help("!")
+	if !strings.Contains(help, "`!config` - Manage the config") || strings.Contains(help, "secret") {
+		t.Errorf("unexpected help:\n%s", help)
+	}
+
+	help = r.Help("!", "config", "s")
+	if !strings.HasPrefix(help, "`!config set <key> <value...>`") || !strings.Contains(help, "**Aliases:** s") {
+		t.Errorf("unexpected subcommand help:\n%s", help)
+	}
+}
+
+func TestRouter_Cooldown(t *testing.T) {
+	r := testRouter()
+	command := &Command{Name: "daily", Cooldown: &Cooldown{Scope: CooldownScopeUser, Duration: time.Hour}}
+
+	newEvent := func(userID snowflake.ID) *Event {
+		return &Event{
+			MessageCreate: &events.MessageCreate{GenericMessage: &events.GenericMessage{
+				Message: fluxer.Message{Author: fluxer.User{ID: userID}},
+			}},
+			Command: command,
+		}
+	}
+
+	if err := r.checkCooldown(newEvent(1)); err != nil {
+		t.Fatal(err)
+	}
+	var cooldownErr *CooldownError
+	if err := r.checkCooldown(newEvent(1)); !errors.As(err, &cooldownErr) || cooldownErr.Remaining <= 0 {
+		t.Errorf("expected cooldown error, got %v", err)
+	}
+	if err := r.checkCooldown(newEvent(2)); err != nil {
+		t.Errorf("expected cooldown to be per user, got %v", err)
+	}
+}
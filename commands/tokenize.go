@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// ErrUnclosedQuote is returned if an argument has an opening quote without a closing one.
+var ErrUnclosedQuote = errors.New("unclosed quote")
+
+// Tokenize splits the input into arguments separated by whitespace.
+// Arguments can be quoted with " or ' to contain whitespace, and \ escapes the next character.
+func Tokenize(input string) ([]string, error) {
+	var (
+		tokens  []string
+		current strings.Builder
+		quote   rune
+		escaped bool
+		inToken bool
+	)
+	for _, r := range input {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+			inToken = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+				continue
+			}
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inToken = true
+		case unicode.IsSpace(r):
+			if inToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				inToken = false
+			}
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+	if quote != 0 {
+		return nil, ErrUnclosedQuote
+	}
+	if inToken {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
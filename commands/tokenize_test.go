@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+		err   error
+	}{
+		{input: "ban  @user spam", want: []string{"ban", "@user", "spam"}},
+		{input: `say "hello world" 'it''s'`, want: []string{"say", "hello world", "its"}},
+		{input: `say hello\ world \"quoted\"`, want: []string{"say", "hello world", `"quoted"`}},
+		{input: `say ""`, want: []string{"say", ""}},
+		{input: `say "unclosed`, err: ErrUnclosedQuote},
+		{input: "   ", want: nil},
+	}
+	for _, tt := range tests {
+		got, err := Tokenize(tt.input)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Tokenize(%q) error = %v, want %v", tt.input, err, tt.err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package events
+
+import (
+	"errors"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/bot"
+)
+
+// AwaitReply waits for the next message of the user in the channel.
+// Use bot.WithCollectorTimeout or bot.WithCollectorContext to limit how long to wait.
+func AwaitReply(client *bot.Client, channelID snowflake.ID, userID snowflake.ID, opts ...bot.CollectorConfigOpt) (*MessageCreate, error) {
+	return bot.NewCollector(client, func(e *MessageCreate) bool {
+		return e.ChannelID == channelID && e.Message.Author.ID == userID
+	}, append(opts, bot.WithCollectorMaxCount(1))...).Next()
+}
+
+// CollectReactions collects all reactions added to the message within the given duration.
+// Reactions are collected until the duration passed, the maximum count set via bot.WithCollectorMaxCount is reached or the context is done.
+func CollectReactions(client *bot.Client, messageID snowflake.ID, duration time.Duration, opts ...bot.CollectorConfigOpt) ([]*MessageReactionAdd, error) {
+	reactions, err := bot.NewCollector(client, func(e *MessageReactionAdd) bool {
+		return e.MessageID == messageID
+	}, append([]bot.CollectorConfigOpt{bot.WithCollectorBufferSize(100)}, append(opts, bot.WithCollectorTimeout(duration))...)...).Collect()
+	if errors.Is(err, bot.ErrCollectorTimeout) {
+		err = nil
+	}
+	return reactions, err
+}
+
+// AwaitVoiceJoin waits for the next member joining the voice channel.
+// Use bot.WithCollectorTimeout or bot.WithCollectorContext to limit how long to wait.
+func AwaitVoiceJoin(client *bot.Client, channelID snowflake.ID, opts ...bot.CollectorConfigOpt) (*GuildVoiceJoin, error) {
+	return bot.NewCollector(client, func(e *GuildVoiceJoin) bool {
+		return e.VoiceState.ChannelID != nil && *e.VoiceState.ChannelID == channelID
+	}, append(opts, bot.WithCollectorMaxCount(1))...).Next()
+}
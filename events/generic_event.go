@@ -1,6 +1,8 @@
 package events
 
 import (
+	"context"
+
 	"github.com/fluxergo/fluxergo/bot"
 )
 
@@ -30,3 +32,12 @@ func (e *GenericEvent) SequenceNumber() int {
 func (e *GenericEvent) ShardID() int {
 	return e.shardID
 }
+
+// Context returns the context.Context of the Client that dispatched the event.
+// It is cancelled once the Client is closed, so it should be passed to long-running calls like rest.WithCtx.
+func (e *GenericEvent) Context() context.Context {
+	if e.client == nil {
+		return context.Background()
+	}
+	return e.client.Context()
+}
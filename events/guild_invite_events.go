@@ -46,3 +46,11 @@ func (e *InviteDelete) Guild() (fluxer.Guild, bool) {
 	}
 	return e.Client().Caches.Guild(*e.GuildID)
 }
+
+// GuildMemberJoinedViaInvite is dispatched by the listener returned from NewInviteTracker after a fluxer.Member joined a fluxer.Guild.
+// Invite is the fluxer.ExtendedInvite which was most likely used to join, determined by comparing the invite uses before and after the join.
+type GuildMemberJoinedViaInvite struct {
+	*GenericGuildMember
+	Invite  fluxer.ExtendedInvite
+	Inviter *fluxer.User
+}
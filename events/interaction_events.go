@@ -0,0 +1,144 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/fluxer"
+	"github.com/fluxergo/fluxergo/rest"
+)
+
+// InteractionTokenLifetime is the duration an interaction token can be used for follow-up messages.
+const InteractionTokenLifetime = 15 * time.Minute
+
+// InteractionCreate indicates that a fluxer.Interaction was created.
+// It has to be responded to within 3 seconds using Respond or one of its helpers.
+type InteractionCreate struct {
+	*GenericEvent
+	fluxer.Interaction
+
+	mu           sync.Mutex
+	acknowledged bool
+}
+
+// Guild returns the fluxer.Guild the interaction happened in.
+// This will only return cached guilds!
+func (e *InteractionCreate) Guild() (fluxer.Guild, bool) {
+	if e.GuildID == nil {
+		return fluxer.Guild{}, false
+	}
+	return e.Client().Caches.Guild(*e.GuildID)
+}
+
+// Acknowledged returns whether the interaction was already responded to.
+func (e *InteractionCreate) Acknowledged() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.acknowledged
+}
+
+// Respond responds to the interaction with the given fluxer.InteractionResponseType and fluxer.InteractionCallbackData.
+// It returns fluxer.ErrInteractionAlreadyReplied if the interaction was already responded to.
+func (e *InteractionCreate) Respond(responseType fluxer.InteractionResponseType, data fluxer.InteractionCallbackData, opts ...rest.RequestOpt) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.acknowledged {
+		return fluxer.ErrInteractionAlreadyReplied
+	}
+	if err := e.Client().Rest.CreateInteractionResponse(e.ID, e.Token, fluxer.InteractionResponse{Type: responseType, Data: data}, e.requestOpts(opts)...); err != nil {
+		return err
+	}
+	e.acknowledged = true
+	return nil
+}
+
+// CreateMessage responds to the interaction with a new message.
+func (e *InteractionCreate) CreateMessage(messageCreate fluxer.MessageCreate, opts ...rest.RequestOpt) error {
+	return e.Respond(fluxer.InteractionResponseTypeCreateMessage, messageCreate, opts...)
+}
+
+// DeferReply acknowledges the interaction and shows a loading state. The response can be sent later using UpdateResponse.
+func (e *InteractionCreate) DeferReply(ephemeral bool, opts ...rest.RequestOpt) error {
+	var data fluxer.InteractionCallbackData
+	if ephemeral {
+		data = fluxer.MessageCreate{Flags: fluxer.MessageFlagEphemeral}
+	}
+	return e.Respond(fluxer.InteractionResponseTypeDeferredCreateMessage, data, opts...)
+}
+
+// UpdateMessage responds to a component interaction by updating the message the component is attached to.
+func (e *InteractionCreate) UpdateMessage(messageUpdate fluxer.MessageUpdate, opts ...rest.RequestOpt) error {
+	return e.Respond(fluxer.InteractionResponseTypeUpdateMessage, messageUpdate, opts...)
+}
+
+// DeferUpdateMessage acknowledges a component interaction without updating the message yet.
+func (e *InteractionCreate) DeferUpdateMessage(opts ...rest.RequestOpt) error {
+	return e.Respond(fluxer.InteractionResponseTypeDeferredUpdateMessage, nil, opts...)
+}
+
+// AutocompleteResult responds to an autocomplete interaction with the given choices.
+func (e *InteractionCreate) AutocompleteResult(choices []fluxer.AutocompleteChoice, opts ...rest.RequestOpt) error {
+	return e.Respond(fluxer.InteractionResponseTypeAutocompleteResult, fluxer.AutocompleteResult{Choices: choices}, opts...)
+}
+
+// GetResponse returns the original response of the interaction.
+func (e *InteractionCreate) GetResponse(opts ...rest.RequestOpt) (*fluxer.Message, error) {
+	if err := e.checkToken(); err != nil {
+		return nil, err
+	}
+	return e.Client().Rest.GetInteractionResponse(e.ApplicationID, e.Token, e.requestOpts(opts)...)
+}
+
+// UpdateResponse updates the original response of the interaction, for example after DeferReply.
+func (e *InteractionCreate) UpdateResponse(messageUpdate fluxer.MessageUpdate, opts ...rest.RequestOpt) (*fluxer.Message, error) {
+	if err := e.checkToken(); err != nil {
+		return nil, err
+	}
+	return e.Client().Rest.UpdateInteractionResponse(e.ApplicationID, e.Token, messageUpdate, e.requestOpts(opts)...)
+}
+
+// DeleteResponse deletes the original response of the interaction.
+func (e *InteractionCreate) DeleteResponse(opts ...rest.RequestOpt) error {
+	if err := e.checkToken(); err != nil {
+		return err
+	}
+	return e.Client().Rest.DeleteInteractionResponse(e.ApplicationID, e.Token, e.requestOpts(opts)...)
+}
+
+// CreateFollowupMessage sends a follow-up message to the interaction.
+func (e *InteractionCreate) CreateFollowupMessage(messageCreate fluxer.MessageCreate, opts ...rest.RequestOpt) (*fluxer.Message, error) {
+	if err := e.checkToken(); err != nil {
+		return nil, err
+	}
+	return e.Client().Rest.CreateFollowupMessage(e.ApplicationID, e.Token, messageCreate, e.requestOpts(opts)...)
+}
+
+// UpdateFollowupMessage updates a follow-up message of the interaction.
+func (e *InteractionCreate) UpdateFollowupMessage(messageID snowflake.ID, messageUpdate fluxer.MessageUpdate, opts ...rest.RequestOpt) (*fluxer.Message, error) {
+	if err := e.checkToken(); err != nil {
+		return nil, err
+	}
+	return e.Client().Rest.UpdateFollowupMessage(e.ApplicationID, e.Token, messageID, messageUpdate, e.requestOpts(opts)...)
+}
+
+// DeleteFollowupMessage deletes a follow-up message of the interaction.
+func (e *InteractionCreate) DeleteFollowupMessage(messageID snowflake.ID, opts ...rest.RequestOpt) error {
+	if err := e.checkToken(); err != nil {
+		return err
+	}
+	return e.Client().Rest.DeleteFollowupMessage(e.ApplicationID, e.Token, messageID, e.requestOpts(opts)...)
+}
+
+// checkToken returns fluxer.ErrInteractionExpired if the interaction token is no longer valid.
+func (e *InteractionCreate) checkToken() error {
+	if time.Since(e.CreatedAt()) > InteractionTokenLifetime {
+		return fluxer.ErrInteractionExpired
+	}
+	return nil
+}
+
+func (e *InteractionCreate) requestOpts(opts []rest.RequestOpt) []rest.RequestOpt {
+	return append([]rest.RequestOpt{rest.WithCtx(e.Context())}, opts...)
+}
@@ -0,0 +1,120 @@
+package events
+
+import (
+	"iter"
+	"log/slog"
+	"sync"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/bot"
+	"github.com/fluxergo/fluxergo/cache"
+	"github.com/fluxergo/fluxergo/fluxer"
+)
+
+var _ bot.EventListener = (*inviteTracker)(nil)
+
+// NewInviteTracker returns a bot.EventListener which dispatches a GuildMemberJoinedViaInvite event after each GuildMemberJoin.
+// It fetches the invites of the guild after a member joined and compares their uses with the cached invites to find the used invite.
+// This requires cache.FlagInvites and the fluxer.PermissionManageGuild permission.
+func NewInviteTracker() bot.EventListener {
+	return &inviteTracker{
+		guildLocks: map[snowflake.ID]*sync.Mutex{},
+	}
+}
+
+type inviteTracker struct {
+	mu         sync.Mutex
+	guildLocks map[snowflake.ID]*sync.Mutex
+}
+
+func (t *inviteTracker) OnEvent(event bot.Event) {
+	switch e := event.(type) {
+	case *GuildMemberJoin:
+		// fetching the invites can take a while, so we don't block the event dispatching
+		go t.track(e)
+	case *GuildLeave:
+		t.mu.Lock()
+		delete(t.guildLocks, e.GuildID)
+		t.mu.Unlock()
+	}
+}
+
+// guildLock returns the lock of the given guild. Joins of the same guild are processed one after another, as concurrent joins would make the use counts ambiguous.
+func (t *inviteTracker) guildLock(guildID snowflake.ID) *sync.Mutex {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	mu, ok := t.guildLocks[guildID]
+	if !ok {
+		mu = &sync.Mutex{}
+		t.guildLocks[guildID] = mu
+	}
+	return mu
+}
+
+func (t *inviteTracker) track(e *GuildMemberJoin) {
+	client := e.Client()
+	if client.Caches.CacheFlags().Missing(cache.FlagInvites) {
+		client.Logger.Warn("invite tracker requires cache.FlagInvites to be enabled")
+		return
+	}
+
+	mu := t.guildLock(e.GuildID)
+	mu.Lock()
+	defer mu.Unlock()
+
+	invites, err := client.Rest.GetGuildInvites(e.GuildID)
+	if err != nil {
+		client.Logger.Error("failed to fetch guild invites for invite tracking", slog.Any("err", err), slog.String("guild_id", e.GuildID.String()))
+		return
+	}
+
+	invite, ok := findUsedInvite(client.Caches.Invites(e.GuildID), invites)
+	client.Caches.SetInvites(e.GuildID, invites)
+	if !ok {
+		return
+	}
+
+	client.EventManager.DispatchEvent(&GuildMemberJoinedViaInvite{
+		GenericGuildMember: e.GenericGuildMember,
+		Invite:             invite,
+		Inviter:            invite.Inviter,
+	})
+}
+
+// findUsedInvite compares the old cached invites with the current invites and returns the invite whose uses increased the most.
+// Invites which reached their max uses get deleted, so a missing invite which was one use away from its limit is used as a fallback.
+func findUsedInvite(oldInvites iter.Seq[fluxer.ExtendedInvite], newInvites []fluxer.ExtendedInvite) (fluxer.ExtendedInvite, bool) {
+	current := make(map[string]fluxer.ExtendedInvite, len(newInvites))
+	for _, invite := range newInvites {
+		current[invite.Code] = invite
+	}
+
+	var (
+		used      fluxer.ExtendedInvite
+		increase  int
+		exhausted *fluxer.ExtendedInvite
+	)
+	for oldInvite := range oldInvites {
+		newInvite, ok := current[oldInvite.Code]
+		if !ok {
+			if oldInvite.MaxUses > 0 && oldInvite.Uses+1 >= oldInvite.MaxUses {
+				oldInvite.Uses++
+				exhausted = &oldInvite
+			}
+			continue
+		}
+		if diff := newInvite.Uses - oldInvite.Uses; diff > increase {
+			used = newInvite
+			increase = diff
+		}
+	}
+
+	if increase > 0 {
+		return used, true
+	}
+	if exhausted != nil {
+		return *exhausted, true
+	}
+	return fluxer.ExtendedInvite{}, false
+}
@@ -0,0 +1,53 @@
+package events
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/fluxergo/fluxergo/fluxer"
+)
+
+func testInvite(code string, uses int, maxUses int) fluxer.ExtendedInvite {
+	return fluxer.ExtendedInvite{
+		Invite:  fluxer.Invite{Code: code},
+		Uses:    uses,
+		MaxUses: maxUses,
+	}
+}
+
+func TestFindUsedInvite(t *testing.T) {
+	data := []struct {
+		name       string
+		oldInvites []fluxer.ExtendedInvite
+		newInvites []fluxer.ExtendedInvite
+		expected   string
+	}{
+		{
+			name:       "uses increased",
+			oldInvites: []fluxer.ExtendedInvite{testInvite("a", 1, 0), testInvite("b", 5, 0)},
+			newInvites: []fluxer.ExtendedInvite{testInvite("a", 1, 0), testInvite("b", 6, 0)},
+			expected:   "b",
+		},
+		{
+			name:       "exhausted invite got deleted",
+			oldInvites: []fluxer.ExtendedInvite{testInvite("a", 1, 0), testInvite("b", 4, 5)},
+			newInvites: []fluxer.ExtendedInvite{testInvite("a", 1, 0)},
+			expected:   "b",
+		},
+		{
+			name:       "no change",
+			oldInvites: []fluxer.ExtendedInvite{testInvite("a", 1, 0)},
+			newInvites: []fluxer.ExtendedInvite{testInvite("a", 1, 0), testInvite("c", 0, 0)},
+			expected:   "",
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			invite, ok := findUsedInvite(slices.Values(d.oldInvites), d.newInvites)
+			if ok != (d.expected != "") || invite.Code != d.expected {
+				t.Errorf("expected invite %q, got %q (%t)", d.expected, invite.Code, ok)
+			}
+		})
+	}
+}
@@ -23,6 +23,11 @@ type ListenerAdapter struct {
 	OnGuildChannelDelete     func(event *GuildChannelDelete)
 	OnGuildChannelPinsUpdate func(event *GuildChannelPinsUpdate)
 
+	// Thread Member Events
+	OnThreadMemberAdd    func(event *ThreadMemberAdd)
+	OnThreadMemberUpdate func(event *ThreadMemberUpdate)
+	OnThreadMemberRemove func(event *ThreadMemberRemove)
+
 	// DM Channel Events
 	OnDMChannelPinsUpdate func(event *DMChannelPinsUpdate)
 
@@ -69,6 +74,8 @@ type ListenerAdapter struct {
 	OnGuildInviteCreate func(event *InviteCreate)
 	OnGuildInviteDelete func(event *InviteDelete)
 
+	OnGuildMemberJoinedViaInvite func(event *GuildMemberJoinedViaInvite)
+
 	// Guild Member Events
 	OnGuildMemberJoin   func(event *GuildMemberJoin)
 	OnGuildMemberUpdate func(event *GuildMemberUpdate)
@@ -92,6 +99,18 @@ type ListenerAdapter struct {
 	OnGuildVoiceMove        func(event *GuildVoiceMove)
 	OnGuildVoiceLeave       func(event *GuildVoiceLeave)
 
+	// Voice Conn Events
+	OnVoiceConnStatusChange func(event *VoiceConnStatusChange)
+	OnVoiceSpeakingStart    func(event *VoiceSpeakingStart)
+	OnVoiceSpeakingStop     func(event *VoiceSpeakingStop)
+	OnVoiceParticipantJoin  func(event *VoiceParticipantJoin)
+	OnVoiceParticipantLeave func(event *VoiceParticipantLeave)
+
+	// Voice Player Events
+	OnPlayerTrackStart func(event *PlayerTrackStart)
+	OnPlayerTrackEnd   func(event *PlayerTrackEnd)
+	OnPlayerTrackError func(event *PlayerTrackError)
+
 	// Guild Role Events
 	OnRoleCreate func(event *RoleCreate)
 	OnRoleUpdate func(event *RoleUpdate)
@@ -140,6 +159,9 @@ type ListenerAdapter struct {
 	OnGuildIntegrationsUpdate func(event *GuildIntegrationsUpdate)
 
 	OnGuildWebhooksUpdate func(event *WebhooksUpdate)
+
+	// Interaction Events
+	OnInteractionCreate func(event *InteractionCreate)
 }
 
 // OnEvent is getting called everytime we receive an event
@@ -173,6 +195,20 @@ func (l *ListenerAdapter) OnEvent(event bot.Event) {
 			listener(e)
 		}
 
+	// Thread Member Events
+	case *ThreadMemberAdd:
+		if listener := l.OnThreadMemberAdd; listener != nil {
+			listener(e)
+		}
+	case *ThreadMemberUpdate:
+		if listener := l.OnThreadMemberUpdate; listener != nil {
+			listener(e)
+		}
+	case *ThreadMemberRemove:
+		if listener := l.OnThreadMemberRemove; listener != nil {
+			listener(e)
+		}
+
 	// DMChannel Events
 	case *DMChannelPinsUpdate:
 		if listener := l.OnDMChannelPinsUpdate; listener != nil {
@@ -308,6 +344,10 @@ func (l *ListenerAdapter) OnEvent(event bot.Event) {
 		if listener := l.OnGuildInviteDelete; listener != nil {
 			listener(e)
 		}
+	case *GuildMemberJoinedViaInvite:
+		if listener := l.OnGuildMemberJoinedViaInvite; listener != nil {
+			listener(e)
+		}
 
 	// Member Events
 	case *GuildMemberJoin:
@@ -377,6 +417,42 @@ func (l *ListenerAdapter) OnEvent(event bot.Event) {
 			listener(e)
 		}
 
+	// Voice Conn Events
+	case *VoiceConnStatusChange:
+		if listener := l.OnVoiceConnStatusChange; listener != nil {
+			listener(e)
+		}
+	case *VoiceSpeakingStart:
+		if listener := l.OnVoiceSpeakingStart; listener != nil {
+			listener(e)
+		}
+	case *VoiceSpeakingStop:
+		if listener := l.OnVoiceSpeakingStop; listener != nil {
+			listener(e)
+		}
+	case *VoiceParticipantJoin:
+		if listener := l.OnVoiceParticipantJoin; listener != nil {
+			listener(e)
+		}
+	case *VoiceParticipantLeave:
+		if listener := l.OnVoiceParticipantLeave; listener != nil {
+			listener(e)
+		}
+
+	// Voice Player Events
+	case *PlayerTrackStart:
+		if listener := l.OnPlayerTrackStart; listener != nil {
+			listener(e)
+		}
+	case *PlayerTrackEnd:
+		if listener := l.OnPlayerTrackEnd; listener != nil {
+			listener(e)
+		}
+	case *PlayerTrackError:
+		if listener := l.OnPlayerTrackError; listener != nil {
+			listener(e)
+		}
+
 	// Guild Role Events
 	case *RoleCreate:
 		if listener := l.OnRoleCreate; listener != nil {
@@ -521,6 +597,11 @@ func (l *ListenerAdapter) OnEvent(event bot.Event) {
 			listener(e)
 		}
 
+	case *InteractionCreate:
+		if listener := l.OnInteractionCreate; listener != nil {
+			listener(e)
+		}
+
 	default:
 		e.Client().Logger.Error("unexpected event received", slog.String("type", fmt.Sprintf("%T", event)), slog.String("data", fmt.Sprintf("%+v", event)))
 	}
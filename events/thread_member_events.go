@@ -0,0 +1,41 @@
+package events
+
+import (
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/fluxer"
+)
+
+// GenericThreadMember is called upon receiving ThreadMemberAdd, ThreadMemberUpdate or ThreadMemberRemove
+type GenericThreadMember struct {
+	*GenericEvent
+	GuildID      snowflake.ID
+	ThreadID     snowflake.ID
+	UserID       snowflake.ID
+	ThreadMember fluxer.ThreadMember
+}
+
+// Guild returns the fluxer.Guild the event happened in.
+// This will only check cached guilds!
+func (e *GenericThreadMember) Guild() (fluxer.Guild, bool) {
+	return e.Client().Caches.Guild(e.GuildID)
+}
+
+// ThreadMemberAdd indicates that a fluxer.User was added to a thread
+type ThreadMemberAdd struct {
+	*GenericThreadMember
+	Member   fluxer.Member
+	Presence *fluxer.Presence
+}
+
+// ThreadMemberUpdate indicates that the fluxer.ThreadMember of the bot got updated in a thread
+type ThreadMemberUpdate struct {
+	*GenericThreadMember
+	OldThreadMember fluxer.ThreadMember
+}
+
+// ThreadMemberRemove indicates that a fluxer.User was removed from a thread.
+// ThreadMember is the old cached fluxer.ThreadMember and may be empty.
+type ThreadMemberRemove struct {
+	*GenericThreadMember
+}
@@ -0,0 +1,47 @@
+package events
+
+import (
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/voice"
+)
+
+// GenericVoiceConn is called upon receiving VoiceConnStatusChange, VoiceSpeakingStart, VoiceSpeakingStop, VoiceParticipantJoin and VoiceParticipantLeave
+type GenericVoiceConn struct {
+	*GenericEvent
+	// Conn is the voice.Conn of the guild or call the event belongs to
+	Conn voice.Conn
+}
+
+// VoiceConnStatusChange indicates that the voice.ConnStatus of a voice.Conn changed
+type VoiceConnStatusChange struct {
+	*GenericVoiceConn
+	OldStatus voice.ConnStatus
+	NewStatus voice.ConnStatus
+}
+
+// VoiceSpeakingStart indicates that a participant of a voice.Conn started speaking
+type VoiceSpeakingStart struct {
+	*GenericVoiceConn
+	UserID snowflake.ID
+	// Level is the audio level of the participant between 0 and 1
+	Level float32
+}
+
+// VoiceSpeakingStop indicates that a participant of a voice.Conn stopped speaking
+type VoiceSpeakingStop struct {
+	*GenericVoiceConn
+	UserID snowflake.ID
+}
+
+// VoiceParticipantJoin indicates that a user joined the voice channel of a voice.Conn
+type VoiceParticipantJoin struct {
+	*GenericVoiceConn
+	UserID snowflake.ID
+}
+
+// VoiceParticipantLeave indicates that a user left the voice channel of a voice.Conn
+type VoiceParticipantLeave struct {
+	*GenericVoiceConn
+	UserID snowflake.ID
+}
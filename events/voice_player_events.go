@@ -0,0 +1,54 @@
+package events
+
+import (
+	"github.com/fluxergo/fluxergo/bot"
+	"github.com/fluxergo/fluxergo/voice"
+)
+
+// NewPlayerEventFunc returns a voice.PlayerEventFunc which dispatches the voice.PlayerEvent(s) of a voice.Player as PlayerTrackStart, PlayerTrackEnd and PlayerTrackError through the bot.EventManager.
+// Pass it to voice.NewPlayer with voice.WithPlayerEventFunc.
+func NewPlayerEventFunc(client *bot.Client) voice.PlayerEventFunc {
+	return func(event voice.PlayerEvent) {
+		var shardID int
+		if client.HasGateway() {
+			shardID = client.Gateway.ShardID()
+		}
+		genericEvent := NewGenericEvent(client, -1, shardID)
+
+		switch e := event.(type) {
+		case voice.TrackStart:
+			client.EventManager.DispatchEvent(&PlayerTrackStart{
+				GenericEvent: genericEvent,
+				TrackStart:   e,
+			})
+		case voice.TrackEnd:
+			client.EventManager.DispatchEvent(&PlayerTrackEnd{
+				GenericEvent: genericEvent,
+				TrackEnd:     e,
+			})
+		case voice.TrackError:
+			client.EventManager.DispatchEvent(&PlayerTrackError{
+				GenericEvent: genericEvent,
+				TrackError:   e,
+			})
+		}
+	}
+}
+
+// PlayerTrackStart indicates that a voice.Player started playing a voice.Track
+type PlayerTrackStart struct {
+	*GenericEvent
+	voice.TrackStart
+}
+
+// PlayerTrackEnd indicates that a voice.Track of a voice.Player finished, was skipped or stopped or failed
+type PlayerTrackEnd struct {
+	*GenericEvent
+	voice.TrackEnd
+}
+
+// PlayerTrackError indicates that a voice.Track of a voice.Player could not be opened or read
+type PlayerTrackError struct {
+	*GenericEvent
+	voice.TrackError
+}
@@ -8,6 +8,7 @@ var (
 	ErrNoGuildMembersIntent    = errors.New("this operation requires the GUILD_MEMBERS intent")
 	ErrNoShardManager          = errors.New("no shard manager configured")
 	ErrNoGateway               = errors.New("no gateway configured")
+	ErrNoEventSource           = errors.New("no event source configured")
 	ErrGatewayAlreadyConnected = errors.New("gateway is already connected")
 	ErrShardNotConnected       = errors.New("shard is not connected")
 	ErrShardNotReady           = errors.New("shard is not ready")
@@ -0,0 +1,423 @@
+package fluxer
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// InteractionType is the type of Interaction
+type InteractionType int
+
+// Constants for InteractionType
+const (
+	InteractionTypePing InteractionType = iota + 1
+	InteractionTypeApplicationCommand
+	InteractionTypeComponent
+	InteractionTypeAutocomplete
+	InteractionTypeModalSubmit
+)
+
+// Interaction is sent when a user invokes an application command or uses a message component.
+type Interaction struct {
+	ID             snowflake.ID    `json:"id"`
+	ApplicationID  snowflake.ID    `json:"application_id"`
+	Type           InteractionType `json:"type"`
+	Data           InteractionData `json:"data,omitempty"`
+	GuildID        *snowflake.ID   `json:"guild_id,omitempty"`
+	ChannelID      *snowflake.ID   `json:"channel_id,omitempty"`
+	Member         *Member         `json:"member,omitempty"`
+	User           *User           `json:"user,omitempty"`
+	Token          string          `json:"token"`
+	Version        int             `json:"version"`
+	Message        *Message        `json:"message,omitempty"`
+	AppPermissions *Permissions    `json:"app_permissions,omitempty"`
+	Locale         Locale          `json:"locale,omitempty"`
+	GuildLocale    *Locale         `json:"guild_locale,omitempty"`
+}
+
+func (i *Interaction) UnmarshalJSON(data []byte) error {
+	type interaction Interaction
+	var v struct {
+		interaction
+		Data json.RawMessage `json:"data,omitempty"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*i = Interaction(v.interaction)
+	if i.Member != nil && i.GuildID != nil {
+		i.Member.GuildID = *i.GuildID
+	}
+	if len(v.Data) == 0 {
+		return nil
+	}
+
+	var err error
+	switch i.Type {
+	case InteractionTypeApplicationCommand, InteractionTypeAutocomplete:
+		var d ApplicationCommandInteractionData
+		err = json.Unmarshal(v.Data, &d)
+		i.Data = d
+
+	case InteractionTypeComponent:
+		var d ComponentInteractionData
+		err = json.Unmarshal(v.Data, &d)
+		i.Data = d
+
+	case InteractionTypeModalSubmit:
+		var d ModalSubmitInteractionData
+		err = json.Unmarshal(v.Data, &d)
+		i.Data = d
+	}
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal interaction data: %w", err)
+	}
+	return nil
+}
+
+// Invoker returns the User who created the Interaction. In guilds this is the User of the Member.
+func (i Interaction) Invoker() User {
+	if i.Member != nil {
+		return i.Member.User
+	}
+	if i.User != nil {
+		return *i.User
+	}
+	return User{}
+}
+
+// CreatedAt returns the creation time of the Interaction.
+func (i Interaction) CreatedAt() time.Time {
+	return i.ID.Time()
+}
+
+// ApplicationCommandData returns the ApplicationCommandInteractionData of the Interaction.
+func (i Interaction) ApplicationCommandData() (ApplicationCommandInteractionData, bool) {
+	d, ok := i.Data.(ApplicationCommandInteractionData)
+	return d, ok
+}
+
+// ComponentData returns the ComponentInteractionData of the Interaction.
+func (i Interaction) ComponentData() (ComponentInteractionData, bool) {
+	d, ok := i.Data.(ComponentInteractionData)
+	return d, ok
+}
+
+// ModalSubmitData returns the ModalSubmitInteractionData of the Interaction.
+func (i Interaction) ModalSubmitData() (ModalSubmitInteractionData, bool) {
+	d, ok := i.Data.(ModalSubmitInteractionData)
+	return d, ok
+}
+
+// InteractionData is the data of an Interaction. It is either ApplicationCommandInteractionData, ComponentInteractionData or ModalSubmitInteractionData.
+type InteractionData interface {
+	interactionData()
+}
+
+// ApplicationCommandType is the type of ApplicationCommand
+type ApplicationCommandType int
+
+// Constants for ApplicationCommandType
+const (
+	ApplicationCommandTypeSlash ApplicationCommandType = iota + 1
+	ApplicationCommandTypeUser
+	ApplicationCommandTypeMessage
+)
+
+// ApplicationCommandOptionType is the type of ApplicationCommandInteractionOption
+type ApplicationCommandOptionType int
+
+// Constants for ApplicationCommandOptionType
+const (
+	ApplicationCommandOptionTypeSubCommand ApplicationCommandOptionType = iota + 1
+	ApplicationCommandOptionTypeSubCommandGroup
+	ApplicationCommandOptionTypeString
+	ApplicationCommandOptionTypeInt
+	ApplicationCommandOptionTypeBool
+	ApplicationCommandOptionTypeUser
+	ApplicationCommandOptionTypeChannel
+	ApplicationCommandOptionTypeRole
+	ApplicationCommandOptionTypeMentionable
+	ApplicationCommandOptionTypeFloat
+	ApplicationCommandOptionTypeAttachment
+)
+
+// ApplicationCommandInteractionData is the data of an Interaction of type InteractionTypeApplicationCommand or InteractionTypeAutocomplete.
+type ApplicationCommandInteractionData struct {
+	ID          snowflake.ID                          `json:"id"`
+	Name        string                                `json:"name"`
+	CommandType ApplicationCommandType                `json:"type"`
+	GuildID     *snowflake.ID                         `json:"guild_id,omitempty"`
+	TargetID    *snowflake.ID                         `json:"target_id,omitempty"`
+	Options     []ApplicationCommandInteractionOption `json:"options,omitempty"`
+	Resolved    ResolvedData                          `json:"resolved"`
+}
+
+func (ApplicationCommandInteractionData) interactionData() {}
+
+// SubCommandPath returns the names of the invoked sub command group and sub command, if any.
+func (d ApplicationCommandInteractionData) SubCommandPath() []string {
+	var path []string
+	options := d.Options
+	for len(options) == 1 && options[0].isSubCommand() {
+		path = append(path, options[0].Name)
+		options = options[0].Options
+	}
+	return path
+}
+
+// Option returns the ApplicationCommandInteractionOption with the given name of the invoked (sub) command.
+func (d ApplicationCommandInteractionData) Option(name string) (ApplicationCommandInteractionOption, bool) {
+	for _, option := range d.commandOptions() {
+		if option.Name == name {
+			return option, true
+		}
+	}
+	return ApplicationCommandInteractionOption{}, false
+}
+
+// Focused returns the option the user is currently typing in during autocomplete.
+func (d ApplicationCommandInteractionData) Focused() (ApplicationCommandInteractionOption, bool) {
+	for _, option := range d.commandOptions() {
+		if option.Focused {
+			return option, true
+		}
+	}
+	return ApplicationCommandInteractionOption{}, false
+}
+
+// String returns the value of the string option with the given name.
+func (d ApplicationCommandInteractionData) String(name string) (string, bool) {
+	var v string
+	ok := d.optionValue(name, &v)
+	return v, ok
+}
+
+// Int returns the value of the int option with the given name.
+func (d ApplicationCommandInteractionData) Int(name string) (int, bool) {
+	var v int
+	ok := d.optionValue(name, &v)
+	return v, ok
+}
+
+// Float returns the value of the float option with the given name.
+func (d ApplicationCommandInteractionData) Float(name string) (float64, bool) {
+	var v float64
+	ok := d.optionValue(name, &v)
+	return v, ok
+}
+
+// Bool returns the value of the bool option with the given name.
+func (d ApplicationCommandInteractionData) Bool(name string) (bool, bool) {
+	var v bool
+	ok := d.optionValue(name, &v)
+	return v, ok
+}
+
+// Snowflake returns the value of a user, channel, role, mentionable or attachment option with the given name.
+func (d ApplicationCommandInteractionData) Snowflake(name string) (snowflake.ID, bool) {
+	var v snowflake.ID
+	ok := d.optionValue(name, &v)
+	return v, ok
+}
+
+// User returns the resolved User of the user option with the given name.
+func (d ApplicationCommandInteractionData) User(name string) (User, bool) {
+	id, ok := d.Snowflake(name)
+	if !ok {
+		return User{}, false
+	}
+	user, ok := d.Resolved.Users[id]
+	return user, ok
+}
+
+// Role returns the resolved Role of the role option with the given name.
+func (d ApplicationCommandInteractionData) Role(name string) (Role, bool) {
+	id, ok := d.Snowflake(name)
+	if !ok {
+		return Role{}, false
+	}
+	role, ok := d.Resolved.Roles[id]
+	return role, ok
+}
+
+// commandOptions returns the options of the invoked sub command or the command itself.
+func (d ApplicationCommandInteractionData) commandOptions() []ApplicationCommandInteractionOption {
+	options := d.Options
+	for len(options) == 1 && options[0].isSubCommand() {
+		options = options[0].Options
+	}
+	return options
+}
+
+func (d ApplicationCommandInteractionData) optionValue(name string, v any) bool {
+	option, ok := d.Option(name)
+	if !ok || len(option.Value) == 0 {
+		return false
+	}
+	return json.Unmarshal(option.Value, v) == nil
+}
+
+// ApplicationCommandInteractionOption is an option passed to an application command.
+type ApplicationCommandInteractionOption struct {
+	Name    string                                `json:"name"`
+	Type    ApplicationCommandOptionType          `json:"type"`
+	Value   json.RawMessage                       `json:"value,omitempty"`
+	Options []ApplicationCommandInteractionOption `json:"options,omitempty"`
+	Focused bool                                  `json:"focused,omitempty"`
+}
+
+func (o ApplicationCommandInteractionOption) isSubCommand() bool {
+	return o.Type == ApplicationCommandOptionTypeSubCommand || o.Type == ApplicationCommandOptionTypeSubCommandGroup
+}
+
+// ResolvedData contains the users, members, roles, channels, messages and attachments referenced by an Interaction.
+type ResolvedData struct {
+	Users       map[snowflake.ID]User            `json:"users,omitempty"`
+	Members     map[snowflake.ID]ResolvedMember  `json:"members,omitempty"`
+	Roles       map[snowflake.ID]Role            `json:"roles,omitempty"`
+	Channels    map[snowflake.ID]ResolvedChannel `json:"channels,omitempty"`
+	Messages    map[snowflake.ID]Message         `json:"messages,omitempty"`
+	Attachments map[snowflake.ID]Attachment      `json:"attachments,omitempty"`
+}
+
+// ResolvedMember is a Member without the User, which can be found in ResolvedData.Users.
+type ResolvedMember struct {
+	Member
+	Permissions Permissions `json:"permissions,omitempty"`
+}
+
+// ResolvedChannel is a partial channel referenced by an Interaction.
+type ResolvedChannel struct {
+	ID          snowflake.ID  `json:"id"`
+	Name        string        `json:"name"`
+	Type        ChannelType   `json:"type"`
+	Permissions Permissions   `json:"permissions"`
+	ParentID    *snowflake.ID `json:"parent_id,omitempty"`
+}
+
+// ComponentType is the type of message component
+type ComponentType int
+
+// Constants for ComponentType
+const (
+	ComponentTypeActionRow ComponentType = iota + 1
+	ComponentTypeButton
+	ComponentTypeStringSelectMenu
+	ComponentTypeTextInput
+	ComponentTypeUserSelectMenu
+	ComponentTypeRoleSelectMenu
+	ComponentTypeMentionableSelectMenu
+	ComponentTypeChannelSelectMenu
+)
+
+// ComponentInteractionData is the data of an Interaction of type InteractionTypeComponent.
+type ComponentInteractionData struct {
+	CustomID      string        `json:"custom_id"`
+	ComponentType ComponentType `json:"component_type"`
+	Values        []string      `json:"values,omitempty"`
+	Resolved      *ResolvedData `json:"resolved,omitempty"`
+}
+
+func (ComponentInteractionData) interactionData() {}
+
+// ModalSubmitInteractionData is the data of an Interaction of type InteractionTypeModalSubmit.
+type ModalSubmitInteractionData struct {
+	CustomID   string                 `json:"custom_id"`
+	Components []ModalSubmitActionRow `json:"components"`
+}
+
+func (ModalSubmitInteractionData) interactionData() {}
+
+// Text returns the value of the text input with the given custom ID.
+func (d ModalSubmitInteractionData) Text(customID string) (string, bool) {
+	for _, row := range d.Components {
+		for _, component := range row.Components {
+			if component.CustomID == customID {
+				return component.Value, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ModalSubmitActionRow is an action row of a submitted modal.
+type ModalSubmitActionRow struct {
+	Type       ComponentType          `json:"type"`
+	Components []ModalSubmitComponent `json:"components"`
+}
+
+// ModalSubmitComponent is a text input of a submitted modal with the value entered by the user.
+type ModalSubmitComponent struct {
+	Type     ComponentType `json:"type"`
+	CustomID string        `json:"custom_id"`
+	Value    string        `json:"value"`
+}
+
+// InteractionResponseType is the type of InteractionResponse
+type InteractionResponseType int
+
+// Constants for InteractionResponseType
+const (
+	InteractionResponseTypePong InteractionResponseType = iota + 1
+	_
+	_
+	InteractionResponseTypeCreateMessage
+	InteractionResponseTypeDeferredCreateMessage
+	InteractionResponseTypeDeferredUpdateMessage
+	InteractionResponseTypeUpdateMessage
+	InteractionResponseTypeAutocompleteResult
+)
+
+// InteractionCallbackData is the data of an InteractionResponse.
+// It is implemented by MessageCreate, MessageUpdate and AutocompleteResult.
+type InteractionCallbackData interface {
+	interactionCallbackData()
+}
+
+// InteractionResponse is used to respond to an Interaction.
+type InteractionResponse struct {
+	Type InteractionResponseType `json:"type"`
+	Data InteractionCallbackData `json:"data,omitempty"`
+}
+
+// ToBody returns the InteractionResponse ready for body.
+func (r InteractionResponse) ToBody() (any, error) {
+	switch d := r.Data.(type) {
+	case MessageCreate:
+		if len(d.Files) > 0 {
+			d.Attachments = parseAttachments(d.Files)
+			r.Data = d
+			return PayloadWithFiles(r, d.Files...)
+		}
+	case MessageUpdate:
+		if len(d.Files) > 0 {
+			for _, attachmentCreate := range parseAttachments(d.Files) {
+				if d.Attachments == nil {
+					d.Attachments = new([]AttachmentUpdate)
+				}
+				*d.Attachments = append(*d.Attachments, attachmentCreate)
+			}
+			r.Data = d
+			return PayloadWithFiles(r, d.Files...)
+		}
+	}
+	return r, nil
+}
+
+// AutocompleteResult is the InteractionCallbackData of an InteractionResponseTypeAutocompleteResult.
+type AutocompleteResult struct {
+	Choices []AutocompleteChoice `json:"choices"`
+}
+
+func (AutocompleteResult) interactionCallbackData() {}
+
+// AutocompleteChoice is a suggested value for the focused option. Value has to be a string, int or float64 depending on the option type.
+type AutocompleteChoice struct {
+	Name  string `json:"name"`
+	Value any    `json:"value"`
+}
@@ -0,0 +1,109 @@
+package fluxer
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestInteraction_UnmarshalJSON(t *testing.T) {
+	data := `{
+		"id": "1",
+		"application_id": "2",
+		"type": 2,
+		"guild_id": "3",
+		"member": {"user": {"id": "4", "username": "test"}},
+		"token": "token",
+		"data": {
+			"id": "5",
+			"name": "config",
+			"type": 1,
+			"options": [{"name": "set", "type": 1, "options": [
+				{"name": "key", "type": 3, "value": "prefix"},
+				{"name": "count", "type": 4, "value": 3}
+			]}]
+		}
+	}`
+
+	var interaction Interaction
+	if err := json.Unmarshal([]byte(data), &interaction); err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	if interaction.Invoker().ID != 4 {
+		t.Errorf("expected invoker 4, got %s", interaction.Invoker().ID)
+	}
+	if interaction.Member.GuildID != 3 {
+		t.Errorf("expected member guild 3, got %s", interaction.Member.GuildID)
+	}
+
+	commandData, ok := interaction.ApplicationCommandData()
+	if !ok {
+		t.Fatalf("expected ApplicationCommandInteractionData, got %T", interaction.Data)
+	}
+	if path := commandData.SubCommandPath(); len(path) != 1 || path[0] != "set" {
+		t.Errorf("expected sub command path [set], got %v", path)
+	}
+	if key, _ := commandData.String("key"); key != "prefix" {
+		t.Errorf("expected key prefix, got %q", key)
+	}
+	if count, _ := commandData.Int("count"); count != 3 {
+		t.Errorf("expected count 3, got %d", count)
+	}
+	if _, ok = commandData.String("missing"); ok {
+		t.Error("expected missing option to not be found")
+	}
+}
+
+func TestInteraction_UnmarshalJSONWithoutCommandData(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		text string
+	}{
+		{
+			name: "ping",
+			data: `{"id": "1", "type": 1, "guild_id": "3", "member": {"user": {"id": "4"}}, "token": "token"}`,
+		},
+		{
+			name: "modal submit",
+			data: `{"id": "1", "type": 5, "guild_id": "3", "member": {"user": {"id": "4"}}, "token": "token", "data": {
+				"custom_id": "feedback",
+				"components": [{"type": 1, "components": [{"type": 4, "custom_id": "text", "value": "hello"}]}]
+			}}`,
+			text: "hello",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var interaction Interaction
+			if err := json.Unmarshal([]byte(tt.data), &interaction); err != nil {
+				t.Fatalf("unexpected error unmarshaling: %v", err)
+			}
+			if interaction.Member.GuildID != 3 {
+				t.Errorf("expected member guild 3, got %s", interaction.Member.GuildID)
+			}
+			if tt.text == "" {
+				return
+			}
+			modalData, ok := interaction.ModalSubmitData()
+			if !ok {
+				t.Fatalf("expected ModalSubmitInteractionData, got %T", interaction.Data)
+			}
+			if text, _ := modalData.Text("text"); text != tt.text {
+				t.Errorf("expected text %q, got %q", tt.text, text)
+			}
+		})
+	}
+}
+
+func TestInteractionResponse_MarshalJSON(t *testing.T) {
+	response := InteractionResponse{
+		Type: InteractionResponseTypeDeferredCreateMessage,
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+	if expected := `{"type":5}`; string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, string(data))
+	}
+}
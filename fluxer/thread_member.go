@@ -0,0 +1,58 @@
+package fluxer
+
+import (
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/internal/flags"
+)
+
+// ThreadMember represents a User who joined a thread
+type ThreadMember struct {
+	ThreadID      snowflake.ID      `json:"id"`
+	UserID        snowflake.ID      `json:"user_id"`
+	JoinTimestamp time.Time         `json:"join_timestamp"`
+	Flags         ThreadMemberFlags `json:"flags"`
+
+	// This field is not present everywhere in the API and often populated by fluxergo
+	GuildID snowflake.ID `json:"guild_id,omitempty"`
+}
+
+// ThreadMemberFlags are the notification settings of a ThreadMember
+type ThreadMemberFlags int
+
+const (
+	ThreadMemberFlagHasInteracted ThreadMemberFlags = 1 << iota
+	ThreadMemberFlagAllMessages
+	ThreadMemberFlagOnlyMentions
+	ThreadMemberFlagNoMessages
+	ThreadMemberFlagsNone ThreadMemberFlags = 0
+)
+
+// Add allows you to add multiple bits together, producing a new bit
+func (f ThreadMemberFlags) Add(bits ...ThreadMemberFlags) ThreadMemberFlags {
+	return flags.Add(f, bits...)
+}
+
+// Remove allows you to subtract multiple bits from the first, producing a new bit
+func (f ThreadMemberFlags) Remove(bits ...ThreadMemberFlags) ThreadMemberFlags {
+	return flags.Remove(f, bits...)
+}
+
+// Has will ensure that the bit includes all the bits entered
+func (f ThreadMemberFlags) Has(bits ...ThreadMemberFlags) bool {
+	return flags.Has(f, bits...)
+}
+
+// Missing will check whether the bit is missing any one of the bits
+func (f ThreadMemberFlags) Missing(bits ...ThreadMemberFlags) bool {
+	return flags.Missing(f, bits...)
+}
+
+// AddedThreadMember is a ThreadMember which was added to a thread, including the guild Member and Presence of the User
+type AddedThreadMember struct {
+	ThreadMember
+	Member   Member    `json:"member"`
+	Presence *Presence `json:"presence"`
+}
@@ -84,7 +84,7 @@ func getVersion() string {
 // New creates a new bot.Client with the provided token & bot.ConfigOpt(s)
 func New(token string, opts ...bot.ConfigOpt) (*bot.Client, error) {
 	return bot.BuildClient(token,
-		opts,
+		append([]bot.ConfigOpt{bot.WithVoiceConnEventHandler(handlers.HandleVoiceConnEvent)}, opts...),
 		handlers.GetGatewayHandlers(),
 		runtime.GOOS,
 		Name,
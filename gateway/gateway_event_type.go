@@ -40,6 +40,8 @@ const (
 	EventTypeChannelUpdate              EventType = "CHANNEL_UPDATE"
 	EventTypeChannelDelete              EventType = "CHANNEL_DELETE"
 	EventTypeChannelPinsUpdate          EventType = "CHANNEL_PINS_UPDATE"
+	EventTypeThreadMemberUpdate         EventType = "THREAD_MEMBER_UPDATE"
+	EventTypeThreadMembersUpdate        EventType = "THREAD_MEMBERS_UPDATE"
 	EventTypeInviteCreate               EventType = "INVITE_CREATE"
 	EventTypeInviteDelete               EventType = "INVITE_DELETE"
 	EventTypeTypingStart                EventType = "TYPING_START"
@@ -164,6 +164,24 @@ func (e *EventChannelDelete) UnmarshalJSON(data []byte) error {
 func (EventChannelDelete) messageData() {}
 func (EventChannelDelete) eventData()   {}
 
+type EventThreadMemberUpdate struct {
+	fluxer.ThreadMember
+}
+
+func (EventThreadMemberUpdate) messageData() {}
+func (EventThreadMemberUpdate) eventData()   {}
+
+type EventThreadMembersUpdate struct {
+	ID               snowflake.ID               `json:"id"`
+	GuildID          snowflake.ID               `json:"guild_id"`
+	MemberCount      int                        `json:"member_count"`
+	AddedMembers     []fluxer.AddedThreadMember `json:"added_members"`
+	RemovedMemberIDs []snowflake.ID             `json:"removed_member_ids"`
+}
+
+func (EventThreadMembersUpdate) messageData() {}
+func (EventThreadMembersUpdate) eventData()   {}
+
 type EventGuildCreate struct {
 	fluxer.GatewayGuild
 }
@@ -448,6 +466,13 @@ type EventGuildScheduledEventUserRemove struct {
 func (EventGuildScheduledEventUserRemove) messageData() {}
 func (EventGuildScheduledEventUserRemove) eventData()   {}
 
+type EventInteractionCreate struct {
+	fluxer.Interaction
+}
+
+func (EventInteractionCreate) messageData() {}
+func (EventInteractionCreate) eventData()   {}
+
 type EventInviteCreate struct {
 	ChannelID         snowflake.ID               `json:"channel_id"`
 	Code              string                     `json:"code"`
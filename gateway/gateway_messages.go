@@ -140,6 +140,16 @@ func UnmarshalEventData(data []byte, eventType EventType) (EventData, error) {
 		err = json.Unmarshal(data, &d)
 		eventData = d
 
+	case EventTypeThreadMemberUpdate:
+		var d EventThreadMemberUpdate
+		err = json.Unmarshal(data, &d)
+		eventData = d
+
+	case EventTypeThreadMembersUpdate:
+		var d EventThreadMembersUpdate
+		err = json.Unmarshal(data, &d)
+		eventData = d
+
 	case EventTypeGuildCreate:
 		var d EventGuildCreate
 		err = json.Unmarshal(data, &d)
@@ -225,6 +235,11 @@ func UnmarshalEventData(data []byte, eventType EventType) (EventData, error) {
 		err = json.Unmarshal(data, &d)
 		eventData = d
 
+	case EventTypeInteractionCreate:
+		var d EventInteractionCreate
+		err = json.Unmarshal(data, &d)
+		eventData = d
+
 	case EventTypeInviteCreate:
 		var d EventInviteCreate
 		err = json.Unmarshal(data, &d)
@@ -466,8 +481,9 @@ func WithActivityState(state string) ActivityOpt {
 	}
 }
 
-// MessageDataVoiceStateUpdate is used for updating the bots voice state in a guild
+// MessageDataVoiceStateUpdate is used for updating the bots voice state in a guild or a call in a private channel
 type MessageDataVoiceStateUpdate struct {
+	// GuildID is zero for calls in fluxer.DMChannel(s) and fluxer.GroupDMChannel(s) and sent as null.
 	GuildID      snowflake.ID  `json:"guild_id"`
 	ChannelID    *snowflake.ID `json:"channel_id"`
 	SelfMute     bool          `json:"self_mute"`
@@ -480,6 +496,21 @@ type MessageDataVoiceStateUpdate struct {
 	Longitude    string        `json:"longitude"`
 }
 
+func (m MessageDataVoiceStateUpdate) MarshalJSON() ([]byte, error) {
+	type messageDataVoiceStateUpdate MessageDataVoiceStateUpdate
+	var guildID *snowflake.ID
+	if m.GuildID != 0 {
+		guildID = &m.GuildID
+	}
+	return json.Marshal(struct {
+		GuildID *snowflake.ID `json:"guild_id"`
+		messageDataVoiceStateUpdate
+	}{
+		GuildID:                     guildID,
+		messageDataVoiceStateUpdate: messageDataVoiceStateUpdate(m),
+	})
+}
+
 func (MessageDataVoiceStateUpdate) messageData() {}
 
 // MessageDataResume is used to resume a connection to discord in the case that you are disconnected. Is automatically
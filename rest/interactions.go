@@ -0,0 +1,104 @@
+package rest
+
+import (
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/fluxer"
+)
+
+var _ Interactions = (*interactionImpl)(nil)
+
+func NewInteractions(client Client, defaultAllowedMentions fluxer.AllowedMentions) Interactions {
+	return &interactionImpl{client: client, defaultAllowedMentions: defaultAllowedMentions}
+}
+
+type Interactions interface {
+	CreateInteractionResponse(interactionID snowflake.ID, interactionToken string, interactionResponse fluxer.InteractionResponse, opts ...RequestOpt) error
+	GetInteractionResponse(applicationID snowflake.ID, interactionToken string, opts ...RequestOpt) (*fluxer.Message, error)
+	UpdateInteractionResponse(applicationID snowflake.ID, interactionToken string, messageUpdate fluxer.MessageUpdate, opts ...RequestOpt) (*fluxer.Message, error)
+	DeleteInteractionResponse(applicationID snowflake.ID, interactionToken string, opts ...RequestOpt) error
+
+	GetFollowupMessage(applicationID snowflake.ID, interactionToken string, messageID snowflake.ID, opts ...RequestOpt) (*fluxer.Message, error)
+	CreateFollowupMessage(applicationID snowflake.ID, interactionToken string, messageCreate fluxer.MessageCreate, opts ...RequestOpt) (*fluxer.Message, error)
+	UpdateFollowupMessage(applicationID snowflake.ID, interactionToken string, messageID snowflake.ID, messageUpdate fluxer.MessageUpdate, opts ...RequestOpt) (*fluxer.Message, error)
+	DeleteFollowupMessage(applicationID snowflake.ID, interactionToken string, messageID snowflake.ID, opts ...RequestOpt) error
+}
+
+type interactionImpl struct {
+	client                 Client
+	defaultAllowedMentions fluxer.AllowedMentions
+}
+
+func (s *interactionImpl) CreateInteractionResponse(interactionID snowflake.ID, interactionToken string, interactionResponse fluxer.InteractionResponse, opts ...RequestOpt) error {
+	switch data := interactionResponse.Data.(type) {
+	case fluxer.MessageCreate:
+		if data.AllowedMentions == nil {
+			data.AllowedMentions = &s.defaultAllowedMentions
+			interactionResponse.Data = data
+		}
+	case fluxer.MessageUpdate:
+		if data.AllowedMentions == nil && data.Content != nil {
+			data.AllowedMentions = &s.defaultAllowedMentions
+			interactionResponse.Data = data
+		}
+	}
+	body, err := interactionResponse.ToBody()
+	if err != nil {
+		return err
+	}
+	return s.client.Do(CreateInteractionResponse.Compile(nil, interactionID, interactionToken), body, nil, opts...)
+}
+
+func (s *interactionImpl) GetInteractionResponse(applicationID snowflake.ID, interactionToken string, opts ...RequestOpt) (message *fluxer.Message, err error) {
+	err = s.client.Do(GetInteractionResponse.Compile(nil, applicationID, interactionToken), nil, &message, opts...)
+	return
+}
+
+func (s *interactionImpl) UpdateInteractionResponse(applicationID snowflake.ID, interactionToken string, messageUpdate fluxer.MessageUpdate, opts ...RequestOpt) (message *fluxer.Message, err error) {
+	if messageUpdate.AllowedMentions == nil && messageUpdate.Content != nil {
+		messageUpdate.AllowedMentions = &s.defaultAllowedMentions
+	}
+	body, err := messageUpdate.ToBody()
+	if err != nil {
+		return
+	}
+	err = s.client.Do(UpdateInteractionResponse.Compile(nil, applicationID, interactionToken), body, &message, opts...)
+	return
+}
+
+func (s *interactionImpl) DeleteInteractionResponse(applicationID snowflake.ID, interactionToken string, opts ...RequestOpt) error {
+	return s.client.Do(DeleteInteractionResponse.Compile(nil, applicationID, interactionToken), nil, nil, opts...)
+}
+
+func (s *interactionImpl) GetFollowupMessage(applicationID snowflake.ID, interactionToken string, messageID snowflake.ID, opts ...RequestOpt) (message *fluxer.Message, err error) {
+	err = s.client.Do(GetFollowupMessage.Compile(nil, applicationID, interactionToken, messageID), nil, &message, opts...)
+	return
+}
+
+func (s *interactionImpl) CreateFollowupMessage(applicationID snowflake.ID, interactionToken string, messageCreate fluxer.MessageCreate, opts ...RequestOpt) (message *fluxer.Message, err error) {
+	if messageCreate.AllowedMentions == nil {
+		messageCreate.AllowedMentions = &s.defaultAllowedMentions
+	}
+	body, err := messageCreate.ToBody()
+	if err != nil {
+		return
+	}
+	err = s.client.Do(CreateFollowupMessage.Compile(nil, applicationID, interactionToken), body, &message, opts...)
+	return
+}
+
+func (s *interactionImpl) UpdateFollowupMessage(applicationID snowflake.ID, interactionToken string, messageID snowflake.ID, messageUpdate fluxer.MessageUpdate, opts ...RequestOpt) (message *fluxer.Message, err error) {
+	if messageUpdate.AllowedMentions == nil && messageUpdate.Content != nil {
+		messageUpdate.AllowedMentions = &s.defaultAllowedMentions
+	}
+	body, err := messageUpdate.ToBody()
+	if err != nil {
+		return
+	}
+	err = s.client.Do(UpdateFollowupMessage.Compile(nil, applicationID, interactionToken, messageID), body, &message, opts...)
+	return
+}
+
+func (s *interactionImpl) DeleteFollowupMessage(applicationID snowflake.ID, interactionToken string, messageID snowflake.ID, opts ...RequestOpt) error {
+	return s.client.Do(DeleteFollowupMessage.Compile(nil, applicationID, interactionToken, messageID), nil, nil, opts...)
+}
@@ -13,6 +13,7 @@ type Rest interface {
 	Invites
 	Users
 	Webhooks
+	Interactions
 	Emojis
 	Stickers
 	GuildScheduledEvents
@@ -36,6 +37,7 @@ func New(client Client, opts ...ConfigOpt) Rest {
 		Invites:              NewInvites(client),
 		Users:                NewUsers(client),
 		Webhooks:             NewWebhooks(client, cfg.DefaultAllowedMentions),
+		Interactions:         NewInteractions(client, cfg.DefaultAllowedMentions),
 		Emojis:               NewEmojis(client),
 		Stickers:             NewStickers(client),
 		GuildScheduledEvents: NewGuildScheduledEvents(client),
@@ -54,6 +56,7 @@ type restImpl struct {
 	Invites
 	Users
 	Webhooks
+	Interactions
 	Emojis
 	Stickers
 	GuildScheduledEvents
@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"log/slog"
+	"maps"
+	"time"
+)
+
+func defaultConfig() config {
+	return config{
+		Logger:        slog.Default(),
+		StorePath:     "scheduler.json",
+		MaxAttempts:   5,
+		RetryDelay:    30 * time.Second,
+		MaxRetryDelay: time.Hour,
+		JobTimeout:    30 * time.Second,
+		Handlers:      defaultJobHandlers(),
+	}
+}
+
+type config struct {
+	Logger        *slog.Logger
+	Store         Store
+	StorePath     string
+	MaxAttempts   int
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+	JobTimeout    time.Duration
+	Handlers      map[string]JobHandler
+	OnJobFailed   func(job Job, err error)
+}
+
+// ConfigOpt is a functional option for configuring a Scheduler.
+type ConfigOpt func(config *config)
+
+func (c *config) apply(opts []ConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.Logger = c.Logger.With(slog.String("name", "scheduler"))
+}
+
+// WithLogger overrides the default Logger in the config.
+func WithLogger(logger *slog.Logger) ConfigOpt {
+	return func(config *config) {
+		config.Logger = logger
+	}
+}
+
+// WithStore sets the Store the Job(s) are persisted in. The default is a file Store at the path set by WithStorePath.
+func WithStore(store Store) ConfigOpt {
+	return func(config *config) {
+		config.Store = store
+	}
+}
+
+// WithStorePath sets the path of the default file Store. The default is "scheduler.json".
+func WithStorePath(path string) ConfigOpt {
+	return func(config *config) {
+		config.StorePath = path
+	}
+}
+
+// WithMaxAttempts sets how often a Job is run before it is given up. The default is 5.
+func WithMaxAttempts(maxAttempts int) ConfigOpt {
+	return func(config *config) {
+		config.MaxAttempts = maxAttempts
+	}
+}
+
+// WithRetryDelay sets the delay before the first retry of a failed Job, which doubles with every attempt up to the max delay.
+// The defaults are 30s and 1h.
+func WithRetryDelay(delay time.Duration, maxDelay time.Duration) ConfigOpt {
+	return func(config *config) {
+		config.RetryDelay = delay
+		config.MaxRetryDelay = maxDelay
+	}
+}
+
+// WithJobTimeout sets the timeout of a single run of a Job. The default is 30s.
+func WithJobTimeout(timeout time.Duration) ConfigOpt {
+	return func(config *config) {
+		config.JobTimeout = timeout
+	}
+}
+
+// WithJobHandler registers the JobHandler for the given job type. It overrides built-in JobHandler(s).
+func WithJobHandler(jobType string, handler JobHandler) ConfigOpt {
+	return func(config *config) {
+		config.Handlers = maps.Clone(config.Handlers)
+		config.Handlers[jobType] = handler
+	}
+}
+
+// WithOnJobFailed sets a func which is called when a Job failed permanently or ran out of attempts and was removed.
+func WithOnJobFailed(onJobFailed func(job Job, err error)) ConfigOpt {
+	return func(config *config) {
+		config.OnJobFailed = onJobFailed
+	}
+}
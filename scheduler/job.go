@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/fluxer"
+	"github.com/fluxergo/fluxergo/rest"
+)
+
+// Built-in job types
+const (
+	JobTypeDeleteBan        = "delete_ban"
+	JobTypeRemoveMemberRole = "remove_member_role"
+	JobTypeCreateMessage    = "create_message"
+)
+
+// Job is a persisted task which runs at RunAt.
+type Job struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"created_at"`
+	RunAt     time.Time       `json:"run_at"`
+	// Attempts is the number of failed runs.
+	Attempts  int    `json:"attempts,omitempty"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Decode unmarshals the Data of the Job into v.
+func (j Job) Decode(v any) error {
+	return json.Unmarshal(j.Data, v)
+}
+
+// JobData is the data of a Job. It is marshalled to JSON and passed to the JobHandler registered for its JobType.
+type JobData interface {
+	JobType() string
+}
+
+// JobHandler runs a Job. Returned errors are retried unless they are permanent, for example a rest.Error with a 4xx status code.
+type JobHandler func(ctx context.Context, rest rest.Rest, job Job) error
+
+// NewJobHandler returns a JobHandler which decodes the Data of the Job into T before calling f.
+func NewJobHandler[T JobData](f func(ctx context.Context, rest rest.Rest, data T) error) JobHandler {
+	return func(ctx context.Context, rest rest.Rest, job Job) error {
+		var data T
+		if err := job.Decode(&data); err != nil {
+			return permanent(err)
+		}
+		return f(ctx, rest, data)
+	}
+}
+
+var _ JobData = (*DeleteBan)(nil)
+
+// DeleteBan unbans the user from the guild, for example to end a temporary ban.
+type DeleteBan struct {
+	GuildID snowflake.ID `json:"guild_id"`
+	UserID  snowflake.ID `json:"user_id"`
+}
+
+func (DeleteBan) JobType() string {
+	return JobTypeDeleteBan
+}
+
+var _ JobData = (*RemoveMemberRole)(nil)
+
+// RemoveMemberRole removes the role from the member, for example to end a temporary role.
+type RemoveMemberRole struct {
+	GuildID snowflake.ID `json:"guild_id"`
+	UserID  snowflake.ID `json:"user_id"`
+	RoleID  snowflake.ID `json:"role_id"`
+}
+
+func (RemoveMemberRole) JobType() string {
+	return JobTypeRemoveMemberRole
+}
+
+var _ JobData = (*CreateMessage)(nil)
+
+// CreateMessage sends the message to the channel, for example as a reminder. Files are not persisted.
+type CreateMessage struct {
+	ChannelID snowflake.ID         `json:"channel_id"`
+	Message   fluxer.MessageCreate `json:"message"`
+}
+
+func (CreateMessage) JobType() string {
+	return JobTypeCreateMessage
+}
+
+func defaultJobHandlers() map[string]JobHandler {
+	return map[string]JobHandler{
+		JobTypeDeleteBan: NewJobHandler(func(ctx context.Context, client rest.Rest, data DeleteBan) error {
+			return client.DeleteBan(data.GuildID, data.UserID, rest.WithCtx(ctx))
+		}),
+		JobTypeRemoveMemberRole: NewJobHandler(func(ctx context.Context, client rest.Rest, data RemoveMemberRole) error {
+			return client.RemoveMemberRole(data.GuildID, data.UserID, data.RoleID, rest.WithCtx(ctx))
+		}),
+		JobTypeCreateMessage: NewJobHandler(func(ctx context.Context, client rest.Rest, data CreateMessage) error {
+			_, err := client.CreateMessage(data.ChannelID, data.Message, rest.WithCtx(ctx))
+			return err
+		}),
+	}
+}
@@ -0,0 +1,369 @@
+// Package scheduler runs persisted, delayed tasks like lifting temporary bans or sending reminders through rest.Rest.
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/fluxergo/fluxergo/internal/insecurerandstr"
+	"github.com/fluxergo/fluxergo/rest"
+)
+
+var (
+	// ErrJobNotFound is returned by Scheduler.Cancel if no Job with the given ID is scheduled.
+	ErrJobNotFound = errors.New("job not found")
+	// ErrNoJobHandler is passed to the OnJobFailed func if no JobHandler is registered for the type of a Job.
+	ErrNoJobHandler = errors.New("no job handler registered")
+	// ErrSchedulerClosed is returned by Scheduler.Schedule after the Scheduler was closed.
+	ErrSchedulerClosed = errors.New("scheduler closed")
+)
+
+// PermanentError marks an error returned by a JobHandler as permanent, so the Job is not retried.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+func permanent(err error) error {
+	return &PermanentError{Err: err}
+}
+
+// isPermanent returns whether the error should not be retried.
+// This is the case for a PermanentError and rest.Error(s) with a 4xx status code other than 429.
+func isPermanent(err error) bool {
+	var permanentErr *PermanentError
+	if errors.As(err, &permanentErr) {
+		return true
+	}
+	var restErr *rest.Error
+	if errors.As(err, &restErr) && restErr.Response != nil {
+		status := restErr.Response.StatusCode
+		return status >= 400 && status < 500 && status != http.StatusTooManyRequests
+	}
+	return false
+}
+
+var _ Scheduler = (*schedulerImpl)(nil)
+
+// New returns a new Scheduler running the Job(s) of its Store through the given rest.Rest.
+// Job(s) which became due while the Scheduler was not running are run immediately.
+func New(client rest.Rest, opts ...ConfigOpt) (Scheduler, error) {
+	cfg := defaultConfig()
+	cfg.apply(opts)
+
+	if cfg.Store == nil {
+		store, err := NewFileStore(cfg.StorePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open scheduler store: %w", err)
+		}
+		cfg.Store = store
+	}
+	jobs, err := cfg.Store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load scheduled jobs: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &schedulerImpl{
+		config:  cfg,
+		rest:    client,
+		ctx:     ctx,
+		cancel:  cancel,
+		jobs:    make(map[string]*scheduledJob, len(jobs)),
+		wake:    make(chan struct{}, 1),
+		closed:  make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	for _, job := range jobs {
+		s.jobs[job.ID] = &scheduledJob{Job: job}
+	}
+	go s.run()
+	return s, nil
+}
+
+// Scheduler runs Job(s) at a given time and persists them in a Store, so they survive restarts.
+// Failed Job(s) are retried with an exponential backoff.
+type Scheduler interface {
+	// Schedule persists a new Job with the given JobData which runs at runAt.
+	Schedule(runAt time.Time, data JobData) (Job, error)
+
+	// ScheduleIn persists a new Job with the given JobData which runs after the delay.
+	ScheduleIn(delay time.Duration, data JobData) (Job, error)
+
+	// Cancel removes the Job with the given ID. A run which already started is not interrupted.
+	Cancel(id string) error
+
+	// Job returns the Job with the given ID.
+	Job(id string) (Job, bool)
+
+	// Jobs returns all scheduled Job(s) ordered by their run time.
+	Jobs() []Job
+
+	// Close stops running new Job(s), waits until running Job(s) returned or the context is done and closes the Store.
+	// Job(s) still running when the context is done are cancelled and kept without counting the attempt.
+	Close(ctx context.Context) error
+}
+
+type scheduledJob struct {
+	Job
+	running bool
+}
+
+type schedulerImpl struct {
+	config config
+	rest   rest.Rest
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	jobs    map[string]*scheduledJob
+	running sync.WaitGroup
+
+	wake      chan struct{}
+	closeOnce sync.Once
+	closed    chan struct{}
+	stopped   chan struct{}
+}
+
+func (s *schedulerImpl) Schedule(runAt time.Time, data JobData) (Job, error) {
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return Job{}, fmt.Errorf("failed to marshal job data: %w", err)
+	}
+	job := Job{
+		ID:        insecurerandstr.RandStr(16),
+		Type:      data.JobType(),
+		Data:      rawData,
+		CreatedAt: time.Now(),
+		RunAt:     runAt,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.closed:
+		return Job{}, ErrSchedulerClosed
+	default:
+	}
+	if err = s.config.Store.Save(job); err != nil {
+		return Job{}, fmt.Errorf("failed to persist job: %w", err)
+	}
+	s.jobs[job.ID] = &scheduledJob{Job: job}
+	s.notify()
+	return job, nil
+}
+
+func (s *schedulerImpl) ScheduleIn(delay time.Duration, data JobData) (Job, error) {
+	return s.Schedule(time.Now().Add(delay), data)
+}
+
+func (s *schedulerImpl) Cancel(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[id]; !ok {
+		return ErrJobNotFound
+	}
+	if err := s.config.Store.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete job: %w", err)
+	}
+	delete(s.jobs, id)
+	s.notify()
+	return nil
+}
+
+func (s *schedulerImpl) Job(id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return job.Job, true
+}
+
+func (s *schedulerImpl) Jobs() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make(map[string]Job, len(s.jobs))
+	for id, job := range s.jobs {
+		jobs[id] = job.Job
+	}
+	return sortedJobs(jobs)
+}
+
+func (s *schedulerImpl) Close(ctx context.Context) error {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		close(s.closed)
+		s.mu.Unlock()
+	})
+	<-s.stopped
+
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	// cancel the remaining Job(s) and wait for them to return before the Store is closed
+	s.cancel()
+	<-done
+	return s.config.Store.Close()
+}
+
+func (s *schedulerImpl) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *schedulerImpl) run() {
+	defer close(s.stopped)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			s.runDue()
+		case <-s.wake:
+		case <-s.closed:
+			return
+		}
+
+		s.mu.Lock()
+		next, ok := s.nextRunAt()
+		s.mu.Unlock()
+		timer.Stop()
+		if ok {
+			timer.Reset(max(time.Until(next), 0))
+		}
+	}
+}
+
+// nextRunAt returns the earliest run time of all Job(s) which are not running.
+func (s *schedulerImpl) nextRunAt() (time.Time, bool) {
+	var (
+		next time.Time
+		ok   bool
+	)
+	for _, job := range s.jobs {
+		if !job.running && (!ok || job.RunAt.Before(next)) {
+			next = job.RunAt
+			ok = true
+		}
+	}
+	return next, ok
+}
+
+func (s *schedulerImpl) runDue() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for _, job := range s.jobs {
+		if job.running || job.RunAt.After(now) {
+			continue
+		}
+		job.running = true
+		s.running.Add(1)
+		go s.execute(job.Job)
+	}
+}
+
+func (s *schedulerImpl) execute(job Job) {
+	defer s.running.Done()
+	err := s.runJob(job)
+	if failed, ok := s.finish(job, err); ok && s.config.OnJobFailed != nil {
+		s.config.OnJobFailed(failed, err)
+	}
+}
+
+// finish removes the Job if it completed or failed permanently and reschedules it otherwise.
+// It returns the Job and true if it failed permanently.
+func (s *schedulerImpl) finish(job Job, err error) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.notify()
+	scheduled, ok := s.jobs[job.ID]
+	if !ok {
+		// the job was cancelled while running
+		return Job{}, false
+	}
+	scheduled.running = false
+
+	if err != nil && s.ctx.Err() != nil {
+		// the scheduler is closing, so the job is run again on the next start
+		s.config.Logger.Debug("job interrupted by close", slog.String("job_id", job.ID), slog.String("job_type", job.Type))
+		return Job{}, false
+	}
+
+	if err == nil {
+		s.config.Logger.Debug("job completed", slog.String("job_id", job.ID), slog.String("job_type", job.Type))
+		s.remove(job.ID)
+		return Job{}, false
+	}
+
+	scheduled.Attempts++
+	scheduled.LastError = err.Error()
+	if isPermanent(err) || scheduled.Attempts >= s.config.MaxAttempts {
+		s.config.Logger.Error("job failed", slog.String("job_id", job.ID), slog.String("job_type", job.Type), slog.Int("attempts", scheduled.Attempts), slog.Any("err", err))
+		s.remove(job.ID)
+		return scheduled.Job, true
+	}
+
+	delay := s.config.RetryDelay
+	for i := 1; i < scheduled.Attempts && delay < s.config.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, s.config.MaxRetryDelay)
+	scheduled.RunAt = time.Now().Add(delay)
+	s.config.Logger.Warn("job failed, retrying", slog.String("job_id", job.ID), slog.String("job_type", job.Type), slog.Int("attempts", scheduled.Attempts), slog.Duration("retry_in", delay), slog.Any("err", err))
+	if err = s.config.Store.Save(scheduled.Job); err != nil {
+		s.config.Logger.Error("failed to persist job", slog.String("job_id", job.ID), slog.Any("err", err))
+	}
+	return Job{}, false
+}
+
+func (s *schedulerImpl) runJob(job Job) (err error) {
+	handler, ok := s.config.Handlers[job.Type]
+	if !ok {
+		return permanent(fmt.Errorf("%w for job type %q", ErrNoJobHandler, job.Type))
+	}
+
+	ctx := s.ctx
+	if s.config.JobTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.config.JobTimeout)
+		defer cancel()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job handler panicked: %v", r)
+		}
+	}()
+	return handler(ctx, s.rest, job)
+}
+
+func (s *schedulerImpl) remove(id string) {
+	delete(s.jobs, id)
+	if err := s.config.Store.Delete(id); err != nil {
+		s.config.Logger.Error("failed to delete job from store", slog.String("job_id", id), slog.Any("err", err))
+	}
+}
@@ -0,0 +1,171 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fluxergo/fluxergo/rest"
+)
+
+type testJob struct {
+	Name string `json:"name"`
+}
+
+func (testJob) JobType() string {
+	return "test"
+}
+
+func TestSchedulerRetry(t *testing.T) {
+	var runs atomic.Int32
+	done := make(chan string, 1)
+	s, err := New(nil,
+		WithStore(NewMemoryStore()),
+		WithRetryDelay(time.Millisecond, time.Millisecond),
+		WithJobHandler("test", NewJobHandler(func(_ context.Context, _ rest.Rest, data testJob) error {
+			if runs.Add(1) < 3 {
+				return errors.New("temporary error")
+			}
+			done <- data.Name
+			return nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close(context.Background())
+
+	job, err := s.ScheduleIn(10*time.Millisecond, testJob{Name: "reminder"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case name := <-done:
+		if name != "reminder" {
+			t.Errorf("expected job data reminder, got %s", name)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("job did not run")
+	}
+	if runs.Load() != 3 {
+		t.Errorf("expected 3 runs, got %d", runs.Load())
+	}
+
+	// the job is removed after the handler returned
+	time.Sleep(10 * time.Millisecond)
+	if _, ok := s.Job(job.ID); ok {
+		t.Error("expected completed job to be removed")
+	}
+}
+
+func TestSchedulerPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+
+	s, err := New(nil, WithStorePath(path), WithJobHandler("test", func(context.Context, rest.Rest, Job) error {
+		t.Error("job should not run")
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, err := s.ScheduleIn(time.Hour, testJob{Name: "kept"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancelled, err := s.ScheduleIn(time.Hour, testJob{Name: "cancelled"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Cancel(cancelled.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Cancel(cancelled.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+	_ = s.Close(context.Background())
+
+	s, err = New(nil, WithStorePath(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close(context.Background())
+
+	jobs := s.Jobs()
+	if len(jobs) != 1 || jobs[0].ID != kept.ID {
+		t.Fatalf("expected only job %s after restart, got %v", kept.ID, jobs)
+	}
+	var data testJob
+	if err = jobs[0].Decode(&data); err != nil || data.Name != "kept" {
+		t.Errorf("expected job data kept, got %v (%v)", data, err)
+	}
+}
+
+func TestSchedulerUnknownJobType(t *testing.T) {
+	failed := make(chan error, 1)
+	s, err := New(nil, WithStore(NewMemoryStore()), WithOnJobFailed(func(_ Job, err error) {
+		failed <- err
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close(context.Background())
+
+	if _, err = s.Schedule(time.Now(), testJob{}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err = <-failed:
+		if !errors.Is(err, ErrNoJobHandler) {
+			t.Errorf("expected ErrNoJobHandler, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("job did not fail")
+	}
+	if len(s.Jobs()) != 0 {
+		t.Error("expected failed job to be removed")
+	}
+}
+
+func TestSchedulerCloseInterruptsJobs(t *testing.T) {
+	store := NewMemoryStore()
+	started := make(chan struct{})
+	s, err := New(nil,
+		WithStore(store),
+		WithMaxAttempts(1),
+		WithJobHandler("test", func(ctx context.Context, _ rest.Rest, _ Job) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+		WithOnJobFailed(func(job Job, err error) {
+			t.Errorf("expected interrupted job not to fail, got %v", err)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := s.ScheduleIn(0, testJob{Name: "interrupted"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err = s.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the job is kept for the next start without counting the interrupted attempt
+	jobs, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != job.ID || jobs[0].Attempts != 0 {
+		t.Errorf("expected job to be kept unchanged, got %+v", jobs)
+	}
+}
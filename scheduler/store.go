@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Store persists the Job(s) of a Scheduler.
+type Store interface {
+	// Load returns all stored Job(s).
+	Load() ([]Job, error)
+	// Save adds or replaces the Job.
+	Save(job Job) error
+	// Delete removes the Job with the given ID.
+	Delete(id string) error
+	// Close closes the Store.
+	Close() error
+}
+
+var _ Store = (*memoryStore)(nil)
+
+// NewMemoryStore returns a new Store which keeps the Job(s) in memory. They are lost on restart.
+func NewMemoryStore() Store {
+	return &memoryStore{jobs: make(map[string]Job)}
+}
+
+type memoryStore struct {
+	mu   sync.Mutex
+	jobs map[string]Job
+}
+
+func (s *memoryStore) Load() ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedJobs(s.jobs), nil
+}
+
+func (s *memoryStore) Save(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = job
+	return nil
+}
+
+func (s *memoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, id)
+	return nil
+}
+
+func (s *memoryStore) Close() error {
+	return nil
+}
+
+var _ Store = (*fileStore)(nil)
+
+// NewFileStore returns a new Store which keeps all Job(s) in a JSON file at the given path.
+// The file is replaced atomically on every change, so it is meant for a moderate amount of Job(s).
+func NewFileStore(path string) (Store, error) {
+	s := &fileStore{path: path, jobs: make(map[string]Job)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []Job
+	if len(data) > 0 {
+		if err = json.Unmarshal(data, &jobs); err != nil {
+			return nil, err
+		}
+	}
+	for _, job := range jobs {
+		s.jobs[job.ID] = job
+	}
+	return s, nil
+}
+
+type fileStore struct {
+	path string
+	mu   sync.Mutex
+	jobs map[string]Job
+}
+
+func (s *fileStore) Load() ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedJobs(s.jobs), nil
+}
+
+func (s *fileStore) Save(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = job
+	return s.write()
+}
+
+func (s *fileStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[id]; !ok {
+		return nil
+	}
+	delete(s.jobs, id)
+	return s.write()
+}
+
+func (s *fileStore) Close() error {
+	return nil
+}
+
+// write replaces the file with all Job(s) by writing a temporary file and renaming it.
+func (s *fileStore) write() error {
+	data, err := json.Marshal(sortedJobs(s.jobs))
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func sortedJobs(jobs map[string]Job) []Job {
+	sorted := make([]Job, 0, len(jobs))
+	for _, job := range jobs {
+		sorted = append(sorted, job)
+	}
+	slices.SortFunc(sorted, func(a, b Job) int {
+		if c := a.RunAt.Compare(b.RunAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return sorted
+}
@@ -0,0 +1,60 @@
+package voice
+
+import (
+	"strings"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/pion/rtp"
+)
+
+// AudioReceiver receives the audio of the participants in the voice channel. Set it with LiveKitConn.SetAudioReceiver.
+// OpusWriter is an AudioReceiver which writes all opus frames to an io.Writer.
+type AudioReceiver interface {
+	// ParticipantJoin is called when a participant joined the voice channel or already was in it when the AudioReceiver was set.
+	ParticipantJoin(userID snowflake.ID)
+
+	// ReceiveOpusFrame is called for every opus packet a participant sent.
+	ReceiveOpusFrame(userID snowflake.ID, packet *Packet) error
+
+	// CleanupUser is called when a participant left the voice channel.
+	CleanupUser(userID snowflake.ID)
+
+	// Close is called when the AudioReceiver was replaced or the LiveKitConn was closed.
+	Close()
+}
+
+// newPacket converts a rtp.Packet of an opus track to a Packet.
+func newPacket(pkt *rtp.Packet) *Packet {
+	packet := &Packet{
+		Type:         pkt.PayloadType,
+		Sequence:     pkt.SequenceNumber,
+		Timestamp:    pkt.Timestamp,
+		SSRC:         pkt.SSRC,
+		HasExtension: pkt.Extension,
+		CSRC:         pkt.CSRC,
+		HeaderSize:   pkt.Header.MarshalSize(),
+		Opus:         pkt.Payload,
+	}
+	if ids := pkt.GetExtensionIDs(); len(ids) > 0 {
+		packet.ExtensionID = int(ids[0])
+		packet.Extension = pkt.GetExtension(ids[0])
+	}
+	return packet
+}
+
+// participantUserID returns the user ID of a LiveKit participant identity.
+// The identity is either the user ID itself or contains it as the first numeric part separated by '_' or ':'.
+func participantUserID(identity string) (snowflake.ID, bool) {
+	for part := range strings.FieldsFuncSeq(identity, func(r rune) bool { return r == '_' || r == ':' }) {
+		if userID, err := snowflake.Parse(part); err == nil && userID != 0 {
+			return userID, true
+		}
+	}
+	return 0, false
+}
+
+// splitStreamID splits the stream ID of a remote track into the participant sid and track sid.
+func splitStreamID(streamID string) (string, string) {
+	participantSid, trackSid, _ := strings.Cut(streamID, "|")
+	return participantSid, trackSid
+}
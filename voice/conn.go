@@ -2,6 +2,7 @@ package voice
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"sync"
 	"time"
@@ -11,113 +12,206 @@ import (
 	"github.com/fluxergo/fluxergo/gateway"
 )
 
+// ErrCallChannelMismatch is returned by Conn.Open if a Conn of a call is opened with another channel.
+var ErrCallChannelMismatch = errors.New("channel does not match the channel of the call")
+
+// ConnTarget identifies the Conn of a guild or of a call in a fluxer.DMChannel or fluxer.GroupDMChannel.
+type ConnTarget struct {
+	// GuildID is the ID of the guild. It is zero for calls.
+	GuildID snowflake.ID
+	// ChannelID is the ID of the private channel of a call. It is zero for guilds, as the Conn can move between the voice channels of a guild.
+	ChannelID snowflake.ID
+}
+
+// GuildTarget returns the ConnTarget of the voice channels of a guild.
+func GuildTarget(guildID snowflake.ID) ConnTarget {
+	return ConnTarget{GuildID: guildID}
+}
+
+// CallTarget returns the ConnTarget of a call in a fluxer.DMChannel or fluxer.GroupDMChannel.
+func CallTarget(channelID snowflake.ID) ConnTarget {
+	return ConnTarget{ChannelID: channelID}
+}
+
+// IsCall returns whether the ConnTarget is a call in a private channel.
+func (t ConnTarget) IsCall() bool {
+	return t.GuildID == 0
+}
+
+// matches returns whether a voice state or server update with the given guild and channel belongs to the ConnTarget.
+// Voice state updates of calls without a channel match all calls, as they are sent when leaving any call.
+func (t ConnTarget) matches(guildID snowflake.ID, channelID *snowflake.ID) bool {
+	if !t.IsCall() {
+		return guildID == t.GuildID
+	}
+	return guildID == 0 && (channelID == nil || *channelID == t.ChannelID)
+}
+
+// ConnStatus is the status of a Conn.
+type ConnStatus int
+
+const (
+	// ConnStatusDisconnected means the Conn is not in a voice channel.
+	ConnStatusDisconnected ConnStatus = iota
+	// ConnStatusJoining means the voice state was updated and the Conn waits for the voice server.
+	ConnStatusJoining
+	// ConnStatusConnecting means the Conn opens the LiveKitConn for the first time.
+	ConnStatusConnecting
+	// ConnStatusConnected means the LiveKitConn is open.
+	ConnStatusConnected
+	// ConnStatusReconnecting means the Conn opens the LiveKitConn again, because the voice server or channel changed or opening it failed.
+	ConnStatusReconnecting
+)
+
 type (
 	// ConnCreateFunc is a type alias for a function that creates a new Conn.
-	ConnCreateFunc func(liveKitCon LiveKitConn, guildID snowflake.ID, userID snowflake.ID, voiceStateUpdateFunc StateUpdateFunc, removeConnFunc func(), opts ...ConnConfigOpt) Conn
+	ConnCreateFunc func(liveKitCon LiveKitConn, target ConnTarget, userID snowflake.ID, voiceStateUpdateFunc StateUpdateFunc, removeConnFunc func(), opts ...ConnConfigOpt) Conn
+
+	// ConnStatusChangeFunc is called when the ConnStatus of a Conn changes.
+	ConnStatusChangeFunc func(conn Conn, oldStatus ConnStatus, newStatus ConnStatus)
+
+	// ConnEventListener is called for every ConnEvent of a Conn except LiveKitStatusChange, which is reflected in the ConnStatus.
+	ConnEventListener func(conn Conn, event ConnEvent)
 
 	// Conn is a complete voice conn to fluxer. It holds the Gateway and voiceudp.UDPConn conn and combines them.
 	Conn interface {
 		LiveKit() LiveKitConn
 
+		// SetAudioReceiver sets the AudioReceiver which receives the audio of the participants. See LiveKitConn.SetAudioReceiver.
+		SetAudioReceiver(receiver AudioReceiver, userFilter UserFilterFunc)
+
+		// Status returns the ConnStatus of the Conn.
+		Status() ConnStatus
+
 		// ChannelID returns the ID of the voice channel the voice Conn is openedChan to.
 		ChannelID() *snowflake.ID
 
-		// GuildID returns the ID of the guild the voice Conn is openedChan to.
+		// GuildID returns the ID of the guild the voice Conn is openedChan to. It is zero for calls.
 		GuildID() snowflake.ID
 
+		// Target returns the ConnTarget of the Conn.
+		Target() ConnTarget
+
 		// Open opens the voice conn. It will connect to the voice gateway and start the Conn conn after it receives the Gateway events.
+		// For calls, the channelID has to be the channel of the ConnTarget.
+		// It blocks until the status is ConnStatusConnected or the context is done, so it can be opened again after the server disconnected it.
 		Open(ctx context.Context, channelID snowflake.ID) error
 
 		// Close closes the voice conn. It will close the Conn conn and disconnect from the voice gateway.
 		Close(ctx context.Context)
 
 		// HandleVoiceStateUpdate provides the gateway.EventVoiceStateUpdate to the voice conn. Which is needed to connect to the voice Gateway.
+		// The LiveKitConn is only opened again if the voice server or channel changed.
 		HandleVoiceStateUpdate(update gateway.EventVoiceStateUpdate)
 
 		// HandleVoiceServerUpdate provides the gateway.EventVoiceServerUpdate to the voice conn. Which is needed to connect to the voice Gateway.
+		// An already open LiveKitConn is opened again if the endpoint or token changed.
 		HandleVoiceServerUpdate(update gateway.EventVoiceServerUpdate)
 	}
 )
 
 // NewConn returns a new default voice conn.
-func NewConn(liveKitCon LiveKitConn, guildID snowflake.ID, userID snowflake.ID, voiceStateUpdateFunc StateUpdateFunc, removeConnFunc func(), opts ...ConnConfigOpt) Conn {
+func NewConn(liveKitCon LiveKitConn, target ConnTarget, userID snowflake.ID, voiceStateUpdateFunc StateUpdateFunc, removeConnFunc func(), opts ...ConnConfigOpt) Conn {
 	cfg := defaultConnConfig()
 	cfg.apply(opts)
 
-	openedCtx, openedCancel := context.WithCancel(context.Background())
-	closedCtx, closedCancel := context.WithCancel(context.Background())
-
-	return &connImpl{
+	c := &connImpl{
 		config:               cfg,
 		voiceStateUpdateFunc: voiceStateUpdateFunc,
 		removeConnFunc:       removeConnFunc,
+		target:               target,
 		state: State{
-			GuildID: guildID,
+			GuildID: target.GuildID,
 			UserID:  userID,
 		},
-		openedCtx:   openedCtx,
-		opened:      openedCancel,
-		closedCtx:   closedCtx,
-		closed:      closedCancel,
+		statusCh:    make(chan struct{}),
 		liveKitConn: liveKitCon,
 	}
+	liveKitCon.SetEventFunc(c.handleLiveKitEvent)
+	return c
 }
 
 type connImpl struct {
 	config               connConfig
 	voiceStateUpdateFunc StateUpdateFunc
 	removeConnFunc       func()
-
-	state   State
-	stateMu sync.Mutex
-
+	target               ConnTarget
+
+	// stateMu guards state, status, statusCh, liveKitState and cancelConnect
+	state  State
+	status ConnStatus
+	// statusCh is closed and replaced on every status change, see waitStatus
+	statusCh chan struct{}
+	stateMu  sync.Mutex
+	// liveKitState is the State the LiveKitConn was last opened with. Only the endpoint and token are compared.
+	liveKitState  *State
+	cancelConnect context.CancelFunc
+
+	// openMu serializes opening and closing the LiveKitConn
+	openMu      sync.Mutex
 	liveKitConn LiveKitConn
-
-	openedCtx context.Context
-	opened    context.CancelFunc
-	closedCtx context.Context
-	closed    context.CancelFunc
 }
 
 func (c *connImpl) LiveKit() LiveKitConn {
 	return c.liveKitConn
 }
 
+func (c *connImpl) SetAudioReceiver(receiver AudioReceiver, userFilter UserFilterFunc) {
+	c.liveKitConn.SetAudioReceiver(receiver, userFilter)
+}
+
+func (c *connImpl) Status() ConnStatus {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	return c.status
+}
+
 func (c *connImpl) ChannelID() *snowflake.ID {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
 	return c.state.ChannelID
 }
 
 func (c *connImpl) GuildID() snowflake.ID {
-	return c.state.GuildID
+	return c.target.GuildID
+}
+
+func (c *connImpl) Target() ConnTarget {
+	return c.target
 }
 
 func (c *connImpl) HandleVoiceStateUpdate(update gateway.EventVoiceStateUpdate) {
 	c.stateMu.Lock()
-	defer c.stateMu.Unlock()
-	if update.GuildID != c.state.GuildID || update.UserID != c.state.UserID {
+	if update.UserID != c.state.UserID || !c.target.matches(update.GuildID, update.ChannelID) {
+		c.stateMu.Unlock()
 		return
 	}
 
 	c.state.SessionID = update.SessionID
 	if update.ChannelID == nil {
 		c.state.ChannelID = nil
-		c.liveKitConn.Close()
-		c.closed()
-	} else {
-		c.state.ChannelID = update.ChannelID
+		c.stateMu.Unlock()
+		c.leave()
+		return
+	}
 
-		if err := c.liveKitConn.Open(c.state); err != nil {
-			c.config.Logger.Error("error connecting to voice", slog.Any("err", err))
-			return
-		}
-		c.opened()
+	if c.state.ChannelID != nil && *c.state.ChannelID != *update.ChannelID {
+		c.config.Logger.Debug("moved to another voice channel", slog.String("channel_id", update.ChannelID.String()))
+	}
+	c.state.ChannelID = update.ChannelID
+
+	// mute and deafen changes don't require a new LiveKit connection
+	if c.state.Endpoint == "" || !c.liveKitStateChanged() {
+		c.stateMu.Unlock()
+		return
 	}
+	c.connect()
 }
 
 func (c *connImpl) HandleVoiceServerUpdate(update gateway.EventVoiceServerUpdate) {
 	c.stateMu.Lock()
-	defer c.stateMu.Unlock()
-	if update.GuildID != c.state.GuildID || update.Endpoint == nil {
+	if !c.target.matches(update.GuildID, &update.ChannelID) || update.Endpoint == nil {
+		c.stateMu.Unlock()
 		return
 	}
 
@@ -125,37 +219,242 @@ func (c *connImpl) HandleVoiceServerUpdate(update gateway.EventVoiceServerUpdate
 	c.state.ConnectionID = update.ConnectionID
 	c.state.Endpoint = *update.Endpoint
 	c.state.ChannelID = &update.ChannelID
+	connectionID := update.ConnectionID
+	data := gateway.MessageDataVoiceStateUpdate{
+		GuildID:      c.state.GuildID,
+		ChannelID:    c.state.ChannelID,
+		ConnectionID: &connectionID,
+	}
+
+	// the first connection is opened after the voice state update, already open connections move to the new server right away
+	if c.liveKitState != nil && c.liveKitStateChanged() {
+		c.config.Logger.Debug("voice server changed", slog.String("endpoint", c.state.Endpoint))
+		c.connect()
+	} else {
+		c.stateMu.Unlock()
+	}
 
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
-		if err := c.voiceStateUpdateFunc(ctx, gateway.MessageDataVoiceStateUpdate{
-			GuildID:      c.state.GuildID,
-			ChannelID:    c.state.ChannelID,
-			ConnectionID: &c.state.ConnectionID,
-		}); err != nil {
+		if err := c.voiceStateUpdateFunc(ctx, data); err != nil {
 			c.config.Logger.Error("error sending voice state update to connect to voice gateway", slog.Any("err", err))
 		}
 	}()
 }
 
+// liveKitStateChanged returns whether the LiveKitConn has to be opened with the current State. c.stateMu must be held.
+func (c *connImpl) liveKitStateChanged() bool {
+	return c.liveKitState == nil || c.liveKitState.Endpoint != c.state.Endpoint || c.liveKitState.Token != c.state.Token
+}
+
+// connect cancels a running connect loop and opens the LiveKitConn with the current State until it succeeds.
+// c.stateMu must be held and is unlocked by connect.
+func (c *connImpl) connect() {
+	if c.cancelConnect != nil {
+		c.cancelConnect()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancelConnect = cancel
+
+	status := ConnStatusConnecting
+	if c.liveKitState != nil {
+		status = ConnStatusReconnecting
+	}
+	state := c.state
+	c.liveKitState = &state
+	oldStatus := c.updateStatus(status)
+	c.stateMu.Unlock()
+
+	c.statusChanged(oldStatus, status)
+	go c.connectLoop(ctx, state)
+}
+
+func (c *connImpl) connectLoop(ctx context.Context, state State) {
+	delay := c.config.ReconnectDelay
+	for {
+		c.openMu.Lock()
+		if ctx.Err() != nil {
+			c.openMu.Unlock()
+			return
+		}
+		err := c.liveKitConn.Open(state)
+		c.openMu.Unlock()
+
+		if err == nil {
+			c.connected(ctx)
+			return
+		}
+
+		c.config.Logger.Error("error connecting to voice", slog.Any("err", err), slog.Duration("retry_in", delay))
+		c.setStatus(ctx, ConnStatusReconnecting)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, c.config.MaxReconnectDelay)
+	}
+}
+
+// connected finishes the connect loop of ctx unless it was cancelled in the meantime.
+func (c *connImpl) connected(ctx context.Context) {
+	c.stateMu.Lock()
+	if ctx.Err() != nil {
+		c.stateMu.Unlock()
+		return
+	}
+	c.cancelConnect()
+	c.cancelConnect = nil
+	oldStatus := c.updateStatus(ConnStatusConnected)
+	c.stateMu.Unlock()
+
+	c.statusChanged(oldStatus, ConnStatusConnected)
+}
+
+func (c *connImpl) handleLiveKitEvent(event ConnEvent) {
+	if e, ok := event.(LiveKitStatusChange); ok {
+		c.handleLiveKitStatus(e.NewStatus)
+		return
+	}
+	c.dispatch(event)
+}
+
+// handleLiveKitStatus reflects the reconnects of an open LiveKitConn in the ConnStatus and opens it again if the server disconnected it.
+func (c *connImpl) handleLiveKitStatus(status Status) {
+	c.stateMu.Lock()
+	// while connecting and after leaving, the status is managed by the connect loop and leave
+	if c.cancelConnect != nil || c.liveKitState == nil {
+		c.stateMu.Unlock()
+		return
+	}
+
+	if status == StatusDisconnected {
+		c.config.Logger.Debug("livekit disconnected, connecting again")
+		c.connect()
+		return
+	}
+	newStatus := ConnStatusReconnecting
+	if status == StatusConnected {
+		newStatus = ConnStatusConnected
+	}
+	oldStatus := c.updateStatus(newStatus)
+	c.stateMu.Unlock()
+
+	c.statusChanged(oldStatus, newStatus)
+}
+
+// leave stops connecting and closes the LiveKitConn.
+func (c *connImpl) leave() {
+	c.stateMu.Lock()
+	if c.cancelConnect != nil {
+		c.cancelConnect()
+		c.cancelConnect = nil
+	}
+	c.liveKitState = nil
+	oldStatus := c.updateStatus(ConnStatusDisconnected)
+	c.stateMu.Unlock()
+
+	c.openMu.Lock()
+	c.liveKitConn.Close()
+	c.openMu.Unlock()
+
+	c.statusChanged(oldStatus, ConnStatusDisconnected)
+}
+
+// setStatus sets the status unless the connect loop of ctx was cancelled in the meantime.
+func (c *connImpl) setStatus(ctx context.Context, status ConnStatus) {
+	c.stateMu.Lock()
+	if ctx.Err() != nil {
+		c.stateMu.Unlock()
+		return
+	}
+	oldStatus := c.updateStatus(status)
+	c.stateMu.Unlock()
+
+	c.statusChanged(oldStatus, status)
+}
+
+// compareAndSetStatus sets the status if it is the expected status and returns whether it was set.
+func (c *connImpl) compareAndSetStatus(expected ConnStatus, status ConnStatus) bool {
+	c.stateMu.Lock()
+	if c.status != expected {
+		c.stateMu.Unlock()
+		return false
+	}
+	c.updateStatus(status)
+	c.stateMu.Unlock()
+
+	c.statusChanged(expected, status)
+	return true
+}
+
+// updateStatus sets the status, wakes up all waitStatus calls and returns the old status. c.stateMu must be held.
+func (c *connImpl) updateStatus(status ConnStatus) ConnStatus {
+	oldStatus := c.status
+	if oldStatus != status {
+		c.status = status
+		close(c.statusCh)
+		c.statusCh = make(chan struct{})
+	}
+	return oldStatus
+}
+
+// waitStatus blocks until the Conn has the given status or the context is done.
+func (c *connImpl) waitStatus(ctx context.Context, status ConnStatus) error {
+	for {
+		c.stateMu.Lock()
+		if c.status == status {
+			c.stateMu.Unlock()
+			return nil
+		}
+		statusCh := c.statusCh
+		c.stateMu.Unlock()
+
+		select {
+		case <-statusCh:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *connImpl) statusChanged(oldStatus ConnStatus, newStatus ConnStatus) {
+	if oldStatus == newStatus {
+		return
+	}
+	c.config.Logger.Debug("voice conn status changed", slog.Int("from", int(oldStatus)), slog.Int("to", int(newStatus)))
+	for _, statusChangeFunc := range c.config.StatusChangeFuncs {
+		statusChangeFunc(c, oldStatus, newStatus)
+	}
+	c.dispatch(ConnStatusChange{OldStatus: oldStatus, NewStatus: newStatus})
+}
+
+func (c *connImpl) dispatch(event ConnEvent) {
+	for _, listener := range c.config.EventListeners {
+		listener(c, event)
+	}
+}
+
 func (c *connImpl) Open(ctx context.Context, channelID snowflake.ID) error {
 	c.config.Logger.Debug("opening voice conn")
+	if c.target.IsCall() && channelID != c.target.ChannelID {
+		return ErrCallChannelMismatch
+	}
 
+	joining := c.compareAndSetStatus(ConnStatusDisconnected, ConnStatusJoining)
 	if err := c.voiceStateUpdateFunc(ctx, gateway.MessageDataVoiceStateUpdate{
 		GuildID:      c.state.GuildID,
 		ChannelID:    &channelID,
 		ConnectionID: nil,
 	}); err != nil {
+		if joining {
+			c.compareAndSetStatus(ConnStatusJoining, ConnStatusDisconnected)
+		}
 		return err
 	}
 
-	select {
-	case <-c.openedCtx.Done():
-		return nil
-	case <-ctx.Done():
-		return ctx.Err()
-	}
+	return c.waitStatus(ctx, ConnStatusConnected)
 }
 
 func (c *connImpl) Close(ctx context.Context) {
@@ -164,11 +463,8 @@ func (c *connImpl) Close(ctx context.Context) {
 	}); err != nil {
 		c.config.Logger.Error("error sending voice state update to close voice conn", slog.Any("err", err))
 	}
-	defer c.liveKitConn.Close()
+	defer c.leave()
 
-	select {
-	case <-c.closedCtx.Done():
-	case <-ctx.Done():
-	}
+	_ = c.waitStatus(ctx, ConnStatusDisconnected)
 	c.removeConnFunc()
 }
@@ -2,16 +2,23 @@ package voice
 
 import (
 	"log/slog"
+	"time"
 )
 
 func defaultConnConfig() connConfig {
 	return connConfig{
-		Logger: slog.Default(),
+		Logger:            slog.Default(),
+		ReconnectDelay:    time.Second,
+		MaxReconnectDelay: 30 * time.Second,
 	}
 }
 
 type connConfig struct {
-	Logger *slog.Logger
+	Logger            *slog.Logger
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+	StatusChangeFuncs []ConnStatusChangeFunc
+	EventListeners    []ConnEventListener
 }
 
 // ConnConfigOpt is used to functionally configure a connConfig.
@@ -30,3 +37,25 @@ func WithConnLogger(logger *slog.Logger) ConnConfigOpt {
 		config.Logger = logger
 	}
 }
+
+// WithConnReconnectDelay sets the initial and maximum delay between attempts to open the LiveKitConn. The delay doubles after each failed attempt.
+func WithConnReconnectDelay(delay time.Duration, maxDelay time.Duration) ConnConfigOpt {
+	return func(config *connConfig) {
+		config.ReconnectDelay = delay
+		config.MaxReconnectDelay = maxDelay
+	}
+}
+
+// WithConnStatusChangeFunc adds a ConnStatusChangeFunc which is called whenever the ConnStatus of the Conn changes.
+func WithConnStatusChangeFunc(statusChangeFunc ConnStatusChangeFunc) ConnConfigOpt {
+	return func(config *connConfig) {
+		config.StatusChangeFuncs = append(config.StatusChangeFuncs, statusChangeFunc)
+	}
+}
+
+// WithConnEventListener adds a ConnEventListener which receives the ConnStatusChange, SpeakingStart, SpeakingStop, ParticipantJoin and ParticipantLeave events of the Conn.
+func WithConnEventListener(listener ConnEventListener) ConnConfigOpt {
+	return func(config *connConfig) {
+		config.EventListeners = append(config.EventListeners, listener)
+	}
+}
@@ -0,0 +1,306 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/fluxergo/fluxergo/gateway"
+)
+
+var _ LiveKitConn = (*fakeLiveKitConn)(nil)
+
+// fakeLiveKitConn records the States it was opened with and fails the first openFailures calls to Open.
+type fakeLiveKitConn struct {
+	frames chan []byte
+
+	mu           sync.Mutex
+	opens        []State
+	openFailures int
+	closes       int
+	eventFunc    ConnEventFunc
+}
+
+func (c *fakeLiveKitConn) Open(state State) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.opens = append(c.opens, state)
+	if c.openFailures > 0 {
+		c.openFailures--
+		return errors.New("open failed")
+	}
+	return nil
+}
+
+func (c *fakeLiveKitConn) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closes++
+}
+
+func (c *fakeLiveKitConn) openStates() []State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]State(nil), c.opens...)
+}
+
+func (*fakeLiveKitConn) Status() Status { return StatusConnected }
+
+func (*fakeLiveKitConn) SetAudioReceiver(AudioReceiver, UserFilterFunc) {}
+
+func (*fakeLiveKitConn) Participants() []snowflake.ID { return nil }
+
+func (c *fakeLiveKitConn) SetEventFunc(eventFunc ConnEventFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.eventFunc = eventFunc
+}
+
+func (c *fakeLiveKitConn) emit(event ConnEvent) {
+	c.mu.Lock()
+	eventFunc := c.eventFunc
+	c.mu.Unlock()
+	eventFunc(event)
+}
+
+func (c *fakeLiveKitConn) AudioWriter(string, AudioSource) (io.WriteCloser, error) {
+	return &fakeTrackWriter{frames: c.frames}, nil
+}
+
+func (*fakeLiveKitConn) VideoWriter(string, VideoSource, int, int, int) (io.WriteCloser, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func (*fakeLiveKitConn) VideoCodecWriter(string, VideoSource, VideoCodec, int, int, int) (io.WriteCloser, error) {
+	return nil, errors.ErrUnsupported
+}
+
+type fakeTrackWriter struct {
+	frames chan []byte
+}
+
+func (w *fakeTrackWriter) Write(p []byte) (int, error) {
+	w.frames <- bytes.Clone(p)
+	return len(p), nil
+}
+
+func (*fakeTrackWriter) Close() error { return nil }
+
+func waitConnStatus(t *testing.T, statuses chan ConnStatus, expected ...ConnStatus) {
+	t.Helper()
+	for _, status := range expected {
+		select {
+		case s := <-statuses:
+			if s != status {
+				t.Fatalf("expected status %d, got %d", status, s)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for status %d", status)
+		}
+	}
+}
+
+func TestConnStateMachine(t *testing.T) {
+	const (
+		guildID  = snowflake.ID(1)
+		userID   = snowflake.ID(2)
+		channelA = snowflake.ID(10)
+		channelB = snowflake.ID(11)
+	)
+
+	liveKitConn := &fakeLiveKitConn{openFailures: 2}
+	stateUpdates := make(chan gateway.MessageDataVoiceStateUpdate, 16)
+	statuses := make(chan ConnStatus, 16)
+	events := make(chan ConnEvent, 16)
+	conn := NewConn(liveKitConn, GuildTarget(guildID), userID,
+		func(_ context.Context, data gateway.MessageDataVoiceStateUpdate) error {
+			stateUpdates <- data
+			return nil
+		},
+		func() {},
+		WithConnReconnectDelay(time.Millisecond, 4*time.Millisecond),
+		WithConnStatusChangeFunc(func(_ Conn, _ ConnStatus, newStatus ConnStatus) {
+			statuses <- newStatus
+		}),
+		WithConnEventListener(func(_ Conn, event ConnEvent) {
+			if _, ok := event.(ConnStatusChange); !ok {
+				events <- event
+			}
+		}),
+	)
+
+	serverUpdate := func(channelID snowflake.ID, endpoint string, token string) {
+		conn.HandleVoiceServerUpdate(gateway.EventVoiceServerUpdate{
+			ChannelID:    channelID,
+			ConnectionID: "connection",
+			Endpoint:     &endpoint,
+			GuildID:      guildID,
+			Token:        token,
+		})
+		if data := <-stateUpdates; data.ConnectionID == nil || *data.ChannelID != channelID {
+			t.Fatalf("unexpected voice state update: %+v", data)
+		}
+	}
+	stateUpdate := func(channelID *snowflake.ID, selfMute bool) {
+		var update gateway.EventVoiceStateUpdate
+		update.GuildID = guildID
+		update.UserID = userID
+		update.ChannelID = channelID
+		update.SelfMute = selfMute
+		update.SessionID = "session"
+		conn.HandleVoiceStateUpdate(update)
+	}
+	channelID := func(id snowflake.ID) *snowflake.ID {
+		return &id
+	}
+
+	opened := make(chan error, 1)
+	go func() {
+		opened <- conn.Open(context.Background(), channelA)
+	}()
+	waitConnStatus(t, statuses, ConnStatusJoining)
+	<-stateUpdates
+
+	// the LiveKitConn is opened after the voice state update and retried until it succeeds
+	serverUpdate(channelA, "endpoint-a", "token-a")
+	if opens := liveKitConn.openStates(); len(opens) != 0 {
+		t.Fatalf("expected no open before the voice state update, got %d", len(opens))
+	}
+	stateUpdate(channelID(channelA), false)
+	waitConnStatus(t, statuses, ConnStatusConnecting, ConnStatusReconnecting, ConnStatusConnected)
+	if err := <-opened; err != nil {
+		t.Fatal(err)
+	}
+	if opens := liveKitConn.openStates(); len(opens) != 3 || opens[2].Endpoint != "endpoint-a" || opens[2].SessionID != "session" {
+		t.Fatalf("unexpected opens: %+v", opens)
+	}
+
+	// mute changes keep the connection
+	stateUpdate(channelID(channelA), true)
+	if opens := liveKitConn.openStates(); len(opens) != 3 {
+		t.Fatalf("expected no open after mute, got %d opens", len(opens))
+	}
+
+	// a new endpoint reconnects right away
+	serverUpdate(channelA, "endpoint-b", "token-b")
+	waitConnStatus(t, statuses, ConnStatusReconnecting, ConnStatusConnected)
+	if opens := liveKitConn.openStates(); len(opens) != 4 || opens[3].Endpoint != "endpoint-b" {
+		t.Fatalf("unexpected opens: %+v", opens)
+	}
+
+	// a channel move opens the LiveKitConn with the token of the new room
+	stateUpdate(channelID(channelB), false)
+	serverUpdate(channelB, "endpoint-b", "token-c")
+	waitConnStatus(t, statuses, ConnStatusReconnecting, ConnStatusConnected)
+	if opens := liveKitConn.openStates(); len(opens) != 5 || opens[4].Token != "token-c" || *opens[4].ChannelID != channelB {
+		t.Fatalf("unexpected opens: %+v", opens)
+	}
+	if id := conn.ChannelID(); id == nil || *id != channelB {
+		t.Fatalf("expected channel %d, got %v", channelB, id)
+	}
+
+	// events of the LiveKitConn are reflected in the status or passed to the listeners
+	liveKitConn.emit(LiveKitStatusChange{OldStatus: StatusConnected, NewStatus: StatusConnecting})
+	liveKitConn.emit(LiveKitStatusChange{OldStatus: StatusConnecting, NewStatus: StatusConnected})
+	waitConnStatus(t, statuses, ConnStatusReconnecting, ConnStatusConnected)
+	liveKitConn.emit(SpeakingStart{UserID: 3, Level: 1})
+	if event := <-events; event != (SpeakingStart{UserID: 3, Level: 1}) {
+		t.Fatalf("unexpected event: %#v", event)
+	}
+
+	// a LiveKitConn disconnected by the server is opened again
+	liveKitConn.emit(LiveKitStatusChange{OldStatus: StatusConnected, NewStatus: StatusDisconnected})
+	waitConnStatus(t, statuses, ConnStatusReconnecting, ConnStatusConnected)
+	if opens := liveKitConn.openStates(); len(opens) != 6 {
+		t.Fatalf("expected 6 opens, got %d", len(opens))
+	}
+
+	stateUpdate(nil, false)
+	waitConnStatus(t, statuses, ConnStatusDisconnected)
+	if status := conn.Status(); status != ConnStatusDisconnected {
+		t.Fatalf("expected disconnected status, got %d", status)
+	}
+
+	// after the server disconnected the conn, Open waits for the next connection again
+	go func() {
+		opened <- conn.Open(context.Background(), channelA)
+	}()
+	waitConnStatus(t, statuses, ConnStatusJoining)
+	<-stateUpdates
+	select {
+	case err := <-opened:
+		t.Fatalf("expected Open to wait for the connection, got %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	serverUpdate(channelA, "endpoint-a", "token-d")
+	stateUpdate(channelID(channelA), false)
+	waitConnStatus(t, statuses, ConnStatusConnecting, ConnStatusConnected)
+	if err := <-opened; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestManagerCall(t *testing.T) {
+	const (
+		userID    = snowflake.ID(2)
+		channelID = snowflake.ID(20)
+	)
+
+	liveKitConn := &fakeLiveKitConn{}
+	stateUpdates := make(chan gateway.MessageDataVoiceStateUpdate, 16)
+	statuses := make(chan ConnStatus, 16)
+	var manager Manager
+	manager = NewManager(func(_ context.Context, data gateway.MessageDataVoiceStateUpdate) error {
+		stateUpdates <- data
+		return nil
+	}, userID,
+		WithLiveKitConnCreateFunc(func() LiveKitConn { return liveKitConn }),
+		WithConnConfigOpts(WithConnStatusChangeFunc(func(conn Conn, _ ConnStatus, newStatus ConnStatus) {
+			// status callbacks must be able to use the manager
+			_ = manager.GetConn(conn.Target())
+			statuses <- newStatus
+		})),
+	)
+
+	conn := manager.CreateConn(CallTarget(channelID))
+	if err := conn.Open(context.Background(), channelID+1); !errors.Is(err, ErrCallChannelMismatch) {
+		t.Fatalf("expected ErrCallChannelMismatch, got %v", err)
+	}
+
+	go func() {
+		_ = conn.Open(context.Background(), channelID)
+	}()
+	waitConnStatus(t, statuses, ConnStatusJoining)
+	if data := <-stateUpdates; data.GuildID != 0 || *data.ChannelID != channelID {
+		t.Fatalf("unexpected voice state update: %+v", data)
+	}
+
+	endpoint := "endpoint"
+	manager.HandleVoiceServerUpdate(gateway.EventVoiceServerUpdate{
+		ChannelID:    channelID,
+		ConnectionID: "connection",
+		Endpoint:     &endpoint,
+		Token:        "token",
+	})
+	<-stateUpdates
+
+	var update gateway.EventVoiceStateUpdate
+	update.UserID = userID
+	update.ChannelID = &[]snowflake.ID{channelID}[0]
+	manager.HandleVoiceStateUpdate(update)
+	waitConnStatus(t, statuses, ConnStatusConnecting, ConnStatusConnected)
+
+	// leaving a call has neither a guild nor a channel
+	update.ChannelID = nil
+	manager.HandleVoiceStateUpdate(update)
+	waitConnStatus(t, statuses, ConnStatusDisconnected)
+	if got := manager.GetConn(CallTarget(channelID)); got != conn {
+		t.Fatal("expected call conn to be registered by its channel")
+	}
+}
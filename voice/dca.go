@@ -0,0 +1,252 @@
+package voice
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+var (
+	dcaMagic = []byte("DCA1")
+
+	// ErrInvalidDCAFrame is returned if a frame of a DCA stream has an invalid length.
+	ErrInvalidDCAFrame = errors.New("invalid dca frame")
+)
+
+// DCAMetadata is the JSON metadata of a DCA1 file. See https://github.com/bwmarrin/dca/wiki/DCA1-specification.
+type DCAMetadata struct {
+	DCA    DCAInfo         `json:"dca"`
+	Opus   DCAOpusInfo     `json:"opus"`
+	Info   *DCASongInfo    `json:"info,omitempty"`
+	Origin *DCAOriginInfo  `json:"origin,omitempty"`
+	Extra  json.RawMessage `json:"extra,omitempty"`
+}
+
+// DCAInfo describes the DCA version and the tool which created the file.
+type DCAInfo struct {
+	Version int         `json:"version"`
+	Tool    DCAToolInfo `json:"tool"`
+}
+
+// DCAToolInfo describes the tool which created the file.
+type DCAToolInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	URL     string `json:"url,omitempty"`
+	Author  string `json:"author,omitempty"`
+}
+
+// DCAOpusInfo describes the opus encoding of the frames.
+type DCAOpusInfo struct {
+	Mode       string `json:"mode"`
+	SampleRate int    `json:"sample_rate"`
+	FrameSize  int    `json:"frame_size"`
+	ABR        *int   `json:"abr"`
+	VBR        bool   `json:"vbr"`
+	Channels   int    `json:"channels"`
+}
+
+// DCASongInfo describes the encoded song.
+type DCASongInfo struct {
+	Title    string  `json:"title,omitempty"`
+	Artist   string  `json:"artist,omitempty"`
+	Album    string  `json:"album,omitempty"`
+	Genre    string  `json:"genre,omitempty"`
+	Comments string  `json:"comments,omitempty"`
+	Cover    *string `json:"cover,omitempty"`
+}
+
+// DCAOriginInfo describes the source the file was encoded from.
+type DCAOriginInfo struct {
+	Source   string  `json:"source,omitempty"`
+	ABR      *int    `json:"abr,omitempty"`
+	Channels int     `json:"channels,omitempty"`
+	Encoding *string `json:"encoding,omitempty"`
+	URL      *string `json:"url,omitempty"`
+}
+
+func defaultDCAMetadata() *DCAMetadata {
+	return &DCAMetadata{
+		DCA: DCAInfo{
+			Version: 1,
+			Tool: DCAToolInfo{
+				Name:    "fluxergo",
+				Version: "1.0.0",
+				URL:     "https://github.com/fluxergo/fluxergo",
+			},
+		},
+		Opus: DCAOpusInfo{
+			Mode:       "voip",
+			SampleRate: oggOpusSampleRate,
+			FrameSize:  OpusFrameSize,
+			VBR:        true,
+			Channels:   2,
+		},
+	}
+}
+
+// NewDCAReader returns a new DCAReader that reads opus frames from the given io.Reader.
+// The header of DCA1 streams is read immediately. Streams without a header are read as legacy DCA0 streams.
+func NewDCAReader(r io.Reader) (*DCAReader, error) {
+	br := bufio.NewReader(r)
+	reader := &DCAReader{r: br}
+
+	magic, err := br.Peek(len(dcaMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error while reading dca header: %w", err)
+	}
+	if !bytes.Equal(magic, dcaMagic) {
+		return reader, nil
+	}
+	_, _ = br.Discard(len(dcaMagic))
+
+	var size int32
+	if err = binary.Read(br, binary.LittleEndian, &size); err != nil {
+		return nil, fmt.Errorf("error while reading dca metadata size: %w", unexpectedEOF(err))
+	}
+	if size < 0 {
+		return nil, fmt.Errorf("invalid dca metadata size: %d", size)
+	}
+	data := make([]byte, size)
+	if _, err = io.ReadFull(br, data); err != nil {
+		return nil, fmt.Errorf("error while reading dca metadata: %w", unexpectedEOF(err))
+	}
+	var metadata DCAMetadata
+	if err = json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("error while decoding dca metadata: %w", err)
+	}
+	reader.metadata = &metadata
+	return reader, nil
+}
+
+// DCAReader reads the opus frames of DCA files, which are used by many music bots.
+// Each opus frame is prefixed with a 2 byte little endian int16 that represents the length of the frame.
+type DCAReader struct {
+	r        *bufio.Reader
+	metadata *DCAMetadata
+	lenBuff  [2]byte
+	buff     [OpusFrameSizeBytes]byte
+}
+
+// Metadata returns the DCAMetadata of a DCA1 stream or nil for a legacy DCA0 stream.
+func (r *DCAReader) Metadata() *DCAMetadata {
+	return r.metadata
+}
+
+// ProvideOpusFrame reads the next opus frame from the underlying io.Reader.
+func (r *DCAReader) ProvideOpusFrame() ([]byte, error) {
+	if _, err := io.ReadFull(r.r, r.lenBuff[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("error while reading dca frame length: %w", err)
+	}
+
+	frameLen := int16(binary.LittleEndian.Uint16(r.lenBuff[:]))
+	if frameLen <= 0 || int(frameLen) > len(r.buff) {
+		return nil, fmt.Errorf("%w: length %d", ErrInvalidDCAFrame, frameLen)
+	}
+	if _, err := io.ReadFull(r.r, r.buff[:frameLen]); err != nil {
+		return nil, fmt.Errorf("error while reading dca frame: %w", unexpectedEOF(err))
+	}
+	return r.buff[:frameLen], nil
+}
+
+// Close is a no-op.
+func (*DCAReader) Close() {}
+
+var _ AudioReceiver = (*DCAWriter)(nil)
+
+// NewDCAWriter returns a new DCAWriter that writes a DCA1 stream with the given DCAMetadata to the given io.Writer.
+// If metadata is nil, metadata for 48 kHz stereo 20ms frames is written.
+func NewDCAWriter(w io.Writer, metadata *DCAMetadata, userFilter UserFilterFunc) *DCAWriter {
+	if metadata == nil {
+		metadata = defaultDCAMetadata()
+	}
+	return &DCAWriter{
+		w:          w,
+		metadata:   metadata,
+		userFilter: userFilter,
+	}
+}
+
+// DCAWriter writes opus frames as DCA1 stream. The header is written before the first frame.
+// It is an AudioReceiver which writes the frames of all users accepted by its UserFilterFunc, so it is usually filtered to a single user.
+type DCAWriter struct {
+	w          io.Writer
+	metadata   *DCAMetadata
+	userFilter UserFilterFunc
+
+	mu            sync.Mutex
+	headerWritten bool
+}
+
+// Write writes a single opus frame.
+func (w *DCAWriter) Write(p []byte) (int, error) {
+	if err := w.WriteFrame(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// WriteFrame writes a single opus frame.
+func (w *DCAWriter) WriteFrame(frame []byte) error {
+	if len(frame) == 0 || len(frame) > 1<<15-1 {
+		return fmt.Errorf("%w: length %d", ErrInvalidDCAFrame, len(frame))
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	buf := make([]byte, 2, 2+len(frame))
+	binary.LittleEndian.PutUint16(buf, uint16(len(frame)))
+	if _, err := w.w.Write(append(buf, frame...)); err != nil {
+		return fmt.Errorf("error while writing dca frame: %w", err)
+	}
+	return nil
+}
+
+func (w *DCAWriter) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+	data, err := json.Marshal(w.metadata)
+	if err != nil {
+		return fmt.Errorf("error while encoding dca metadata: %w", err)
+	}
+	buf := make([]byte, 0, len(dcaMagic)+4+len(data))
+	buf = append(buf, dcaMagic...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(data)))
+	buf = append(buf, data...)
+	if _, err = w.w.Write(buf); err != nil {
+		return fmt.Errorf("error while writing dca header: %w", err)
+	}
+	w.headerWritten = true
+	return nil
+}
+
+// ParticipantJoin is a no-op.
+func (*DCAWriter) ParticipantJoin(_ snowflake.ID) {}
+
+// ReceiveOpusFrame writes the opus frame of the packet if the user is accepted by the UserFilterFunc.
+func (w *DCAWriter) ReceiveOpusFrame(userID snowflake.ID, packet *Packet) error {
+	if w.userFilter != nil && !w.userFilter(userID) {
+		return nil
+	}
+	return w.WriteFrame(packet.Opus)
+}
+
+// CleanupUser is a no-op.
+func (*DCAWriter) CleanupUser(_ snowflake.ID) {}
+
+// Close is a no-op.
+func (*DCAWriter) Close() {}
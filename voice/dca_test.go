@@ -0,0 +1,106 @@
+package voice
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+func readDCAFrames(t *testing.T, r *DCAReader) [][]byte {
+	t.Helper()
+	var frames [][]byte
+	for {
+		frame, err := r.ProvideOpusFrame()
+		if errors.Is(err, io.EOF) {
+			return frames
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		frames = append(frames, bytes.Clone(frame))
+	}
+}
+
+func TestDCAReader(t *testing.T) {
+	legacy, err := os.Open("testdata/nico_legacy.dca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer legacy.Close()
+	legacyReader, err := NewDCAReader(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if legacyReader.Metadata() != nil {
+		t.Fatal("expected no metadata for dca0 file")
+	}
+	legacyFrames := readDCAFrames(t, legacyReader)
+	if len(legacyFrames) != 50 {
+		t.Fatalf("expected 50 frames, got %d", len(legacyFrames))
+	}
+
+	f, err := os.Open("testdata/nico.dca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := NewDCAReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata := r.Metadata()
+	if metadata == nil || metadata.DCA.Version != 1 || metadata.Opus.FrameSize != 960 || metadata.Opus.Channels != 2 || metadata.Info.Title != "Nico" {
+		t.Fatalf("unexpected metadata: %+v", metadata)
+	}
+	frames := readDCAFrames(t, r)
+	if len(frames) != len(legacyFrames) {
+		t.Fatalf("expected %d frames, got %d", len(legacyFrames), len(frames))
+	}
+	for i := range frames {
+		if !bytes.Equal(frames[i], legacyFrames[i]) {
+			t.Fatalf("frame %d differs", i)
+		}
+	}
+}
+
+func TestDCAWriter(t *testing.T) {
+	legacy, err := os.ReadFile("testdata/nico_legacy.dca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacyReader, err := NewDCAReader(bytes.NewReader(legacy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	frames := readDCAFrames(t, legacyReader)
+
+	const userID = snowflake.ID(1)
+	buf := &bytes.Buffer{}
+	w := NewDCAWriter(buf, nil, func(id snowflake.ID) bool { return id == userID })
+	for _, frame := range frames {
+		if err = w.ReceiveOpusFrame(userID, &Packet{Opus: frame}); err != nil {
+			t.Fatal(err)
+		}
+		if err = w.ReceiveOpusFrame(2, &Packet{Opus: frame}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !bytes.HasPrefix(buf.Bytes(), dcaMagic) || !bytes.HasSuffix(buf.Bytes(), legacy) {
+		t.Fatal("expected dca1 header followed by the frames of the user")
+	}
+	r, err := NewDCAReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata := r.Metadata(); metadata == nil || metadata.Opus.SampleRate != 48000 {
+		t.Fatalf("unexpected metadata: %+v", metadata)
+	}
+	if written := readDCAFrames(t, r); len(written) != len(frames) {
+		t.Fatalf("expected %d frames, got %d", len(frames), len(written))
+	}
+}
@@ -0,0 +1,146 @@
+package voice
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	h264NALTypeSlice    = 1
+	h264NALTypeIDRSlice = 5
+	h264NALTypeSEI      = 6
+	h264NALTypeSPS      = 7
+	h264NALTypePPS      = 8
+	h264NALTypeAUD      = 9
+)
+
+var h264StartCode = []byte{0x00, 0x00, 0x00, 0x01}
+
+// NewH264Reader returns a new H264Reader that reads the access units of the H.264 Annex-B stream of the given io.Reader.
+// Annex-B streams have no timestamps, so the VideoFrame timestamps are based on the given fps.
+func NewH264Reader(r io.Reader, fps int) (*H264Reader, error) {
+	if fps <= 0 {
+		return nil, fmt.Errorf("invalid fps: %d", fps)
+	}
+	return &H264Reader{
+		r:             bufio.NewReader(r),
+		frameDuration: time.Second / time.Duration(fps),
+	}, nil
+}
+
+// H264Reader is a VideoFrameProvider that reads a H.264 Annex-B stream, for example the output of ffmpeg with "-f h264".
+// Every VideoFrame contains one access unit, which are all NAL units of a picture prefixed with a start code.
+type H264Reader struct {
+	r             *bufio.Reader
+	frameDuration time.Duration
+	frames        int
+	started       bool
+
+	nal        []byte
+	pending    []byte
+	hasPending bool
+	frame      []byte
+}
+
+// ProvideVideoFrame reads the next access unit from the underlying io.Reader.
+func (r *H264Reader) ProvideVideoFrame() (VideoFrame, error) {
+	r.frame = r.frame[:0]
+	var hasSlice bool
+	for {
+		var nal []byte
+		if r.hasPending {
+			nal = r.pending
+			r.hasPending = false
+		} else {
+			var err error
+			nal, err = r.readNAL()
+			if errors.Is(err, io.EOF) {
+				if len(r.frame) > 0 {
+					break
+				}
+				return VideoFrame{}, io.EOF
+			}
+			if err != nil {
+				return VideoFrame{}, fmt.Errorf("error while reading h264 nal unit: %w", err)
+			}
+		}
+		if len(nal) == 0 {
+			continue
+		}
+
+		if hasSlice && h264StartsAccessUnit(nal) {
+			// the NAL unit belongs to the next access unit
+			r.pending = append(r.pending[:0], nal...)
+			r.hasPending = true
+			break
+		}
+		if nalType := nal[0] & 0x1f; nalType == h264NALTypeSlice || nalType == h264NALTypeIDRSlice {
+			hasSlice = true
+		}
+		r.frame = append(r.frame, h264StartCode...)
+		r.frame = append(r.frame, nal...)
+	}
+
+	frame := VideoFrame{
+		Data:      r.frame,
+		Timestamp: time.Duration(r.frames) * r.frameDuration,
+	}
+	r.frames++
+	return frame, nil
+}
+
+// readNAL reads the next NAL unit without its start code. The returned NAL unit is only valid until the next call.
+func (r *H264Reader) readNAL() ([]byte, error) {
+	r.nal = r.nal[:0]
+	var zeros int
+	for {
+		b, err := r.r.ReadByte()
+		if errors.Is(err, io.EOF) {
+			if r.started && len(r.nal) > 0 {
+				// trailing zero bytes are not part of the NAL unit
+				return r.nal[:len(r.nal)-zeros], nil
+			}
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if b == 0x01 && zeros >= 2 {
+			nal := r.nal[:len(r.nal)-zeros]
+			if r.started {
+				return nal, nil
+			}
+			// everything before the first start code is skipped
+			r.started = true
+			r.nal = r.nal[:0]
+			zeros = 0
+			continue
+		}
+
+		r.nal = append(r.nal, b)
+		if b == 0x00 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+	}
+}
+
+// h264StartsAccessUnit returns whether the NAL unit starts a new access unit if it follows a slice as described in ITU-T H.264 section 7.4.1.2.3.
+func h264StartsAccessUnit(nal []byte) bool {
+	switch nal[0] & 0x1f {
+	case h264NALTypeSEI, h264NALTypeSPS, h264NALTypePPS, h264NALTypeAUD, 14, 15, 16, 17, 18:
+		return true
+	case h264NALTypeSlice, h264NALTypeIDRSlice:
+		// first_mb_in_slice is 0 for the first slice of a picture, which is encoded as a single 1 bit
+		return len(nal) > 1 && nal[1]&0x80 != 0
+	}
+	return false
+}
+
+// Close is a no-op.
+func (*H264Reader) Close() {}
@@ -0,0 +1,139 @@
+package voice
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+const (
+	ivfHeaderSize      = 32
+	ivfFrameHeaderSize = 12
+	// ivfMaxFrameSize limits the size of a single frame, so corrupt streams don't allocate huge buffers.
+	ivfMaxFrameSize = 16 << 20
+)
+
+var (
+	ivfMagic = []byte("DKIF")
+
+	// ErrInvalidIVFStream is returned if the header or a frame of an IVF stream is malformed.
+	ErrInvalidIVFStream = errors.New("invalid ivf stream")
+
+	// ErrUnsupportedIVFCodec is returned by IVFHeader.Codec if the stream is neither VP8 nor VP9.
+	ErrUnsupportedIVFCodec = errors.New("unsupported ivf codec")
+)
+
+// IVFHeader is the file header of an IVF stream.
+type IVFHeader struct {
+	// FourCC identifies the codec of the stream, for example "VP80" or "VP90".
+	FourCC string
+	Width  uint16
+	Height uint16
+	// TimebaseDenominator and TimebaseNumerator define the unit of the frame timestamps in seconds.
+	TimebaseDenominator uint32
+	TimebaseNumerator   uint32
+	// FrameCount is the number of frames in the stream. It is 0 for streams of unknown length.
+	FrameCount uint32
+}
+
+// Codec returns the VideoCodec of the stream or ErrUnsupportedIVFCodec.
+func (h IVFHeader) Codec() (VideoCodec, error) {
+	switch h.FourCC {
+	case "VP80":
+		return VideoCodecVP8, nil
+	case "VP90":
+		return VideoCodecVP9, nil
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnsupportedIVFCodec, h.FourCC)
+}
+
+// FPS returns the frame rate of the stream based on its timebase, which most encoders set to the frame rate.
+func (h IVFHeader) FPS() int {
+	if h.TimebaseNumerator == 0 {
+		return 0
+	}
+	return int(math.Round(float64(h.TimebaseDenominator) / float64(h.TimebaseNumerator)))
+}
+
+// NewIVFReader returns a new IVFReader that reads VP8 or VP9 frames from the given io.Reader. The header is read immediately.
+func NewIVFReader(r io.Reader) (*IVFReader, error) {
+	var buf [ivfHeaderSize]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return nil, fmt.Errorf("error while reading ivf header: %w", unexpectedEOF(err))
+	}
+	if !bytes.Equal(buf[:4], ivfMagic) {
+		return nil, fmt.Errorf("%w: missing signature", ErrInvalidIVFStream)
+	}
+	headerSize := binary.LittleEndian.Uint16(buf[6:8])
+	if headerSize < ivfHeaderSize {
+		return nil, fmt.Errorf("%w: header size %d", ErrInvalidIVFStream, headerSize)
+	}
+	if _, err := io.CopyN(io.Discard, r, int64(headerSize-ivfHeaderSize)); err != nil {
+		return nil, fmt.Errorf("error while reading ivf header: %w", unexpectedEOF(err))
+	}
+
+	header := IVFHeader{
+		FourCC:              string(buf[8:12]),
+		Width:               binary.LittleEndian.Uint16(buf[12:14]),
+		Height:              binary.LittleEndian.Uint16(buf[14:16]),
+		TimebaseDenominator: binary.LittleEndian.Uint32(buf[16:20]),
+		TimebaseNumerator:   binary.LittleEndian.Uint32(buf[20:24]),
+		FrameCount:          binary.LittleEndian.Uint32(buf[24:28]),
+	}
+	if header.TimebaseDenominator == 0 || header.TimebaseNumerator == 0 {
+		return nil, fmt.Errorf("%w: timebase %d/%d", ErrInvalidIVFStream, header.TimebaseNumerator, header.TimebaseDenominator)
+	}
+	return &IVFReader{
+		r:      r,
+		header: header,
+	}, nil
+}
+
+// IVFReader is a VideoFrameProvider that reads the frames of an IVF stream, for example the output of ffmpeg with "-f ivf".
+// The VideoFrame timestamps are based on the frame timestamps of the stream.
+type IVFReader struct {
+	r         io.Reader
+	header    IVFHeader
+	headerBuf [ivfFrameHeaderSize]byte
+	buf       []byte
+}
+
+// Header returns the IVFHeader of the stream.
+func (r *IVFReader) Header() IVFHeader {
+	return r.header
+}
+
+// ProvideVideoFrame reads the next frame from the underlying io.Reader.
+func (r *IVFReader) ProvideVideoFrame() (VideoFrame, error) {
+	if _, err := io.ReadFull(r.r, r.headerBuf[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return VideoFrame{}, io.EOF
+		}
+		return VideoFrame{}, fmt.Errorf("error while reading ivf frame header: %w", err)
+	}
+
+	size := binary.LittleEndian.Uint32(r.headerBuf[:4])
+	if size == 0 || size > ivfMaxFrameSize {
+		return VideoFrame{}, fmt.Errorf("%w: frame size %d", ErrInvalidIVFStream, size)
+	}
+	if cap(r.buf) < int(size) {
+		r.buf = make([]byte, size)
+	}
+	r.buf = r.buf[:size]
+	if _, err := io.ReadFull(r.r, r.buf); err != nil {
+		return VideoFrame{}, fmt.Errorf("error while reading ivf frame: %w", unexpectedEOF(err))
+	}
+
+	pts := int64(binary.LittleEndian.Uint64(r.headerBuf[4:]))
+	return VideoFrame{
+		Data:      r.buf,
+		Timestamp: time.Duration(pts) * time.Duration(r.header.TimebaseNumerator) * time.Second / time.Duration(r.header.TimebaseDenominator),
+	}, nil
+}
+
+// Close is a no-op.
+func (*IVFReader) Close() {}
@@ -0,0 +1,1125 @@
+package voice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/gorilla/websocket"
+	"github.com/pion/webrtc/v4"
+	"github.com/pion/webrtc/v4/pkg/media"
+
+	"github.com/fluxergo/fluxergo/internal/insecurerandstr"
+)
+
+// liveKitProtocolVersion is the version of the LiveKit signalling protocol spoken by the built-in LiveKitConn.
+const liveKitProtocolVersion = 15
+
+var (
+	errLiveKitUnexpectedResponse = errors.New("unexpected livekit signal response")
+	errLiveKitPublishTimeout     = errors.New("timed out waiting for livekit to publish the track")
+	errLiveKitNegotiationLost    = errors.New("initial publisher negotiation was interrupted")
+)
+
+var _ LiveKitConn = (*liveKitConnImpl)(nil)
+
+// NewLiveKitConn returns the built-in LiveKitConn. It joins the LiveKit room of the voice server via the LiveKit signalling protocol,
+// publishes the tracks of AudioWriter and VideoWriter over WebRTC and reconnects if the signalling connection drops.
+func NewLiveKitConn(opts ...LiveKitConnConfigOpt) LiveKitConn {
+	cfg := defaultLiveKitConnConfig()
+	cfg.apply(opts)
+
+	return &liveKitConnImpl{
+		config:       cfg,
+		tracks:       make(map[string]*liveKitTrack),
+		participants: make(map[string]snowflake.ID),
+		speaking:     make(map[string]bool),
+	}
+}
+
+type liveKitConnImpl struct {
+	config liveKitConnConfig
+
+	mu     sync.Mutex
+	status Status
+	state  State
+	tracks map[string]*liveKitTrack
+	// participants maps the sid of the other participants to their user ID
+	participants map[string]snowflake.ID
+	// speaking contains the sids of the participants which are speaking
+	speaking map[string]bool
+
+	receiverMu sync.Mutex
+	receiver   AudioReceiver
+	userFilter UserFilterFunc
+
+	eventMu   sync.Mutex
+	eventFunc ConnEventFunc
+
+	// the following fields are only set while the LiveKitConn is opened
+	cancel            context.CancelFunc
+	done              chan struct{}
+	signal            *liveKitSignal
+	publisher         *webrtc.PeerConnection
+	subscriber        *webrtc.PeerConnection
+	participantSid    string
+	pingInterval      time.Duration
+	pingTimeout       time.Duration
+	published         map[string]chan lkTrackInfo
+	pendingCandidates map[*webrtc.PeerConnection][]webrtc.ICECandidateInit
+	negotiating       bool
+	renegotiate       bool
+}
+
+func (c *liveKitConnImpl) Open(state State) error {
+	c.mu.Lock()
+	if c.status != StatusDisconnected && c.state.Endpoint == state.Endpoint && c.state.Token == state.Token {
+		c.mu.Unlock()
+		return nil
+	}
+	c.mu.Unlock()
+	c.disconnect()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	c.mu.Lock()
+	c.state = state
+	c.cancel = cancel
+	c.done = done
+	c.mu.Unlock()
+	c.setStatus(StatusConnecting)
+
+	signal, err := c.join(ctx)
+	if err != nil {
+		cancel()
+		close(done)
+		c.setStatus(StatusDisconnected)
+		return err
+	}
+	go c.run(ctx, signal, done)
+	return nil
+}
+
+func (c *liveKitConnImpl) Close() {
+	c.disconnect()
+
+	c.receiverMu.Lock()
+	defer c.receiverMu.Unlock()
+	if c.receiver != nil {
+		c.receiver.Close()
+		c.receiver = nil
+		c.userFilter = nil
+	}
+}
+
+// disconnect leaves the room and closes the signalling and peer connections.
+func (c *liveKitConnImpl) disconnect() {
+	c.mu.Lock()
+	cancel := c.cancel
+	done := c.done
+	signal := c.signal
+	c.cancel = nil
+	c.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+
+	if signal != nil {
+		if err := signal.send(lkSignalRequest{Leave: &lkLeaveRequest{Action: lkLeaveActionDisconnect}}); err != nil {
+			c.config.Logger.Debug("failed to send leave request", slog.Any("err", err))
+		}
+	}
+	cancel()
+	if signal != nil {
+		signal.close()
+	}
+	<-done
+	c.teardown()
+}
+
+func (c *liveKitConnImpl) Status() Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
+
+func (c *liveKitConnImpl) SetAudioReceiver(receiver AudioReceiver, userFilter UserFilterFunc) {
+	c.receiverMu.Lock()
+	defer c.receiverMu.Unlock()
+	if c.receiver != nil {
+		c.receiver.Close()
+	}
+	c.receiver = receiver
+	c.userFilter = userFilter
+	if receiver == nil {
+		return
+	}
+	for _, userID := range c.Participants() {
+		if userFilter == nil || userFilter(userID) {
+			receiver.ParticipantJoin(userID)
+		}
+	}
+}
+
+func (c *liveKitConnImpl) SetEventFunc(eventFunc ConnEventFunc) {
+	c.eventMu.Lock()
+	defer c.eventMu.Unlock()
+	c.eventFunc = eventFunc
+}
+
+// emit passes the events to the ConnEventFunc. It must not be called while c.mu is held.
+func (c *liveKitConnImpl) emit(events ...ConnEvent) {
+	c.eventMu.Lock()
+	eventFunc := c.eventFunc
+	c.eventMu.Unlock()
+	if eventFunc == nil {
+		return
+	}
+	for _, event := range events {
+		eventFunc(event)
+	}
+}
+
+func (c *liveKitConnImpl) Participants() []snowflake.ID {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	userIDs := make([]snowflake.ID, 0, len(c.participants))
+	for _, userID := range c.participants {
+		if !slices.Contains(userIDs, userID) {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	slices.Sort(userIDs)
+	return userIDs
+}
+
+func (c *liveKitConnImpl) AudioWriter(name string, source AudioSource) (io.WriteCloser, error) {
+	lkSource := lkTrackSourceMicrophone
+	if source == AudioSourceScreenShare {
+		lkSource = lkTrackSourceScreenShareAudio
+	}
+	return c.newTrackWriter(webrtc.RTPCodecCapability{
+		MimeType:  webrtc.MimeTypeOpus,
+		ClockRate: 48000,
+		Channels:  2,
+	}, OpusFrameDuration, lkAddTrackRequest{
+		Name:   name,
+		Type:   lkTrackTypeAudio,
+		Source: lkSource,
+	})
+}
+
+func (c *liveKitConnImpl) VideoWriter(name string, source VideoSource, width int, height int, fps int) (io.WriteCloser, error) {
+	return c.VideoCodecWriter(name, source, VideoCodecH264, width, height, fps)
+}
+
+func (c *liveKitConnImpl) VideoCodecWriter(name string, source VideoSource, codec VideoCodec, width int, height int, fps int) (io.WriteCloser, error) {
+	if fps <= 0 {
+		return nil, fmt.Errorf("invalid fps: %d", fps)
+	}
+	var mimeType string
+	switch codec {
+	case VideoCodecH264:
+		mimeType = webrtc.MimeTypeH264
+	case VideoCodecVP8:
+		mimeType = webrtc.MimeTypeVP8
+	case VideoCodecVP9:
+		mimeType = webrtc.MimeTypeVP9
+	default:
+		return nil, fmt.Errorf("invalid video codec: %d", codec)
+	}
+	lkSource := lkTrackSourceCamera
+	if source == VideoSourceScreenShare {
+		lkSource = lkTrackSourceScreenShare
+	}
+	return c.newTrackWriter(webrtc.RTPCodecCapability{
+		MimeType:  mimeType,
+		ClockRate: 90000,
+	}, time.Second/time.Duration(fps), lkAddTrackRequest{
+		Name:   name,
+		Type:   lkTrackTypeVideo,
+		Width:  uint32(width),
+		Height: uint32(height),
+		Source: lkSource,
+	})
+}
+
+func (c *liveKitConnImpl) newTrackWriter(codec webrtc.RTPCodecCapability, frameDuration time.Duration, req lkAddTrackRequest) (io.WriteCloser, error) {
+	if c.Status() != StatusConnected {
+		return nil, ErrLiveKitNotConnected
+	}
+
+	req.Cid = "TR_" + insecurerandstr.RandStr(12)
+	local, err := webrtc.NewTrackLocalStaticSample(codec, req.Cid, "fluxergo")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create local track: %w", err)
+	}
+	track := &liveKitTrack{
+		req:           req,
+		local:         local,
+		frameDuration: frameDuration,
+	}
+
+	c.mu.Lock()
+	c.tracks[req.Cid] = track
+	c.mu.Unlock()
+	if err = c.publish(track); err != nil {
+		c.mu.Lock()
+		delete(c.tracks, req.Cid)
+		c.mu.Unlock()
+		return nil, err
+	}
+	return &liveKitTrackWriter{conn: c, track: track}, nil
+}
+
+func (c *liveKitConnImpl) setStatus(status Status) {
+	c.mu.Lock()
+	oldStatus := c.status
+	if oldStatus == status {
+		c.mu.Unlock()
+		return
+	}
+	c.config.Logger.Debug("livekit status changed", slog.Int("from", int(oldStatus)), slog.Int("to", int(status)))
+	c.status = status
+	c.mu.Unlock()
+
+	c.emit(LiveKitStatusChange{OldStatus: oldStatus, NewStatus: status})
+}
+
+// run handles the signalling connection until the LiveKitConn is closed or the server disconnects it.
+// If the signalling connection drops, it resumes the session or joins the room again.
+func (c *liveKitConnImpl) run(ctx context.Context, signal *liveKitSignal, done chan<- struct{}) {
+	defer close(done)
+	for {
+		action := c.listen(ctx, signal)
+		if ctx.Err() != nil {
+			return
+		}
+		if action == lkLeaveActionDisconnect {
+			c.config.Logger.Debug("disconnected by livekit server")
+			c.teardown()
+			return
+		}
+
+		if signal = c.reconnect(ctx, action == lkLeaveActionReconnect); signal == nil {
+			return
+		}
+	}
+}
+
+// listen reads from the signalling connection until it is closed and returns how to reconnect.
+func (c *liveKitConnImpl) listen(ctx context.Context, signal *liveKitSignal) lkLeaveAction {
+	defer signal.close()
+	stop := context.AfterFunc(ctx, signal.close)
+	defer stop()
+	for {
+		rsp, err := signal.read()
+		if err != nil {
+			if ctx.Err() == nil {
+				c.config.Logger.Warn("livekit signalling connection closed", slog.Any("err", err))
+			}
+			return lkLeaveActionResume
+		}
+
+		switch {
+		case rsp.Leave != nil:
+			c.config.Logger.Debug("received leave request", slog.Int("action", int(rsp.Leave.Action)), slog.Int("reason", int(rsp.Leave.Reason)))
+			if rsp.Leave.Action == lkLeaveActionDisconnect && rsp.Leave.CanReconnect {
+				return lkLeaveActionReconnect
+			}
+			return rsp.Leave.Action
+		case rsp.Pong != nil, rsp.PongResp != nil:
+			signal.pong()
+		default:
+			c.handleSignal(rsp)
+		}
+	}
+}
+
+func (c *liveKitConnImpl) handleSignal(rsp lkSignalResponse) {
+	switch {
+	case rsp.Offer != nil:
+		if err := c.handleOffer(*rsp.Offer); err != nil {
+			c.config.Logger.Error("failed to answer subscriber offer", slog.Any("err", err))
+		}
+	case rsp.Answer != nil:
+		if err := c.handleAnswer(*rsp.Answer); err != nil {
+			c.config.Logger.Error("failed to handle publisher answer", slog.Any("err", err))
+		}
+	case rsp.Trickle != nil:
+		if err := c.handleTrickle(*rsp.Trickle); err != nil {
+			c.config.Logger.Error("failed to add ice candidate", slog.Any("err", err))
+		}
+	case rsp.TrackPublished != nil:
+		c.mu.Lock()
+		published, ok := c.published[rsp.TrackPublished.Cid]
+		c.mu.Unlock()
+		if ok {
+			published <- rsp.TrackPublished.Track
+		}
+	case rsp.Update != nil:
+		c.updateParticipants(rsp.Update.Participants, false)
+	case rsp.SpeakersChanged != nil:
+		c.updateSpeakers(rsp.SpeakersChanged.Speakers)
+	case rsp.RefreshToken != nil:
+		c.mu.Lock()
+		c.state.Token = *rsp.RefreshToken
+		c.mu.Unlock()
+	}
+}
+
+func (c *liveKitConnImpl) handleOffer(offer lkSessionDescription) error {
+	c.mu.Lock()
+	subscriber := c.subscriber
+	c.mu.Unlock()
+	if subscriber == nil {
+		return ErrLiveKitNotConnected
+	}
+
+	if err := subscriber.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeOffer, SDP: offer.SDP}); err != nil {
+		return err
+	}
+	c.addPendingCandidates(subscriber)
+	answer, err := subscriber.CreateAnswer(nil)
+	if err != nil {
+		return err
+	}
+	if err = subscriber.SetLocalDescription(answer); err != nil {
+		return err
+	}
+	return c.send(lkSignalRequest{Answer: &lkSessionDescription{Type: answer.Type.String(), SDP: answer.SDP}})
+}
+
+func (c *liveKitConnImpl) handleAnswer(answer lkSessionDescription) error {
+	c.mu.Lock()
+	publisher := c.publisher
+	c.mu.Unlock()
+	if publisher == nil {
+		return ErrLiveKitNotConnected
+	}
+
+	if err := publisher.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeAnswer, SDP: answer.SDP}); err != nil {
+		c.mu.Lock()
+		c.negotiating = false
+		c.mu.Unlock()
+		return err
+	}
+	c.addPendingCandidates(publisher)
+
+	c.mu.Lock()
+	if !c.renegotiate {
+		c.negotiating = false
+		c.mu.Unlock()
+		return nil
+	}
+	c.renegotiate = false
+	c.mu.Unlock()
+	return c.sendOffer(publisher, nil)
+}
+
+func (c *liveKitConnImpl) handleTrickle(trickle lkTrickleRequest) error {
+	var candidate webrtc.ICECandidateInit
+	if err := json.Unmarshal([]byte(trickle.CandidateInit), &candidate); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	pc := c.publisher
+	if trickle.Target == lkSignalTargetSubscriber {
+		pc = c.subscriber
+	}
+	if pc == nil {
+		c.mu.Unlock()
+		return ErrLiveKitNotConnected
+	}
+	if pc.RemoteDescription() == nil {
+		c.pendingCandidates[pc] = append(c.pendingCandidates[pc], candidate)
+		c.mu.Unlock()
+		return nil
+	}
+	c.mu.Unlock()
+	return pc.AddICECandidate(candidate)
+}
+
+// addPendingCandidates adds the ice candidates which were received before the remote description was set.
+func (c *liveKitConnImpl) addPendingCandidates(pc *webrtc.PeerConnection) {
+	c.mu.Lock()
+	candidates := c.pendingCandidates[pc]
+	delete(c.pendingCandidates, pc)
+	c.mu.Unlock()
+
+	for _, candidate := range candidates {
+		if err := pc.AddICECandidate(candidate); err != nil {
+			c.config.Logger.Error("failed to add ice candidate", slog.Any("err", err))
+		}
+	}
+}
+
+func (c *liveKitConnImpl) send(req lkSignalRequest) error {
+	c.mu.Lock()
+	signal := c.signal
+	c.mu.Unlock()
+	if signal == nil {
+		return ErrLiveKitNotConnected
+	}
+	return signal.send(req)
+}
+
+// dial opens a new signalling connection and returns it together with the first response of the server.
+// If sid is set, the session of this participant is resumed.
+func (c *liveKitConnImpl) dial(ctx context.Context, state State, sid string) (*liveKitSignal, lkSignalResponse, error) {
+	signalURL, err := liveKitSignalURL(state.Endpoint, state.Token, sid)
+	if err != nil {
+		return nil, lkSignalResponse{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.config.ConnectTimeout)
+	defer cancel()
+	conn, rs, err := c.config.Dialer.DialContext(ctx, signalURL, nil)
+	if err != nil {
+		if rs != nil {
+			body, _ := io.ReadAll(rs.Body)
+			return nil, lkSignalResponse{}, fmt.Errorf("failed to connect to livekit: %w: %s", err, body)
+		}
+		return nil, lkSignalResponse{}, fmt.Errorf("failed to connect to livekit: %w", err)
+	}
+
+	signal := newLiveKitSignal(conn)
+	stop := context.AfterFunc(ctx, signal.close)
+	defer stop()
+	rsp, err := signal.read()
+	if err != nil {
+		signal.close()
+		return nil, lkSignalResponse{}, fmt.Errorf("failed to read first livekit signal response: %w", err)
+	}
+	return signal, rsp, nil
+}
+
+// join joins the room with a new session and (re)publishes all tracks.
+func (c *liveKitConnImpl) join(ctx context.Context) (*liveKitSignal, error) {
+	c.mu.Lock()
+	state := c.state
+	c.mu.Unlock()
+
+	signal, rsp, err := c.dial(ctx, state, "")
+	if err != nil {
+		return nil, err
+	}
+	if rsp.Leave != nil {
+		signal.close()
+		return nil, fmt.Errorf("livekit refused to join: reason %d", rsp.Leave.Reason)
+	}
+	if rsp.Join == nil {
+		signal.close()
+		return nil, errLiveKitUnexpectedResponse
+	}
+
+	publisher, subscriber, err := c.createPeerConnections(rsp.Join.ICEServers)
+	if err != nil {
+		signal.close()
+		return nil, err
+	}
+
+	c.mu.Lock()
+	oldPublisher, oldSubscriber := c.publisher, c.subscriber
+	c.signal = signal
+	c.publisher = publisher
+	c.subscriber = subscriber
+	c.participantSid = rsp.Join.Participant.Sid
+	c.pingInterval = time.Duration(rsp.Join.PingInterval) * time.Second
+	c.pingTimeout = time.Duration(rsp.Join.PingTimeout) * time.Second
+	c.published = make(map[string]chan lkTrackInfo)
+	c.pendingCandidates = make(map[*webrtc.PeerConnection][]webrtc.ICECandidateInit)
+	c.negotiating = false
+	c.renegotiate = false
+	tracks := make([]*liveKitTrack, 0, len(c.tracks))
+	for _, track := range c.tracks {
+		tracks = append(tracks, track)
+	}
+	c.mu.Unlock()
+	closePeerConnections(c.config.Logger, oldPublisher, oldSubscriber)
+
+	c.updateParticipants(rsp.Join.OtherParticipants, true)
+	c.config.Logger.Debug("joined livekit room", slog.String("room", rsp.Join.Room.Name), slog.String("participant_sid", rsp.Join.Participant.Sid))
+	c.setStatus(StatusConnected)
+	go c.ping(signal)
+
+	if !rsp.Join.SubscriberPrimary {
+		if err = c.negotiate(); err != nil {
+			c.config.Logger.Error("failed to negotiate publisher", slog.Any("err", err))
+		}
+	}
+	if len(tracks) > 0 {
+		go func() {
+			for _, track := range tracks {
+				if err := c.publish(track); err != nil {
+					c.config.Logger.Error("failed to republish track", slog.String("name", track.req.Name), slog.Any("err", err))
+				}
+			}
+		}()
+	}
+	return signal, nil
+}
+
+// resume resumes the current session on a new signalling connection and restarts ICE of the publisher.
+func (c *liveKitConnImpl) resume(ctx context.Context) (*liveKitSignal, error) {
+	c.mu.Lock()
+	state, sid, publisher, subscriber := c.state, c.participantSid, c.publisher, c.subscriber
+	c.mu.Unlock()
+
+	signal, rsp, err := c.dial(ctx, state, sid)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.Reconnect == nil {
+		signal.close()
+		return nil, errLiveKitUnexpectedResponse
+	}
+
+	// the answer to an outstanding offer might have been lost, so the last answer is applied again before restarting ice
+	if publisher.SignalingState() == webrtc.SignalingStateHaveLocalOffer {
+		answer := publisher.CurrentRemoteDescription()
+		if answer == nil {
+			signal.close()
+			return nil, errLiveKitNegotiationLost
+		}
+		if err = publisher.SetRemoteDescription(*answer); err != nil {
+			signal.close()
+			return nil, fmt.Errorf("failed to reapply publisher answer: %w", err)
+		}
+	}
+
+	if len(rsp.Reconnect.ICEServers) > 0 {
+		cfg := webrtc.Configuration{ICEServers: liveKitICEServers(rsp.Reconnect.ICEServers)}
+		for _, pc := range []*webrtc.PeerConnection{publisher, subscriber} {
+			if err = pc.SetConfiguration(cfg); err != nil {
+				c.config.Logger.Warn("failed to update ice servers", slog.Any("err", err))
+			}
+		}
+	}
+
+	c.mu.Lock()
+	c.signal = signal
+	negotiated := c.negotiating || publisher.RemoteDescription() != nil
+	c.negotiating = negotiated
+	c.renegotiate = false
+	c.mu.Unlock()
+
+	c.config.Logger.Debug("resumed livekit session", slog.String("participant_sid", sid))
+	c.setStatus(StatusConnected)
+	go c.ping(signal)
+
+	if negotiated {
+		if err = c.sendOffer(publisher, &webrtc.OfferOptions{ICERestart: true}); err != nil {
+			c.config.Logger.Error("failed to restart publisher ice", slog.Any("err", err))
+		}
+	}
+	return signal, nil
+}
+
+// reconnect resumes the session or joins the room again with an exponential backoff until it succeeds or the context is done.
+func (c *liveKitConnImpl) reconnect(ctx context.Context, full bool) *liveKitSignal {
+	delay := c.config.ReconnectDelay
+	for {
+		c.setStatus(StatusConnecting)
+
+		var (
+			signal *liveKitSignal
+			err    error
+		)
+		if full {
+			signal, err = c.join(ctx)
+		} else {
+			signal, err = c.resume(ctx)
+		}
+		if err == nil {
+			return signal
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		c.config.Logger.Warn("failed to reconnect to livekit", slog.Bool("full", full), slog.Any("err", err), slog.Duration("retry_in", delay))
+		// only try to resume once, the server might have already dropped the session
+		full = true
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil
+		}
+		delay = min(delay*2, c.config.MaxReconnectDelay)
+	}
+}
+
+// ping sends pings on the signalling connection and closes it if the server does not answer within the ping timeout.
+func (c *liveKitConnImpl) ping(signal *liveKitSignal) {
+	c.mu.Lock()
+	interval, timeout := c.pingInterval, c.pingTimeout
+	c.mu.Unlock()
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-signal.closed:
+			return
+		case <-ticker.C:
+		}
+		if timeout > 0 && time.Since(signal.lastPong()) > timeout {
+			c.config.Logger.Warn("livekit ping timed out")
+			signal.close()
+			return
+		}
+		now := time.Now().UnixMilli()
+		if err := signal.send(lkSignalRequest{Ping: &now}); err != nil {
+			c.config.Logger.Debug("failed to send ping", slog.Any("err", err))
+		}
+	}
+}
+
+func (c *liveKitConnImpl) createPeerConnections(iceServers []lkICEServer) (*webrtc.PeerConnection, *webrtc.PeerConnection, error) {
+	cfg := webrtc.Configuration{
+		ICEServers:   liveKitICEServers(iceServers),
+		BundlePolicy: webrtc.BundlePolicyMaxBundle,
+	}
+	publisher, err := c.config.API.NewPeerConnection(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create publisher peer connection: %w", err)
+	}
+	subscriber, err := c.config.API.NewPeerConnection(cfg)
+	if err != nil {
+		_ = publisher.Close()
+		return nil, nil, fmt.Errorf("failed to create subscriber peer connection: %w", err)
+	}
+
+	subscriber.OnTrack(func(track *webrtc.TrackRemote, _ *webrtc.RTPReceiver) {
+		go c.receiveTrack(track)
+	})
+
+	for target, pc := range map[lkSignalTarget]*webrtc.PeerConnection{lkSignalTargetPublisher: publisher, lkSignalTargetSubscriber: subscriber} {
+		pc.OnICECandidate(func(candidate *webrtc.ICECandidate) {
+			if candidate == nil {
+				return
+			}
+			data, err := json.Marshal(candidate.ToJSON())
+			if err != nil {
+				return
+			}
+			if err = c.send(lkSignalRequest{Trickle: &lkTrickleRequest{CandidateInit: string(data), Target: target}}); err != nil {
+				c.config.Logger.Debug("failed to send ice candidate", slog.Any("err", err))
+			}
+		})
+	}
+
+	// LiveKit expects the data channels of the publisher, even if they are unused
+	ordered := false
+	maxRetransmits := uint16(0)
+	if _, err = publisher.CreateDataChannel("_reliable", nil); err == nil {
+		_, err = publisher.CreateDataChannel("_lossy", &webrtc.DataChannelInit{Ordered: &ordered, MaxRetransmits: &maxRetransmits})
+	}
+	if err != nil {
+		closePeerConnections(c.config.Logger, publisher, subscriber)
+		return nil, nil, fmt.Errorf("failed to create data channels: %w", err)
+	}
+	return publisher, subscriber, nil
+}
+
+// updateParticipants applies the lkParticipantInfo(s) to the known participants and passes joins and leaves to the AudioReceiver.
+// If replace is true, all known participants missing in the lkParticipantInfo(s) left.
+func (c *liveKitConnImpl) updateParticipants(infos []lkParticipantInfo, replace bool) {
+	var (
+		joined, left []snowflake.ID
+		events       []ConnEvent
+	)
+	// a user can be connected with multiple participants, so only the first participant joins and the last one leaves
+	removeParticipant := func(sid string, userID snowflake.ID) {
+		delete(c.participants, sid)
+		if c.speaking[sid] {
+			delete(c.speaking, sid)
+			events = append(events, SpeakingStop{UserID: userID})
+		}
+		if !c.hasParticipant(userID) {
+			left = append(left, userID)
+			events = append(events, ParticipantLeave{UserID: userID})
+		}
+	}
+
+	c.mu.Lock()
+	seen := make(map[string]struct{}, len(infos))
+	for _, info := range infos {
+		if info.Sid == c.participantSid {
+			continue
+		}
+		seen[info.Sid] = struct{}{}
+		userID, known := c.participants[info.Sid]
+		if info.State == lkParticipantStateDisconnected {
+			if known {
+				removeParticipant(info.Sid, userID)
+			}
+			continue
+		}
+		if known {
+			continue
+		}
+		if userID, known = participantUserID(info.Identity); !known {
+			c.config.Logger.Debug("ignoring participant with unknown identity", slog.String("identity", info.Identity))
+			continue
+		}
+		if !c.hasParticipant(userID) {
+			joined = append(joined, userID)
+			events = append(events, ParticipantJoin{UserID: userID})
+		}
+		c.participants[info.Sid] = userID
+	}
+	if replace {
+		for sid, userID := range c.participants {
+			if _, ok := seen[sid]; !ok {
+				removeParticipant(sid, userID)
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	c.emit(events...)
+
+	c.receiverMu.Lock()
+	defer c.receiverMu.Unlock()
+	if c.receiver == nil {
+		return
+	}
+	for _, userID := range joined {
+		if c.userFilter == nil || c.userFilter(userID) {
+			c.receiver.ParticipantJoin(userID)
+		}
+	}
+	for _, userID := range left {
+		if c.userFilter == nil || c.userFilter(userID) {
+			c.receiver.CleanupUser(userID)
+		}
+	}
+}
+
+// hasParticipant returns whether the user is connected with any participant. c.mu must be held.
+func (c *liveKitConnImpl) hasParticipant(userID snowflake.ID) bool {
+	for _, participantUserID := range c.participants {
+		if participantUserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// updateSpeakers emits SpeakingStart and SpeakingStop events for the changed speakers.
+func (c *liveKitConnImpl) updateSpeakers(speakers []lkSpeakerInfo) {
+	var events []ConnEvent
+	c.mu.Lock()
+	for _, speaker := range speakers {
+		userID, ok := c.participants[speaker.Sid]
+		if !ok || c.speaking[speaker.Sid] == speaker.Active {
+			continue
+		}
+		if speaker.Active {
+			c.speaking[speaker.Sid] = true
+			events = append(events, SpeakingStart{UserID: userID, Level: speaker.Level})
+		} else {
+			delete(c.speaking, speaker.Sid)
+			events = append(events, SpeakingStop{UserID: userID})
+		}
+	}
+	c.mu.Unlock()
+
+	c.emit(events...)
+}
+
+// receiveTrack passes the packets of a subscribed opus track to the AudioReceiver.
+func (c *liveKitConnImpl) receiveTrack(track *webrtc.TrackRemote) {
+	if !strings.EqualFold(track.Codec().MimeType, webrtc.MimeTypeOpus) {
+		return
+	}
+	participantSid, _ := splitStreamID(track.StreamID())
+	for {
+		pkt, _, err := track.ReadRTP()
+		if err != nil {
+			return
+		}
+		c.mu.Lock()
+		userID, ok := c.participants[participantSid]
+		c.mu.Unlock()
+		if !ok {
+			continue
+		}
+
+		c.receiverMu.Lock()
+		if c.receiver != nil && (c.userFilter == nil || c.userFilter(userID)) {
+			if err = c.receiver.ReceiveOpusFrame(userID, newPacket(pkt)); err != nil {
+				c.config.Logger.Error("error while receiving opus frame", slog.Int64("user_id", int64(userID)), slog.Any("err", err))
+			}
+		}
+		c.receiverMu.Unlock()
+	}
+}
+
+// publish announces the track to the server, adds it to the publisher and renegotiates.
+func (c *liveKitConnImpl) publish(track *liveKitTrack) error {
+	published := make(chan lkTrackInfo, 1)
+	c.mu.Lock()
+	publisher := c.publisher
+	if publisher == nil {
+		c.mu.Unlock()
+		return ErrLiveKitNotConnected
+	}
+	c.published[track.req.Cid] = published
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.published, track.req.Cid)
+		c.mu.Unlock()
+	}()
+
+	if err := c.send(lkSignalRequest{AddTrack: &track.req}); err != nil {
+		return err
+	}
+	var info lkTrackInfo
+	select {
+	case info = <-published:
+	case <-time.After(c.config.ConnectTimeout):
+		return errLiveKitPublishTimeout
+	}
+
+	sender, err := publisher.AddTrack(track.local)
+	if err != nil {
+		return fmt.Errorf("failed to add track: %w", err)
+	}
+	go func() {
+		// read incoming rtcp packets, so interceptors can process them
+		for {
+			if _, _, err := sender.ReadRTCP(); err != nil {
+				return
+			}
+		}
+	}()
+
+	c.mu.Lock()
+	track.sid = info.Sid
+	track.sender = sender
+	c.mu.Unlock()
+	c.config.Logger.Debug("published track", slog.String("name", track.req.Name), slog.String("track_sid", info.Sid))
+	return c.negotiate()
+}
+
+// unpublish removes the track from the publisher and renegotiates.
+func (c *liveKitConnImpl) unpublish(track *liveKitTrack) error {
+	c.mu.Lock()
+	delete(c.tracks, track.req.Cid)
+	publisher, sender := c.publisher, track.sender
+	track.sender = nil
+	c.mu.Unlock()
+	if publisher == nil || sender == nil {
+		return nil
+	}
+
+	if err := publisher.RemoveTrack(sender); err != nil {
+		return fmt.Errorf("failed to remove track: %w", err)
+	}
+	return c.negotiate()
+}
+
+// negotiate sends a new offer of the publisher or remembers to do so after the outstanding answer was received.
+func (c *liveKitConnImpl) negotiate() error {
+	c.mu.Lock()
+	if c.negotiating {
+		c.renegotiate = true
+		c.mu.Unlock()
+		return nil
+	}
+	c.negotiating = true
+	publisher := c.publisher
+	c.mu.Unlock()
+	return c.sendOffer(publisher, nil)
+}
+
+func (c *liveKitConnImpl) sendOffer(publisher *webrtc.PeerConnection, opts *webrtc.OfferOptions) error {
+	offer, err := publisher.CreateOffer(opts)
+	if err == nil {
+		err = publisher.SetLocalDescription(offer)
+	}
+	if err == nil {
+		err = c.send(lkSignalRequest{Offer: &lkSessionDescription{Type: offer.Type.String(), SDP: offer.SDP}})
+	}
+	if err != nil {
+		c.mu.Lock()
+		c.negotiating = false
+		c.mu.Unlock()
+	}
+	return err
+}
+
+// teardown closes the peer connections and resets the session.
+func (c *liveKitConnImpl) teardown() {
+	c.mu.Lock()
+	publisher, subscriber := c.publisher, c.subscriber
+	c.signal = nil
+	c.publisher = nil
+	c.subscriber = nil
+	c.participantSid = ""
+	for _, track := range c.tracks {
+		track.sender = nil
+	}
+	c.mu.Unlock()
+
+	c.updateParticipants(nil, true)
+
+	closePeerConnections(c.config.Logger, publisher, subscriber)
+	c.setStatus(StatusDisconnected)
+}
+
+func closePeerConnections(logger *slog.Logger, pcs ...*webrtc.PeerConnection) {
+	for _, pc := range pcs {
+		if pc == nil {
+			continue
+		}
+		if err := pc.Close(); err != nil {
+			logger.Debug("failed to close peer connection", slog.Any("err", err))
+		}
+	}
+}
+
+func liveKitICEServers(servers []lkICEServer) []webrtc.ICEServer {
+	iceServers := make([]webrtc.ICEServer, 0, len(servers))
+	for _, server := range servers {
+		iceServers = append(iceServers, webrtc.ICEServer{
+			URLs:       server.URLs,
+			Username:   server.Username,
+			Credential: server.Credential,
+		})
+	}
+	return iceServers
+}
+
+// liveKitSignalURL returns the URL of the signalling websocket for the given endpoint.
+// Endpoints without a scheme use wss and http(s) schemes are replaced by ws(s).
+func liveKitSignalURL(endpoint string, token string, sid string) (string, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "wss://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid livekit endpoint: %w", err)
+	}
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/rtc"
+
+	values := url.Values{}
+	values.Set("access_token", token)
+	values.Set("auto_subscribe", "1")
+	values.Set("sdk", "go")
+	values.Set("protocol", fmt.Sprint(liveKitProtocolVersion))
+	if sid != "" {
+		values.Set("reconnect", "1")
+		values.Set("sid", sid)
+	}
+	u.RawQuery = values.Encode()
+	return u.String(), nil
+}
+
+func newLiveKitSignal(conn *websocket.Conn) *liveKitSignal {
+	s := &liveKitSignal{
+		conn:   conn,
+		closed: make(chan struct{}),
+	}
+	s.pong()
+	return s
+}
+
+// liveKitSignal is a websocket connection to the LiveKit signalling server.
+type liveKitSignal struct {
+	conn       *websocket.Conn
+	writeMu    sync.Mutex
+	closeOnce  sync.Once
+	closed     chan struct{}
+	lastPongAt atomic.Int64
+}
+
+func (s *liveKitSignal) send(req lkSignalRequest) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.conn.WriteMessage(websocket.BinaryMessage, marshalProto(&req))
+}
+
+func (s *liveKitSignal) read() (lkSignalResponse, error) {
+	var rsp lkSignalResponse
+	_, data, err := s.conn.ReadMessage()
+	if err != nil {
+		return rsp, err
+	}
+	err = unmarshalProto(data, &rsp)
+	return rsp, err
+}
+
+func (s *liveKitSignal) pong() {
+	s.lastPongAt.Store(time.Now().UnixNano())
+}
+
+func (s *liveKitSignal) lastPong() time.Time {
+	return time.Unix(0, s.lastPongAt.Load())
+}
+
+func (s *liveKitSignal) close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		_ = s.conn.Close()
+	})
+}
+
+// liveKitTrack is a local track which is republished after the room was joined again.
+type liveKitTrack struct {
+	req           lkAddTrackRequest
+	local         *webrtc.TrackLocalStaticSample
+	frameDuration time.Duration
+	sid           string
+	sender        *webrtc.RTPSender
+}
+
+type liveKitTrackWriter struct {
+	conn      *liveKitConnImpl
+	track     *liveKitTrack
+	closeOnce sync.Once
+}
+
+// Write writes a single frame to the track.
+func (w *liveKitTrackWriter) Write(p []byte) (int, error) {
+	if w.conn.Status() == StatusDisconnected {
+		return 0, ErrLiveKitNotConnected
+	}
+	if err := w.track.local.WriteSample(media.Sample{Data: p, Duration: w.track.frameDuration}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close unpublishes the track.
+func (w *liveKitTrackWriter) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		err = w.conn.unpublish(w.track)
+	})
+	return err
+}
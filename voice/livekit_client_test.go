@@ -0,0 +1,459 @@
+package voice
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/gorilla/websocket"
+	"github.com/pion/webrtc/v4"
+	"github.com/pion/webrtc/v4/pkg/media"
+)
+
+func newTestWebRTCAPI() *webrtc.API {
+	var settingEngine webrtc.SettingEngine
+	settingEngine.SetIncludeLoopbackCandidate(true)
+	settingEngine.SetNetworkTypes([]webrtc.NetworkType{webrtc.NetworkTypeUDP4})
+	return webrtc.NewAPI(webrtc.WithSettingEngine(settingEngine))
+}
+
+// mockLiveKitServer is a minimal LiveKit signalling server which negotiates with pion peer connections.
+// It sends complete session descriptions and ignores trickled candidates of the client.
+type mockLiveKitServer struct {
+	t        *testing.T
+	api      *webrtc.API
+	upgrader websocket.Upgrader
+
+	mu       sync.Mutex
+	sessions []*mockLiveKitSession
+	queries  []map[string]string
+
+	requests chan lkSignalRequest
+}
+
+type mockLiveKitSession struct {
+	conn       *websocket.Conn
+	writeMu    sync.Mutex
+	publisher  *webrtc.PeerConnection
+	subscriber *webrtc.PeerConnection
+}
+
+func (s *mockLiveKitSession) write(rsp lkSignalResponse) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_ = s.conn.WriteMessage(websocket.BinaryMessage, marshalProto(&rsp))
+}
+
+func newMockLiveKitServer(t *testing.T) (*mockLiveKitServer, *httptest.Server) {
+	m := &mockLiveKitServer{
+		t:        t,
+		api:      newTestWebRTCAPI(),
+		requests: make(chan lkSignalRequest, 256),
+	}
+	server := httptest.NewServer(http.HandlerFunc(m.serve))
+	t.Cleanup(server.Close)
+	return m, server
+}
+
+func (m *mockLiveKitServer) serve(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/rtc" || r.URL.Query().Get("access_token") != "token" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	conn, err := m.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	session := &mockLiveKitSession{conn: conn}
+	if session.publisher, err = m.api.NewPeerConnection(webrtc.Configuration{}); err != nil {
+		m.t.Error(err)
+		return
+	}
+	defer session.publisher.Close()
+	if session.subscriber, err = m.api.NewPeerConnection(webrtc.Configuration{}); err != nil {
+		m.t.Error(err)
+		return
+	}
+	defer session.subscriber.Close()
+
+	query := map[string]string{}
+	for key := range r.URL.Query() {
+		query[key] = r.URL.Query().Get(key)
+	}
+	m.mu.Lock()
+	m.sessions = append(m.sessions, session)
+	m.queries = append(m.queries, query)
+	m.mu.Unlock()
+
+	if query["reconnect"] == "1" {
+		session.write(lkSignalResponse{Reconnect: &lkReconnectResponse{}})
+	} else {
+		session.write(lkSignalResponse{Join: &lkJoinResponse{
+			Room:              lkRoom{Sid: "RM_1", Name: "room"},
+			Participant:       lkParticipantInfo{Sid: "PA_1", Identity: "1"},
+			SubscriberPrimary: true,
+			PingInterval:      1,
+			PingTimeout:       5,
+		}})
+	}
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var req lkSignalRequest
+		if err = unmarshalProto(data, &req); err != nil {
+			m.t.Error(err)
+			return
+		}
+		m.requests <- req
+
+		switch {
+		case req.AddTrack != nil:
+			session.write(lkSignalResponse{TrackPublished: &lkTrackPublishedResponse{
+				Cid:   req.AddTrack.Cid,
+				Track: lkTrackInfo{Sid: "TR_" + req.AddTrack.Name, Type: req.AddTrack.Type, Name: req.AddTrack.Name},
+			}})
+		case req.Offer != nil:
+			if err = session.publisher.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeOffer, SDP: req.Offer.SDP}); err != nil {
+				m.t.Error(err)
+				return
+			}
+			answer, err := session.publisher.CreateAnswer(nil)
+			if err != nil {
+				m.t.Error(err)
+				return
+			}
+			gathered := webrtc.GatheringCompletePromise(session.publisher)
+			if err = session.publisher.SetLocalDescription(answer); err != nil {
+				m.t.Error(err)
+				return
+			}
+			<-gathered
+			session.write(lkSignalResponse{Answer: &lkSessionDescription{Type: "answer", SDP: session.publisher.LocalDescription().SDP}})
+		case req.Answer != nil:
+			if err = session.subscriber.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeAnswer, SDP: req.Answer.SDP}); err != nil {
+				m.t.Error(err)
+				return
+			}
+		case req.Ping != nil:
+			session.write(lkSignalResponse{Pong: req.Ping})
+		}
+	}
+}
+
+// publishAudio adds an opus track of the participant to the subscriber and sends an offer to the client.
+func (m *mockLiveKitServer) publishAudio(t *testing.T, participantSid string) *webrtc.TrackLocalStaticSample {
+	t.Helper()
+	session := m.session()
+	track, err := webrtc.NewTrackLocalStaticSample(webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeOpus, ClockRate: 48000, Channels: 2}, "TR_2", participantSid+"|TR_2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = session.subscriber.AddTrack(track); err != nil {
+		t.Fatal(err)
+	}
+	offer, err := session.subscriber.CreateOffer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gathered := webrtc.GatheringCompletePromise(session.subscriber)
+	if err = session.subscriber.SetLocalDescription(offer); err != nil {
+		t.Fatal(err)
+	}
+	<-gathered
+	session.write(lkSignalResponse{Offer: &lkSessionDescription{Type: "offer", SDP: session.subscriber.LocalDescription().SDP}})
+	return track
+}
+
+func (m *mockLiveKitServer) session() *mockLiveKitSession {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sessions[len(m.sessions)-1]
+}
+
+func (m *mockLiveKitServer) dropConns() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, session := range m.sessions {
+		_ = session.conn.Close()
+	}
+}
+
+func (m *mockLiveKitServer) query(i int) map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if i >= len(m.queries) {
+		return nil
+	}
+	return m.queries[i]
+}
+
+// waitRequest returns the next request matching f.
+func (m *mockLiveKitServer) waitRequest(t *testing.T, f func(req lkSignalRequest) bool) lkSignalRequest {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case req := <-m.requests:
+			if f(req) {
+				return req
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for signal request")
+		}
+	}
+}
+
+func waitStatus(t *testing.T, conn LiveKitConn, status Status) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for conn.Status() != status {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected status %d, got %d", status, conn.Status())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+type testAudioReceiver struct {
+	joins    chan snowflake.ID
+	frames   chan snowflake.ID
+	cleanups chan snowflake.ID
+	closed   chan struct{}
+}
+
+func newTestAudioReceiver() *testAudioReceiver {
+	return &testAudioReceiver{
+		joins:    make(chan snowflake.ID, 16),
+		frames:   make(chan snowflake.ID, 1024),
+		cleanups: make(chan snowflake.ID, 16),
+		closed:   make(chan struct{}),
+	}
+}
+
+func (r *testAudioReceiver) ParticipantJoin(userID snowflake.ID) {
+	r.joins <- userID
+}
+
+func (r *testAudioReceiver) ReceiveOpusFrame(userID snowflake.ID, _ *Packet) error {
+	select {
+	case r.frames <- userID:
+	default:
+	}
+	return nil
+}
+
+func (r *testAudioReceiver) CleanupUser(userID snowflake.ID) {
+	r.cleanups <- userID
+}
+
+func (r *testAudioReceiver) Close() {
+	close(r.closed)
+}
+
+func waitUserID(t *testing.T, ch <-chan snowflake.ID, expected snowflake.ID) {
+	t.Helper()
+	select {
+	case userID := <-ch:
+		if userID != expected {
+			t.Fatalf("expected user %d, got %d", expected, userID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for audio receiver")
+	}
+}
+
+func TestLiveKitConn(t *testing.T) {
+	mock, server := newMockLiveKitServer(t)
+
+	conn := NewLiveKitConn(WithLiveKitConnReconnectDelay(10*time.Millisecond, 50*time.Millisecond))
+	if conn.Status() != StatusDisconnected {
+		t.Fatalf("expected status disconnected, got %d", conn.Status())
+	}
+
+	if _, err := conn.AudioWriter("audio", AudioSourceMicrophone); err != ErrLiveKitNotConnected {
+		t.Fatalf("expected ErrLiveKitNotConnected, got %v", err)
+	}
+
+	state := State{Endpoint: server.URL, Token: "token"}
+	if err := conn.Open(state); err != nil {
+		t.Fatal(err)
+	}
+	if conn.Status() != StatusConnected {
+		t.Fatalf("expected status connected, got %d", conn.Status())
+	}
+	if query := mock.query(0); query["reconnect"] != "" || query["auto_subscribe"] != "1" {
+		t.Fatalf("unexpected join query: %v", query)
+	}
+
+	w, err := conn.AudioWriter("audio", AudioSourceMicrophone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addTrack := mock.waitRequest(t, func(req lkSignalRequest) bool { return req.AddTrack != nil })
+	if addTrack.AddTrack.Name != "audio" || addTrack.AddTrack.Type != lkTrackTypeAudio || addTrack.AddTrack.Source != lkTrackSourceMicrophone {
+		t.Fatalf("unexpected add track request: %+v", addTrack.AddTrack)
+	}
+	offer := mock.waitRequest(t, func(req lkSignalRequest) bool { return req.Offer != nil })
+	if !strings.Contains(offer.Offer.SDP, "opus") || !strings.Contains(offer.Offer.SDP, addTrack.AddTrack.Cid) {
+		t.Fatal("expected offer to contain the opus track")
+	}
+	if _, err = w.Write([]byte{0xf8, 0xff, 0xfe}); err != nil {
+		t.Fatal(err)
+	}
+
+	// drop the signalling connection, the session should be resumed
+	mock.dropConns()
+	mock.waitRequest(t, func(req lkSignalRequest) bool { return req.Offer != nil })
+	waitStatus(t, conn, StatusConnected)
+	if query := mock.query(1); query["reconnect"] != "1" || query["sid"] != "PA_1" {
+		t.Fatalf("unexpected resume query: %v", query)
+	}
+
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	conn.Close()
+	mock.waitRequest(t, func(req lkSignalRequest) bool { return req.Leave != nil })
+	if conn.Status() != StatusDisconnected {
+		t.Fatalf("expected status disconnected, got %d", conn.Status())
+	}
+	if _, err = w.Write([]byte{0xf8, 0xff, 0xfe}); err != ErrLiveKitNotConnected {
+		t.Fatalf("expected ErrLiveKitNotConnected, got %v", err)
+	}
+}
+
+func TestLiveKitConnAudioReceiver(t *testing.T) {
+	mock, server := newMockLiveKitServer(t)
+
+	conn := NewLiveKitConn(WithLiveKitConnAPI(newTestWebRTCAPI()))
+	if err := conn.Open(State{Endpoint: server.URL, Token: "token"}); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	const (
+		userID   = snowflake.ID(123456789012345678)
+		filtered = snowflake.ID(987654321098765432)
+	)
+	receiver := newTestAudioReceiver()
+	conn.SetAudioReceiver(receiver, func(id snowflake.ID) bool { return id != filtered })
+
+	mock.session().write(lkSignalResponse{Update: &lkParticipantUpdate{Participants: []lkParticipantInfo{
+		{Sid: "PA_2", Identity: "user_" + userID.String(), State: lkParticipantStateActive},
+		{Sid: "PA_3", Identity: filtered.String(), State: lkParticipantStateActive},
+	}}})
+	waitUserID(t, receiver.joins, userID)
+
+	track := mock.publishAudio(t, "PA_2")
+	mock.waitRequest(t, func(req lkSignalRequest) bool { return req.Answer != nil })
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(OpusFrameDuration)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_ = track.WriteSample(media.Sample{Data: []byte{0xf8, 0xff, 0xfe}, Duration: OpusFrameDuration})
+			}
+		}
+	}()
+	waitUserID(t, receiver.frames, userID)
+
+	if participants := conn.Participants(); len(participants) != 2 {
+		t.Fatalf("expected 2 participants, got %v", participants)
+	}
+
+	mock.session().write(lkSignalResponse{Update: &lkParticipantUpdate{Participants: []lkParticipantInfo{
+		{Sid: "PA_2", Identity: "user_" + userID.String(), State: lkParticipantStateDisconnected},
+	}}})
+	waitUserID(t, receiver.cleanups, userID)
+
+	conn.Close()
+	select {
+	case <-receiver.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected audio receiver to be closed")
+	}
+}
+
+func TestLiveKitConnEvents(t *testing.T) {
+	mock, server := newMockLiveKitServer(t)
+
+	events := make(chan ConnEvent, 16)
+	conn := NewLiveKitConn(WithLiveKitConnAPI(newTestWebRTCAPI()))
+	conn.SetEventFunc(func(event ConnEvent) {
+		events <- event
+	})
+	if err := conn.Open(State{Endpoint: server.URL, Token: "token"}); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	waitEvent := func(expected ConnEvent) {
+		t.Helper()
+		select {
+		case event := <-events:
+			if event != expected {
+				t.Fatalf("expected %#v, got %#v", expected, event)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %#v", expected)
+		}
+	}
+	waitEvent(LiveKitStatusChange{OldStatus: StatusDisconnected, NewStatus: StatusConnecting})
+	waitEvent(LiveKitStatusChange{OldStatus: StatusConnecting, NewStatus: StatusConnected})
+
+	// the user is connected with two participants, but only joins and leaves once
+	const userID = snowflake.ID(123456789012345678)
+	mock.session().write(lkSignalResponse{Update: &lkParticipantUpdate{Participants: []lkParticipantInfo{
+		{Sid: "PA_2", Identity: "user_" + userID.String(), State: lkParticipantStateActive},
+		{Sid: "PA_3", Identity: "user_" + userID.String() + "_2", State: lkParticipantStateActive},
+	}}})
+	waitEvent(ParticipantJoin{UserID: userID})
+
+	mock.session().write(lkSignalResponse{SpeakersChanged: &lkSpeakersChanged{Speakers: []lkSpeakerInfo{
+		{Sid: "PA_2", Level: 0.5, Active: true},
+	}}})
+	waitEvent(SpeakingStart{UserID: userID, Level: 0.5})
+
+	mock.session().write(lkSignalResponse{Update: &lkParticipantUpdate{Participants: []lkParticipantInfo{
+		{Sid: "PA_2", Identity: "user_" + userID.String(), State: lkParticipantStateDisconnected},
+	}}})
+	waitEvent(SpeakingStop{UserID: userID})
+
+	mock.session().write(lkSignalResponse{Update: &lkParticipantUpdate{Participants: []lkParticipantInfo{
+		{Sid: "PA_3", Identity: "user_" + userID.String() + "_2", State: lkParticipantStateDisconnected},
+	}}})
+	waitEvent(ParticipantLeave{UserID: userID})
+}
+
+func TestLiveKitSignalURL(t *testing.T) {
+	tests := map[string]string{
+		"voice.fluxer.app":          "wss://voice.fluxer.app/rtc",
+		"https://voice.fluxer.app/": "wss://voice.fluxer.app/rtc",
+		"ws://localhost:7880":       "ws://localhost:7880/rtc",
+	}
+	for endpoint, expected := range tests {
+		signalURL, err := liveKitSignalURL(endpoint, "token", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(signalURL, expected+"?") {
+			t.Errorf("expected %q to start with %q", signalURL, expected)
+		}
+	}
+}
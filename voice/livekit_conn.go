@@ -43,6 +43,18 @@ const (
 	VideoSourceScreenShare
 )
 
+// VideoCodec is the codec of a video track published with LiveKitConn.VideoCodecWriter.
+type VideoCodec int
+
+const (
+	// VideoCodecH264 expects one Annex-B access unit per write, see H264Reader.
+	VideoCodecH264 VideoCodec = iota
+	// VideoCodecVP8 expects one VP8 frame per write, see IVFReader.
+	VideoCodecVP8
+	// VideoCodecVP9 expects one VP9 frame per write, see IVFReader.
+	VideoCodecVP9
+)
+
 type (
 	LiveKitConnCreateFunc func() LiveKitConn
 
@@ -69,7 +81,75 @@ type (
 
 		Status() Status
 
+		// SetAudioReceiver sets the AudioReceiver which receives the audio of all participants accepted by the UserFilterFunc.
+		// A nil UserFilterFunc accepts all participants. The previous AudioReceiver is closed.
+		SetAudioReceiver(receiver AudioReceiver, userFilter UserFilterFunc)
+
+		// Participants returns the user IDs of the other participants in the voice channel.
+		Participants() []snowflake.ID
+
+		// SetEventFunc sets the ConnEventFunc which receives the LiveKitStatusChange, SpeakingStart, SpeakingStop, ParticipantJoin and ParticipantLeave events.
+		// It is set by the Conn which owns the LiveKitConn.
+		SetEventFunc(eventFunc ConnEventFunc)
+
 		AudioWriter(name string, source AudioSource) (io.WriteCloser, error)
+
+		// VideoWriter returns an io.WriteCloser which publishes a H.264 video track, see VideoCodecWriter.
 		VideoWriter(name string, source VideoSource, width int, height int, fps int) (io.WriteCloser, error)
+
+		// VideoCodecWriter returns an io.WriteCloser which publishes a video track in the given VideoCodec. Every Write has to contain a single frame.
+		// The frames are timestamped with the given fps, use WriteVideo or WriteAudioVideo to pace them.
+		VideoCodecWriter(name string, source VideoSource, codec VideoCodec, width int, height int, fps int) (io.WriteCloser, error)
 	}
 )
+
+type (
+	// ConnEventFunc is called for every ConnEvent of a Conn or LiveKitConn.
+	ConnEventFunc func(event ConnEvent)
+
+	// ConnEvent is one of ConnStatusChange, LiveKitStatusChange, SpeakingStart, SpeakingStop, ParticipantJoin or ParticipantLeave.
+	ConnEvent interface {
+		connEvent()
+	}
+
+	// ConnStatusChange is sent when the ConnStatus of a Conn changed.
+	ConnStatusChange struct {
+		OldStatus ConnStatus
+		NewStatus ConnStatus
+	}
+
+	// LiveKitStatusChange is sent when the Status of a LiveKitConn changed. The Conn reflects it in its ConnStatus.
+	LiveKitStatusChange struct {
+		OldStatus Status
+		NewStatus Status
+	}
+
+	// SpeakingStart is sent when a participant started speaking.
+	SpeakingStart struct {
+		UserID snowflake.ID
+		// Level is the audio level of the participant between 0 and 1.
+		Level float32
+	}
+
+	// SpeakingStop is sent when a participant stopped speaking.
+	SpeakingStop struct {
+		UserID snowflake.ID
+	}
+
+	// ParticipantJoin is sent when a user joined the voice channel.
+	ParticipantJoin struct {
+		UserID snowflake.ID
+	}
+
+	// ParticipantLeave is sent when a user left the voice channel.
+	ParticipantLeave struct {
+		UserID snowflake.ID
+	}
+)
+
+func (ConnStatusChange) connEvent()    {}
+func (LiveKitStatusChange) connEvent() {}
+func (SpeakingStart) connEvent()       {}
+func (SpeakingStop) connEvent()        {}
+func (ParticipantJoin) connEvent()     {}
+func (ParticipantLeave) connEvent()    {}
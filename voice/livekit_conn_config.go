@@ -0,0 +1,78 @@
+package voice
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pion/webrtc/v4"
+)
+
+func defaultLiveKitConnConfig() liveKitConnConfig {
+	return liveKitConnConfig{
+		Logger:            slog.Default(),
+		Dialer:            websocket.DefaultDialer,
+		ConnectTimeout:    10 * time.Second,
+		ReconnectDelay:    time.Second,
+		MaxReconnectDelay: 30 * time.Second,
+	}
+}
+
+type liveKitConnConfig struct {
+	Logger            *slog.Logger
+	Dialer            *websocket.Dialer
+	API               *webrtc.API
+	ConnectTimeout    time.Duration
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+}
+
+// LiveKitConnConfigOpt is used to functionally configure a liveKitConnConfig.
+type LiveKitConnConfigOpt func(config *liveKitConnConfig)
+
+func (c *liveKitConnConfig) apply(opts []LiveKitConnConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.Logger = c.Logger.With(slog.String("name", "voice_livekit"))
+	if c.API == nil {
+		c.API = webrtc.NewAPI()
+	}
+}
+
+// WithLiveKitConnLogger sets the LiveKitConn(s) used Logger.
+func WithLiveKitConnLogger(logger *slog.Logger) LiveKitConnConfigOpt {
+	return func(config *liveKitConnConfig) {
+		config.Logger = logger
+	}
+}
+
+// WithLiveKitConnDialer sets the websocket.Dialer used to connect to the LiveKit signalling server.
+func WithLiveKitConnDialer(dialer *websocket.Dialer) LiveKitConnConfigOpt {
+	return func(config *liveKitConnConfig) {
+		config.Dialer = dialer
+	}
+}
+
+// WithLiveKitConnAPI sets the webrtc.API used to create the peer connections, for example to configure a webrtc.SettingEngine.
+func WithLiveKitConnAPI(api *webrtc.API) LiveKitConnConfigOpt {
+	return func(config *liveKitConnConfig) {
+		config.API = api
+	}
+}
+
+// WithLiveKitConnConnectTimeout sets how long LiveKitConn.Open waits for the room to be joined. The default is 10s.
+func WithLiveKitConnConnectTimeout(timeout time.Duration) LiveKitConnConfigOpt {
+	return func(config *liveKitConnConfig) {
+		config.ConnectTimeout = timeout
+	}
+}
+
+// WithLiveKitConnReconnectDelay sets the delay before the first reconnect attempt after the signalling connection dropped.
+// It doubles with every failed attempt up to the max delay. The defaults are 1s and 30s.
+func WithLiveKitConnReconnectDelay(delay time.Duration, maxDelay time.Duration) LiveKitConnConfigOpt {
+	return func(config *liveKitConnConfig) {
+		config.ReconnectDelay = delay
+		config.MaxReconnectDelay = maxDelay
+	}
+}
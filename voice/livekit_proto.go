@@ -0,0 +1,401 @@
+package voice
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// This file contains a minimal protobuf codec and the subset of the LiveKit signalling protocol (livekit_rtc.proto) used by the
+// built-in LiveKitConn. Fields are mapped by their `proto:"<field number>"` struct tag. Supported field types are string, bool,
+// signed and unsigned integers (varint), float32 (fixed32), []byte, []string, nested structs and slices of them.
+// Pointer fields are only encoded if they are not nil, which is used for oneof fields.
+
+var errProtoTruncated = errors.New("truncated protobuf message")
+
+const (
+	protoWireVarint  = 0
+	protoWireFixed64 = 1
+	protoWireBytes   = 2
+	protoWireFixed32 = 5
+)
+
+type protoField struct {
+	index int
+	num   uint64
+}
+
+var protoFieldsCache sync.Map // map[reflect.Type][]protoField
+
+func protoFields(t reflect.Type) []protoField {
+	if fields, ok := protoFieldsCache.Load(t); ok {
+		return fields.([]protoField)
+	}
+	var fields []protoField
+	for i := range t.NumField() {
+		tag, ok := t.Field(i).Tag.Lookup("proto")
+		if !ok {
+			continue
+		}
+		num, err := strconv.ParseUint(tag, 10, 32)
+		if err != nil {
+			panic(fmt.Sprintf("invalid proto tag %q on %s.%s", tag, t.Name(), t.Field(i).Name))
+		}
+		fields = append(fields, protoField{index: i, num: num})
+	}
+	protoFieldsCache.Store(t, fields)
+	return fields
+}
+
+func marshalProto(v any) []byte {
+	return appendProtoMessage(nil, reflect.Indirect(reflect.ValueOf(v)))
+}
+
+func appendProtoMessage(b []byte, v reflect.Value) []byte {
+	for _, field := range protoFields(v.Type()) {
+		b = appendProtoField(b, field.num, v.Field(field.index), false)
+	}
+	return b
+}
+
+func appendProtoField(b []byte, num uint64, v reflect.Value, force bool) []byte {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return b
+		}
+		return appendProtoField(b, num, v.Elem(), true)
+	case reflect.Bool:
+		if !v.Bool() && !force {
+			return b
+		}
+		var u uint64
+		if v.Bool() {
+			u = 1
+		}
+		return binary.AppendUvarint(appendProtoTag(b, num, protoWireVarint), u)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		if v.Int() == 0 && !force {
+			return b
+		}
+		return binary.AppendUvarint(appendProtoTag(b, num, protoWireVarint), uint64(v.Int()))
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		if v.Uint() == 0 && !force {
+			return b
+		}
+		return binary.AppendUvarint(appendProtoTag(b, num, protoWireVarint), v.Uint())
+	case reflect.Float32:
+		if v.Float() == 0 && !force {
+			return b
+		}
+		return binary.LittleEndian.AppendUint32(appendProtoTag(b, num, protoWireFixed32), math.Float32bits(float32(v.Float())))
+	case reflect.String:
+		if v.Len() == 0 && !force {
+			return b
+		}
+		return appendProtoBytes(b, num, []byte(v.String()))
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if v.Len() == 0 && !force {
+				return b
+			}
+			return appendProtoBytes(b, num, v.Bytes())
+		}
+		for i := range v.Len() {
+			b = appendProtoField(b, num, v.Index(i), true)
+		}
+		return b
+	case reflect.Struct:
+		if v.IsZero() && !force {
+			return b
+		}
+		return appendProtoBytes(b, num, appendProtoMessage(nil, v))
+	default:
+		panic("unsupported proto field kind: " + v.Kind().String())
+	}
+}
+
+func appendProtoTag(b []byte, num uint64, wireType uint64) []byte {
+	return binary.AppendUvarint(b, num<<3|wireType)
+}
+
+func appendProtoBytes(b []byte, num uint64, data []byte) []byte {
+	b = appendProtoTag(b, num, protoWireBytes)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func unmarshalProto(data []byte, v any) error {
+	return unmarshalProtoMessage(data, reflect.ValueOf(v).Elem())
+}
+
+func unmarshalProtoMessage(data []byte, v reflect.Value) error {
+	fields := protoFields(v.Type())
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errProtoTruncated
+		}
+		data = data[n:]
+
+		var (
+			num      = tag >> 3
+			wireType = tag & 7
+			u        uint64
+			raw      []byte
+		)
+		switch wireType {
+		case protoWireVarint:
+			if u, n = binary.Uvarint(data); n <= 0 {
+				return errProtoTruncated
+			}
+			data = data[n:]
+		case protoWireFixed64:
+			if len(data) < 8 {
+				return errProtoTruncated
+			}
+			u = binary.LittleEndian.Uint64(data)
+			data = data[8:]
+		case protoWireFixed32:
+			if len(data) < 4 {
+				return errProtoTruncated
+			}
+			u = uint64(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+		case protoWireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < length {
+				return errProtoTruncated
+			}
+			raw = data[n : n+int(length)]
+			data = data[n+int(length):]
+		default:
+			return fmt.Errorf("unsupported protobuf wire type %d", wireType)
+		}
+
+		for _, field := range fields {
+			if field.num != num {
+				continue
+			}
+			if err := setProtoField(v.Field(field.index), u, raw); err != nil {
+				return fmt.Errorf("failed to decode field %d of %s: %w", num, v.Type().Name(), err)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+func setProtoField(v reflect.Value, u uint64, raw []byte) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setProtoField(v.Elem(), u, raw)
+	case reflect.Bool:
+		v.SetBool(u != 0)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(u))
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		v.SetUint(u)
+	case reflect.Float32:
+		v.SetFloat(float64(math.Float32frombits(uint32(u))))
+	case reflect.String:
+		v.SetString(string(raw))
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes(append([]byte(nil), raw...))
+			return nil
+		}
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if err := setProtoField(elem, u, raw); err != nil {
+			return err
+		}
+		v.Set(reflect.Append(v, elem))
+	case reflect.Struct:
+		return unmarshalProtoMessage(raw, v)
+	}
+	return nil
+}
+
+type lkSignalTarget int32
+
+const (
+	lkSignalTargetPublisher lkSignalTarget = iota
+	lkSignalTargetSubscriber
+)
+
+type lkTrackType int32
+
+const (
+	lkTrackTypeAudio lkTrackType = iota
+	lkTrackTypeVideo
+)
+
+type lkTrackSource int32
+
+const (
+	lkTrackSourceUnknown lkTrackSource = iota
+	lkTrackSourceCamera
+	lkTrackSourceMicrophone
+	lkTrackSourceScreenShare
+	lkTrackSourceScreenShareAudio
+)
+
+type lkParticipantState int32
+
+const (
+	lkParticipantStateJoining lkParticipantState = iota
+	lkParticipantStateJoined
+	lkParticipantStateActive
+	lkParticipantStateDisconnected
+)
+
+type lkLeaveAction int32
+
+const (
+	lkLeaveActionDisconnect lkLeaveAction = iota
+	lkLeaveActionResume
+	lkLeaveActionReconnect
+)
+
+type lkSignalRequest struct {
+	Offer    *lkSessionDescription `proto:"1"`
+	Answer   *lkSessionDescription `proto:"2"`
+	Trickle  *lkTrickleRequest     `proto:"3"`
+	AddTrack *lkAddTrackRequest    `proto:"4"`
+	Mute     *lkMuteTrackRequest   `proto:"5"`
+	Leave    *lkLeaveRequest       `proto:"8"`
+	Ping     *int64                `proto:"14"`
+	PingReq  *lkPing               `proto:"16"`
+}
+
+type lkSignalResponse struct {
+	Join             *lkJoinResponse             `proto:"1"`
+	Answer           *lkSessionDescription       `proto:"2"`
+	Offer            *lkSessionDescription       `proto:"3"`
+	Trickle          *lkTrickleRequest           `proto:"4"`
+	Update           *lkParticipantUpdate        `proto:"5"`
+	TrackPublished   *lkTrackPublishedResponse   `proto:"6"`
+	Leave            *lkLeaveRequest             `proto:"8"`
+	SpeakersChanged  *lkSpeakersChanged          `proto:"10"`
+	RefreshToken     *string                     `proto:"16"`
+	TrackUnpublished *lkTrackUnpublishedResponse `proto:"17"`
+	Pong             *int64                      `proto:"18"`
+	Reconnect        *lkReconnectResponse        `proto:"19"`
+	PongResp         *lkPong                     `proto:"20"`
+}
+
+type lkSessionDescription struct {
+	Type string `proto:"1"`
+	SDP  string `proto:"2"`
+}
+
+type lkTrickleRequest struct {
+	CandidateInit string         `proto:"1"`
+	Target        lkSignalTarget `proto:"2"`
+	Final         bool           `proto:"3"`
+}
+
+type lkAddTrackRequest struct {
+	Cid        string        `proto:"1"`
+	Name       string        `proto:"2"`
+	Type       lkTrackType   `proto:"3"`
+	Width      uint32        `proto:"4"`
+	Height     uint32        `proto:"5"`
+	Muted      bool          `proto:"6"`
+	DisableDTX bool          `proto:"7"`
+	Source     lkTrackSource `proto:"8"`
+}
+
+type lkMuteTrackRequest struct {
+	Sid   string `proto:"1"`
+	Muted bool   `proto:"2"`
+}
+
+type lkLeaveRequest struct {
+	CanReconnect bool          `proto:"1"`
+	Reason       int32         `proto:"2"`
+	Action       lkLeaveAction `proto:"3"`
+}
+
+type lkPing struct {
+	Timestamp int64 `proto:"1"`
+	RTT       int64 `proto:"2"`
+}
+
+type lkPong struct {
+	LastPingTimestamp int64 `proto:"1"`
+	Timestamp         int64 `proto:"2"`
+}
+
+type lkJoinResponse struct {
+	Room              lkRoom              `proto:"1"`
+	Participant       lkParticipantInfo   `proto:"2"`
+	OtherParticipants []lkParticipantInfo `proto:"3"`
+	ServerVersion     string              `proto:"4"`
+	ICEServers        []lkICEServer       `proto:"5"`
+	SubscriberPrimary bool                `proto:"6"`
+	PingTimeout       int32               `proto:"10"`
+	PingInterval      int32               `proto:"11"`
+}
+
+type lkReconnectResponse struct {
+	ICEServers []lkICEServer `proto:"1"`
+}
+
+type lkRoom struct {
+	Sid  string `proto:"1"`
+	Name string `proto:"2"`
+}
+
+type lkParticipantInfo struct {
+	Sid      string             `proto:"1"`
+	Identity string             `proto:"2"`
+	State    lkParticipantState `proto:"3"`
+	Tracks   []lkTrackInfo      `proto:"4"`
+	Metadata string             `proto:"5"`
+	Name     string             `proto:"9"`
+}
+
+type lkParticipantUpdate struct {
+	Participants []lkParticipantInfo `proto:"1"`
+}
+
+type lkSpeakersChanged struct {
+	Speakers []lkSpeakerInfo `proto:"1"`
+}
+
+type lkSpeakerInfo struct {
+	Sid    string  `proto:"1"`
+	Level  float32 `proto:"2"`
+	Active bool    `proto:"3"`
+}
+
+type lkTrackInfo struct {
+	Sid    string        `proto:"1"`
+	Type   lkTrackType   `proto:"2"`
+	Name   string        `proto:"3"`
+	Muted  bool          `proto:"4"`
+	Source lkTrackSource `proto:"9"`
+}
+
+type lkTrackPublishedResponse struct {
+	Cid   string      `proto:"1"`
+	Track lkTrackInfo `proto:"2"`
+}
+
+type lkTrackUnpublishedResponse struct {
+	TrackSid string `proto:"1"`
+}
+
+type lkICEServer struct {
+	URLs       []string `proto:"1"`
+	Username   string   `proto:"2"`
+	Credential string   `proto:"3"`
+}
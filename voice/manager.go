@@ -23,17 +23,17 @@ type (
 		// HandleVoiceServerUpdate handles a gateway.EventVoiceServerUpdate
 		HandleVoiceServerUpdate(update gateway.EventVoiceServerUpdate)
 
-		// CreateConn creates a new voice connection for the given guild.
-		CreateConn(guildID snowflake.ID) Conn
+		// CreateConn creates a new voice connection for the given ConnTarget. Use GuildTarget for guilds and CallTarget for calls in private channels.
+		CreateConn(target ConnTarget) Conn
 
-		// GetConn returns the voice connection for the given guild.
-		GetConn(guildID snowflake.ID) Conn
+		// GetConn returns the voice connection for the given ConnTarget.
+		GetConn(target ConnTarget) Conn
 
 		// Conns returns all voice connections. This function is thread-safe.
 		Conns() iter.Seq[Conn]
 
-		// RemoveConn removes the voice connection for the given guild.
-		RemoveConn(guildID snowflake.ID)
+		// RemoveConn removes the voice connection for the given ConnTarget.
+		RemoveConn(target ConnTarget)
 
 		// Close closes all voice connections.
 		Close(ctx context.Context)
@@ -49,7 +49,7 @@ func NewManager(voiceStateUpdateFunc StateUpdateFunc, userID snowflake.ID, opts
 		config:               cfg,
 		voiceStateUpdateFunc: voiceStateUpdateFunc,
 		userID:               userID,
-		conns:                make(map[snowflake.ID]Conn),
+		conns:                make(map[ConnTarget]Conn),
 	}
 }
 
@@ -58,60 +58,88 @@ type managerImpl struct {
 	voiceStateUpdateFunc StateUpdateFunc
 	userID               snowflake.ID
 
-	conns   map[snowflake.ID]Conn
+	conns   map[ConnTarget]Conn
 	connsMu sync.Mutex
 }
 
 func (m *managerImpl) HandleVoiceStateUpdate(update gateway.EventVoiceStateUpdate) {
 	m.config.Logger.Debug("new VoiceStateUpdate", slog.Int64("guild_id", int64(update.GuildID)))
 
-	conn := m.GetConn(update.GuildID)
-	if conn == nil {
+	if update.GuildID != 0 {
+		if conn := m.GetConn(GuildTarget(update.GuildID)); conn != nil {
+			conn.HandleVoiceStateUpdate(update)
+		}
+		return
+	}
+	if update.ChannelID != nil {
+		if conn := m.GetConn(CallTarget(*update.ChannelID)); conn != nil {
+			conn.HandleVoiceStateUpdate(update)
+		}
 		return
 	}
-	conn.HandleVoiceStateUpdate(update)
+
+	// leaving a call has neither a guild nor a channel, so all calls have to handle it.
+	// The conns are collected first so their callbacks can use the manager without deadlocking.
+	m.connsMu.Lock()
+	var calls []Conn
+	for target, conn := range m.conns {
+		if target.IsCall() {
+			calls = append(calls, conn)
+		}
+	}
+	m.connsMu.Unlock()
+	for _, conn := range calls {
+		conn.HandleVoiceStateUpdate(update)
+	}
 }
 
 func (m *managerImpl) HandleVoiceServerUpdate(update gateway.EventVoiceServerUpdate) {
-	m.config.Logger.Debug("new VoiceServerUpdate", slog.Int64("guild_id", int64(update.GuildID)))
+	m.config.Logger.Debug("new VoiceServerUpdate", slog.Int64("guild_id", int64(update.GuildID)), slog.Int64("channel_id", int64(update.ChannelID)))
 
-	conn := m.GetConn(update.GuildID)
+	target := GuildTarget(update.GuildID)
+	if update.GuildID == 0 {
+		target = CallTarget(update.ChannelID)
+	}
+	conn := m.GetConn(target)
 	if conn == nil {
 		return
 	}
 	conn.HandleVoiceServerUpdate(update)
 }
 
-func (m *managerImpl) CreateConn(guildID snowflake.ID) Conn {
+func (m *managerImpl) CreateConn(target ConnTarget) Conn {
 	m.connsMu.Lock()
 	defer m.connsMu.Unlock()
 
-	m.config.Logger.Debug("Creating new voice conn", slog.Int64("guild_id", int64(guildID)))
-	if conn, ok := m.conns[guildID]; ok {
+	m.config.Logger.Debug("Creating new voice conn", slog.Int64("guild_id", int64(target.GuildID)), slog.Int64("channel_id", int64(target.ChannelID)))
+	if conn, ok := m.conns[target]; ok {
 		return conn
 	}
 
-	if m.config.LiveKitConnCreateFunc == nil {
-		panic("Missing LiveKitConnCreateFunc in ManagerConfig, please provide one using WithLiveKitConnCreateFunc")
-	}
-
 	var once sync.Once
 	removeFunc := func() {
 		once.Do(func() {
-			m.RemoveConn(guildID)
+			m.RemoveConn(target)
 		})
 	}
 
-	conn := m.config.ConnCreateFunc(m.config.LiveKitConnCreateFunc(), guildID, m.userID, m.voiceStateUpdateFunc, removeFunc, append([]ConnConfigOpt{WithConnLogger(m.config.Logger)}, m.config.ConnOpts...)...)
-	m.conns[guildID] = conn
+	var liveKitConn LiveKitConn
+	if m.config.LiveKitConnCreateFunc != nil {
+		liveKitConn = m.config.LiveKitConnCreateFunc()
+	} else {
+		liveKitConn = NewLiveKitConn(append([]LiveKitConnConfigOpt{WithLiveKitConnLogger(m.config.Logger)}, m.config.LiveKitConnOpts...)...)
+	}
+
+	conn := m.config.ConnCreateFunc(liveKitConn, target, m.userID, m.voiceStateUpdateFunc, removeFunc, append([]ConnConfigOpt{WithConnLogger(m.config.Logger)}, m.config.ConnOpts...)...)
+	m.conns[target] = conn
 
 	return conn
 }
 
-func (m *managerImpl) GetConn(guildID snowflake.ID) Conn {
+func (m *managerImpl) GetConn(target ConnTarget) Conn {
 	m.connsMu.Lock()
 	defer m.connsMu.Unlock()
-	return m.conns[guildID]
+	return m.conns[target]
 }
 
 func (m *managerImpl) Conns() iter.Seq[Conn] {
@@ -126,17 +154,17 @@ func (m *managerImpl) Conns() iter.Seq[Conn] {
 	}
 }
 
-func (m *managerImpl) RemoveConn(guildID snowflake.ID) {
+func (m *managerImpl) RemoveConn(target ConnTarget) {
 	m.connsMu.Lock()
 	defer m.connsMu.Unlock()
 
-	m.config.Logger.Debug("Removing voice conn", slog.Int64("guild_id", int64(guildID)))
+	m.config.Logger.Debug("Removing voice conn", slog.Int64("guild_id", int64(target.GuildID)), slog.Int64("channel_id", int64(target.ChannelID)))
 
-	if _, ok := m.conns[guildID]; !ok {
+	if _, ok := m.conns[target]; !ok {
 		return
 	}
 
-	delete(m.conns, guildID)
+	delete(m.conns, target)
 }
 
 func (m *managerImpl) Close(ctx context.Context) {
@@ -146,5 +174,5 @@ func (m *managerImpl) Close(ctx context.Context) {
 	for i := range conns {
 		conns[i].Close(ctx)
 	}
-	m.conns = map[snowflake.ID]Conn{}
+	m.conns = map[ConnTarget]Conn{}
 }
@@ -17,6 +17,7 @@ type managerConfig struct {
 	ConnCreateFunc        ConnCreateFunc
 	ConnOpts              []ConnConfigOpt
 	LiveKitConnCreateFunc LiveKitConnCreateFunc
+	LiveKitConnOpts       []LiveKitConnConfigOpt
 }
 
 // ManagerConfigOpt is used to functionally configure a managerConfig.
@@ -50,8 +51,16 @@ func WithConnConfigOpts(opts ...ConnConfigOpt) ManagerConfigOpt {
 	}
 }
 
+// WithLiveKitConnCreateFunc sets the LiveKitConnCreateFunc for the Manager. The default creates the built-in LiveKitConn.
 func WithLiveKitConnCreateFunc(liveKitConnCreateFunc LiveKitConnCreateFunc) ManagerConfigOpt {
 	return func(config *managerConfig) {
 		config.LiveKitConnCreateFunc = liveKitConnCreateFunc
 	}
 }
+
+// WithLiveKitConnConfigOpts sets the LiveKitConnConfigOpt(s) of the built-in LiveKitConn.
+func WithLiveKitConnConfigOpts(opts ...LiveKitConnConfigOpt) ManagerConfigOpt {
+	return func(config *managerConfig) {
+		config.LiveKitConnOpts = append(config.LiveKitConnOpts, opts...)
+	}
+}
@@ -0,0 +1,151 @@
+package voice
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// This file implements the Ogg container framing (RFC 3533) used by OggOpusReader and OggOpusStreamWriter.
+
+const (
+	oggHeaderSize     = 27
+	oggMaxSegments    = 255
+	oggMaxSegmentSize = 255
+
+	oggHeaderTypeContinued = 0x01
+	oggHeaderTypeBOS       = 0x02
+	oggHeaderTypeEOS       = 0x04
+)
+
+var (
+	oggCapturePattern = []byte("OggS")
+
+	// ErrInvalidOggPage is returned if an Ogg page is malformed or its checksum does not match.
+	ErrInvalidOggPage = errors.New("invalid ogg page")
+)
+
+var oggCRCTable = func() [256]uint32 {
+	var table [256]uint32
+	for i := range table {
+		crc := uint32(i) << 24
+		for range 8 {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04c11db7
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}()
+
+func oggCRC(crc uint32, data []byte) uint32 {
+	for _, b := range data {
+		crc = crc<<8 ^ oggCRCTable[byte(crc>>24)^b]
+	}
+	return crc
+}
+
+// oggPage is a single page of an Ogg logical bitstream.
+type oggPage struct {
+	HeaderType byte
+	Granule    uint64
+	Serial     uint32
+	Sequence   uint32
+	Segments   []byte
+	Data       []byte
+}
+
+// packets calls f for every packet or packet fragment in the page. complete is false for the last fragment if it continues on the next page.
+func (p oggPage) packets(f func(data []byte, complete bool)) {
+	var start, size int
+	for i, segment := range p.Segments {
+		size += int(segment)
+		if segment < oggMaxSegmentSize {
+			f(p.Data[start:start+size], true)
+			start += size
+			size = 0
+		} else if i == len(p.Segments)-1 {
+			f(p.Data[start:start+size], false)
+		}
+	}
+}
+
+// readOggPage reads the next page from r and validates its checksum.
+func readOggPage(r io.Reader) (oggPage, error) {
+	var header [oggHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return oggPage{}, err
+	}
+	if !bytes.Equal(header[:4], oggCapturePattern) || header[4] != 0 {
+		return oggPage{}, fmt.Errorf("%w: missing capture pattern", ErrInvalidOggPage)
+	}
+
+	segments := make([]byte, header[26])
+	if _, err := io.ReadFull(r, segments); err != nil {
+		return oggPage{}, unexpectedEOF(err)
+	}
+	var size int
+	for _, segment := range segments {
+		size += int(segment)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return oggPage{}, unexpectedEOF(err)
+	}
+
+	checksum := binary.LittleEndian.Uint32(header[22:26])
+	clear(header[22:26])
+	crc := oggCRC(0, header[:])
+	crc = oggCRC(crc, segments)
+	if crc = oggCRC(crc, data); crc != checksum {
+		return oggPage{}, fmt.Errorf("%w: checksum mismatch", ErrInvalidOggPage)
+	}
+
+	return oggPage{
+		HeaderType: header[5],
+		Granule:    binary.LittleEndian.Uint64(header[6:14]),
+		Serial:     binary.LittleEndian.Uint32(header[14:18]),
+		Sequence:   binary.LittleEndian.Uint32(header[18:22]),
+		Segments:   segments,
+		Data:       data,
+	}, nil
+}
+
+// writeOggPage writes the page to w and calculates its checksum.
+func writeOggPage(w io.Writer, page oggPage) error {
+	buf := make([]byte, oggHeaderSize, oggHeaderSize+len(page.Segments)+len(page.Data))
+	copy(buf, oggCapturePattern)
+	buf[5] = page.HeaderType
+	binary.LittleEndian.PutUint64(buf[6:14], page.Granule)
+	binary.LittleEndian.PutUint32(buf[14:18], page.Serial)
+	binary.LittleEndian.PutUint32(buf[18:22], page.Sequence)
+	buf[26] = byte(len(page.Segments))
+	buf = append(buf, page.Segments...)
+	buf = append(buf, page.Data...)
+	binary.LittleEndian.PutUint32(buf[22:26], oggCRC(0, buf))
+
+	_, err := w.Write(buf)
+	return err
+}
+
+// oggLacing returns the lacing values of a packet with the given size.
+func oggLacing(size int) []byte {
+	lacing := make([]byte, size/oggMaxSegmentSize+1)
+	for i := range len(lacing) - 1 {
+		lacing[i] = oggMaxSegmentSize
+	}
+	lacing[len(lacing)-1] = byte(size % oggMaxSegmentSize)
+	return lacing
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
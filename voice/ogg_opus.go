@@ -0,0 +1,482 @@
+package voice
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	// oggOpusPacketsPerPage is the number of packets OggOpusStreamWriter buffers before it writes a page, which is about one second of audio.
+	oggOpusPacketsPerPage = 50
+	// oggOpusSampleRate is the rate of the granule position of Ogg Opus streams.
+	oggOpusSampleRate = 48000
+	oggOpusVendor     = "fluxergo"
+)
+
+var (
+	opusHeadMagic = []byte("OpusHead")
+	opusTagsMagic = []byte("OpusTags")
+
+	// ErrInvalidOggOpusStream is returned if the headers of an Ogg Opus stream are malformed.
+	ErrInvalidOggOpusStream = errors.New("invalid ogg opus stream")
+)
+
+// OpusHead is the identification header of an Ogg Opus stream.
+type OpusHead struct {
+	Version              byte
+	Channels             byte
+	PreSkip              uint16
+	InputSampleRate      uint32
+	OutputGain           int16
+	ChannelMappingFamily byte
+}
+
+func (h OpusHead) marshal() []byte {
+	buf := make([]byte, 0, 19)
+	buf = append(buf, opusHeadMagic...)
+	buf = append(buf, h.Version, h.Channels)
+	buf = binary.LittleEndian.AppendUint16(buf, h.PreSkip)
+	buf = binary.LittleEndian.AppendUint32(buf, h.InputSampleRate)
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(h.OutputGain))
+	return append(buf, h.ChannelMappingFamily)
+}
+
+func (h *OpusHead) unmarshal(data []byte) error {
+	if len(data) < 19 || !bytes.Equal(data[:8], opusHeadMagic) {
+		return fmt.Errorf("%w: missing OpusHead", ErrInvalidOggOpusStream)
+	}
+	if data[8]>>4 != 0 {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidOggOpusStream, data[8])
+	}
+	*h = OpusHead{
+		Version:              data[8],
+		Channels:             data[9],
+		PreSkip:              binary.LittleEndian.Uint16(data[10:12]),
+		InputSampleRate:      binary.LittleEndian.Uint32(data[12:16]),
+		OutputGain:           int16(binary.LittleEndian.Uint16(data[16:18])),
+		ChannelMappingFamily: data[18],
+	}
+	return nil
+}
+
+// OpusTags is the comment header of an Ogg Opus stream. Comments are in the form "KEY=value".
+type OpusTags struct {
+	Vendor   string
+	Comments []string
+}
+
+func (t OpusTags) marshal() []byte {
+	buf := append([]byte(nil), opusTagsMagic...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(t.Vendor)))
+	buf = append(buf, t.Vendor...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(t.Comments)))
+	for _, comment := range t.Comments {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(comment)))
+		buf = append(buf, comment...)
+	}
+	return buf
+}
+
+func (t *OpusTags) unmarshal(data []byte) error {
+	if len(data) < 8 || !bytes.Equal(data[:8], opusTagsMagic) {
+		return fmt.Errorf("%w: missing OpusTags", ErrInvalidOggOpusStream)
+	}
+	data = data[8:]
+	readString := func() (string, bool) {
+		if len(data) < 4 {
+			return "", false
+		}
+		size := binary.LittleEndian.Uint32(data)
+		if uint64(len(data)-4) < uint64(size) {
+			return "", false
+		}
+		s := string(data[4 : 4+size])
+		data = data[4+size:]
+		return s, true
+	}
+
+	vendor, ok := readString()
+	if !ok || len(data) < 4 {
+		return fmt.Errorf("%w: truncated OpusTags", ErrInvalidOggOpusStream)
+	}
+	count := binary.LittleEndian.Uint32(data)
+	data = data[4:]
+	tags := OpusTags{Vendor: vendor}
+	for range count {
+		comment, ok := readString()
+		if !ok {
+			return fmt.Errorf("%w: truncated OpusTags", ErrInvalidOggOpusStream)
+		}
+		tags.Comments = append(tags.Comments, comment)
+	}
+	*t = tags
+	return nil
+}
+
+// opusPacketSamples returns the number of samples at 48 kHz in the opus packet as described in RFC 6716 section 3.1.
+func opusPacketSamples(packet []byte) int {
+	if len(packet) == 0 {
+		return 0
+	}
+	toc := packet[0]
+	config := toc >> 3
+
+	var frameSamples int
+	switch {
+	case config < 12:
+		frameSamples = [...]int{480, 960, 1920, 2880}[config%4]
+	case config < 16:
+		frameSamples = [...]int{480, 960}[config%2]
+	default:
+		frameSamples = [...]int{120, 240, 480, 960}[config%4]
+	}
+
+	switch toc & 0x03 {
+	case 0:
+		return frameSamples
+	case 1, 2:
+		return 2 * frameSamples
+	default:
+		if len(packet) < 2 {
+			return 0
+		}
+		return int(packet[1]&0x3f) * frameSamples
+	}
+}
+
+// NewOggOpusReader returns a new OggOpusReader that reads opus frames from the Ogg Opus stream of the given io.Reader.
+func NewOggOpusReader(r io.Reader) *OggOpusReader {
+	return &OggOpusReader{
+		r: r,
+	}
+}
+
+// OggOpusReader reads the opus frames of an Ogg Opus stream (RFC 7845), for example a .opus file or the ogg output of ffmpeg.
+// Only the first logical stream is read, chained streams following it are supported.
+type OggOpusReader struct {
+	r io.Reader
+
+	serial    uint32
+	hasSerial bool
+	ended     bool
+	head      OpusHead
+	tags      OpusTags
+	headers   int
+
+	packets     [][]byte
+	partial     []byte
+	granule     uint64
+	syncGranule bool
+	samples     uint64
+}
+
+// ProvideOpusFrame reads the next opus frame from the Ogg Opus stream. The headers of the stream are parsed and skipped.
+func (r *OggOpusReader) ProvideOpusFrame() ([]byte, error) {
+	for {
+		if len(r.packets) == 0 {
+			if err := r.readPage(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		packet := r.packets[0]
+		r.packets = r.packets[1:]
+		switch r.headers {
+		case 0:
+			if err := r.head.unmarshal(packet); err != nil {
+				return nil, err
+			}
+			r.headers++
+			continue
+		case 1:
+			if err := r.tags.unmarshal(packet); err != nil {
+				return nil, err
+			}
+			r.headers++
+			continue
+		}
+
+		r.samples += uint64(opusPacketSamples(packet))
+		if len(r.packets) == 0 && r.syncGranule {
+			// the granule position of a page is the position after its last complete packet
+			r.samples = r.granule
+			r.syncGranule = false
+		}
+		return packet, nil
+	}
+}
+
+func (r *OggOpusReader) readPage() error {
+	page, err := readOggPage(r.r)
+	if err != nil {
+		return err
+	}
+
+	if !r.hasSerial || (r.ended && page.HeaderType&oggHeaderTypeBOS != 0) {
+		if page.HeaderType&oggHeaderTypeBOS == 0 {
+			return fmt.Errorf("%w: stream does not start with a BOS page", ErrInvalidOggOpusStream)
+		}
+		r.serial = page.Serial
+		r.hasSerial = true
+		r.ended = false
+		r.headers = 0
+		r.samples = 0
+		r.partial = nil
+	}
+	if page.Serial != r.serial {
+		// pages of other multiplexed logical streams are skipped
+		return nil
+	}
+	if page.HeaderType&oggHeaderTypeEOS != 0 {
+		r.ended = true
+	}
+
+	continued := page.HeaderType&oggHeaderTypeContinued != 0
+	page.packets(func(data []byte, complete bool) {
+		if continued {
+			continued = false
+			if r.partial == nil {
+				// the stream started in the middle of a packet
+				return
+			}
+			data = append(r.partial, data...)
+			r.partial = nil
+		}
+		if !complete {
+			r.partial = append([]byte(nil), data...)
+			return
+		}
+		r.packets = append(r.packets, data)
+	})
+	if r.headers >= 2 && page.Granule != ^uint64(0) && len(r.packets) > 0 {
+		r.granule = page.Granule
+		r.syncGranule = true
+	}
+	return nil
+}
+
+// Head returns the OpusHead of the stream. It is available after the first frame was read.
+func (r *OggOpusReader) Head() OpusHead {
+	return r.head
+}
+
+// Tags returns the OpusTags of the stream. It is available after the first frame was read.
+func (r *OggOpusReader) Tags() OpusTags {
+	return r.tags
+}
+
+// Position returns the playback position after the last read frame, based on the granule positions of the stream.
+func (r *OggOpusReader) Position() time.Duration {
+	samples := r.samples - min(r.samples, uint64(r.head.PreSkip))
+	return time.Duration(samples) * time.Second / oggOpusSampleRate
+}
+
+// Close is a no-op.
+func (*OggOpusReader) Close() {}
+
+// NewOggOpusStreamWriter returns a new OggOpusStreamWriter that writes a stereo Ogg Opus stream to the given io.Writer.
+func NewOggOpusStreamWriter(w io.Writer) *OggOpusStreamWriter {
+	return &OggOpusStreamWriter{
+		w:      w,
+		serial: rand.Uint32(),
+	}
+}
+
+// OggOpusStreamWriter writes opus frames as an Ogg Opus stream (RFC 7845) which can be saved as .opus file.
+// Frames are buffered and written in pages of about one second. Call Close to write the remaining frames and end the stream.
+type OggOpusStreamWriter struct {
+	w              io.Writer
+	serial         uint32
+	sequence       uint32
+	granule        uint64
+	headersWritten bool
+	closed         bool
+
+	segments []byte
+	data     []byte
+	packets  int
+}
+
+// Write writes a single opus frame to the stream.
+func (w *OggOpusStreamWriter) Write(p []byte) (int, error) {
+	if err := w.WriteFrame(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// WriteFrame writes a single opus frame to the stream. The granule position advances by the duration of the frame.
+func (w *OggOpusStreamWriter) WriteFrame(frame []byte) error {
+	if w.closed {
+		return io.ErrClosedPipe
+	}
+	if err := w.writeHeaders(); err != nil {
+		return err
+	}
+
+	lacing := oggLacing(len(frame))
+	if len(lacing) > oggMaxSegments {
+		return fmt.Errorf("opus frame too large: %d bytes", len(frame))
+	}
+	if len(w.segments)+len(lacing) > oggMaxSegments {
+		if err := w.flush(0); err != nil {
+			return err
+		}
+	}
+	w.segments = append(w.segments, lacing...)
+	w.data = append(w.data, frame...)
+	w.granule += uint64(opusPacketSamples(frame))
+	w.packets++
+
+	if w.packets >= oggOpusPacketsPerPage {
+		return w.flush(0)
+	}
+	return nil
+}
+
+// Flush writes the buffered frames as a page.
+func (w *OggOpusStreamWriter) Flush() error {
+	if w.packets == 0 {
+		return nil
+	}
+	return w.flush(0)
+}
+
+// Close writes the buffered frames and ends the stream. It does not close the underlying io.Writer.
+func (w *OggOpusStreamWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	if err := w.writeHeaders(); err != nil {
+		return err
+	}
+	w.closed = true
+	return w.flush(oggHeaderTypeEOS)
+}
+
+func (w *OggOpusStreamWriter) writeHeaders() error {
+	if w.headersWritten {
+		return nil
+	}
+	w.headersWritten = true
+
+	head := OpusHead{
+		Version:         1,
+		Channels:        2,
+		InputSampleRate: oggOpusSampleRate,
+	}.marshal()
+	if err := w.writePacketPage(oggHeaderTypeBOS, head); err != nil {
+		return err
+	}
+	return w.writePacketPage(0, OpusTags{Vendor: oggOpusVendor}.marshal())
+}
+
+// writePacketPage writes a header packet on its own page.
+func (w *OggOpusStreamWriter) writePacketPage(headerType byte, packet []byte) error {
+	w.segments = oggLacing(len(packet))
+	w.data = packet
+	return w.flush(headerType)
+}
+
+func (w *OggOpusStreamWriter) flush(headerType byte) error {
+	page := oggPage{
+		HeaderType: headerType,
+		Granule:    w.granule,
+		Serial:     w.serial,
+		Sequence:   w.sequence,
+		Segments:   w.segments,
+		Data:       w.data,
+	}
+	w.sequence++
+	w.segments = nil
+	w.data = nil
+	w.packets = 0
+	return writeOggPage(w.w, page)
+}
+
+var _ AudioReceiver = (*OggOpusWriter)(nil)
+
+// NewOggOpusWriter returns a new AudioReceiver that writes the audio of every user to its own Ogg Opus stream.
+// newWriter is called with the user ID when the first frame of a user is received, for example to create a .opus file.
+// The io.WriteCloser is closed when the user left or the OggOpusWriter is closed.
+func NewOggOpusWriter(newWriter func(userID snowflake.ID) (io.WriteCloser, error), userFilter UserFilterFunc) *OggOpusWriter {
+	return &OggOpusWriter{
+		newWriter:  newWriter,
+		userFilter: userFilter,
+		streams:    make(map[snowflake.ID]*oggOpusUserStream),
+	}
+}
+
+// OggOpusWriter is an AudioReceiver that writes the opus frames of every user to its own Ogg Opus stream.
+type OggOpusWriter struct {
+	newWriter  func(userID snowflake.ID) (io.WriteCloser, error)
+	userFilter UserFilterFunc
+
+	mu      sync.Mutex
+	streams map[snowflake.ID]*oggOpusUserStream
+}
+
+type oggOpusUserStream struct {
+	w      io.WriteCloser
+	stream *OggOpusStreamWriter
+}
+
+func (s *oggOpusUserStream) close() error {
+	err := s.stream.Close()
+	return errors.Join(err, s.w.Close())
+}
+
+// ParticipantJoin is a no-op. The stream of a user is created when the first frame is received.
+func (*OggOpusWriter) ParticipantJoin(_ snowflake.ID) {}
+
+// ReceiveOpusFrame writes the opus frame to the stream of the user.
+func (w *OggOpusWriter) ReceiveOpusFrame(userID snowflake.ID, packet *Packet) error {
+	if w.userFilter != nil && !w.userFilter(userID) {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	stream, ok := w.streams[userID]
+	if !ok {
+		writer, err := w.newWriter(userID)
+		if err != nil {
+			return fmt.Errorf("error while creating ogg opus stream: %w", err)
+		}
+		stream = &oggOpusUserStream{w: writer, stream: NewOggOpusStreamWriter(writer)}
+		w.streams[userID] = stream
+	}
+	if err := stream.stream.WriteFrame(packet.Opus); err != nil {
+		return fmt.Errorf("error while writing opus frame: %w", err)
+	}
+	return nil
+}
+
+// CleanupUser ends and closes the stream of the user.
+func (w *OggOpusWriter) CleanupUser(userID snowflake.ID) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if stream, ok := w.streams[userID]; ok {
+		_ = stream.close()
+		delete(w.streams, userID)
+	}
+}
+
+// Close ends and closes the streams of all users.
+func (w *OggOpusWriter) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for userID, stream := range w.streams {
+		_ = stream.close()
+		delete(w.streams, userID)
+	}
+}
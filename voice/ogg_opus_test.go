@@ -0,0 +1,81 @@
+package voice
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestOggCRC(t *testing.T) {
+	if crc := oggCRC(0, []byte("123456789")); crc != 0x89a1897f {
+		t.Fatalf("unexpected crc: %#x", crc)
+	}
+}
+
+func TestOggOpusRoundTrip(t *testing.T) {
+	// 20ms CELT frames of different sizes, some of them need multiple lacing values
+	var frames [][]byte
+	for i := range 120 {
+		frame := make([]byte, 1+(i*37)%700)
+		frame[0] = 0xfc
+		for j := 1; j < len(frame); j++ {
+			frame[j] = byte(i + j)
+		}
+		frames = append(frames, frame)
+	}
+
+	buf := &bytes.Buffer{}
+	w := NewOggOpusStreamWriter(buf)
+	for _, frame := range frames {
+		if _, err := w.Write(frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewOggOpusReader(bytes.NewReader(buf.Bytes()))
+	for i, expected := range frames {
+		frame, err := r.ProvideOpusFrame()
+		if err != nil {
+			t.Fatalf("frame %d: %v", i, err)
+		}
+		if !bytes.Equal(frame, expected) {
+			t.Fatalf("frame %d: expected %d bytes, got %d bytes", i, len(expected), len(frame))
+		}
+	}
+	if _, err := r.ProvideOpusFrame(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+
+	if head := r.Head(); head.Channels != 2 || head.InputSampleRate != 48000 {
+		t.Fatalf("unexpected opus head: %+v", head)
+	}
+	if tags := r.Tags(); tags.Vendor != oggOpusVendor {
+		t.Fatalf("unexpected opus tags: %+v", tags)
+	}
+	if position := r.Position(); position != time.Duration(len(frames))*OpusFrameDuration {
+		t.Fatalf("unexpected position: %s", position)
+	}
+}
+
+func TestOggOpusReaderChecksum(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewOggOpusStreamWriter(buf)
+	if err := w.WriteFrame([]byte{0xfc, 0x01, 0x02}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	data[len(data)-1] ^= 0xff
+	r := NewOggOpusReader(bytes.NewReader(data))
+	if _, err := r.ProvideOpusFrame(); !errors.Is(err, ErrInvalidOggPage) {
+		t.Fatalf("expected ErrInvalidOggPage, got %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/disgoorg/snowflake/v2"
 )
@@ -14,8 +15,24 @@ const (
 
 	// OpusFrameSizeBytes is the size of an opus frame in bytes.
 	OpusFrameSizeBytes = OpusFrameSize * 2 * 2
+
+	// OpusFrameDuration is the duration of an opus frame.
+	OpusFrameDuration = 20 * time.Millisecond
 )
 
+// SilenceAudioFrame is an opus frame of silence.
+var SilenceAudioFrame = []byte{0xF8, 0xFF, 0xFE}
+
+// OpusFrameProvider provides opus frames, for example from a file.
+type OpusFrameProvider interface {
+	// ProvideOpusFrame returns the next opus frame or io.EOF if there are no more frames.
+	// The returned frame is only valid until the next call.
+	ProvideOpusFrame() ([]byte, error)
+
+	// Close closes the OpusFrameProvider.
+	Close()
+}
+
 // UserFilterFunc is used as a filter for which users to receive audio from.
 type UserFilterFunc func(userID snowflake.ID) bool
 
@@ -52,7 +69,7 @@ func (h *OpusReader) ProvideOpusFrame() ([]byte, error) {
 // Close is a no-op.
 func (*OpusReader) Close() {}
 
-// NewOpusWriter returns a new OpusFrameReceiver that writes opus frames to the given io.Writer.
+// NewOpusWriter returns a new AudioReceiver that writes opus frames to the given io.Writer.
 func NewOpusWriter(w io.Writer, userFilter UserFilterFunc) *OpusWriter {
 	return &OpusWriter{
 		w:          w,
@@ -60,13 +77,18 @@ func NewOpusWriter(w io.Writer, userFilter UserFilterFunc) *OpusWriter {
 	}
 }
 
-// OpusWriter is an OpusFrameReceiver that writes opus frames to the given io.Writer.
+var _ AudioReceiver = (*OpusWriter)(nil)
+
+// OpusWriter is an AudioReceiver that writes opus frames to the given io.Writer.
 // Each opus frame is prefixed with a 4 byte little endian uint32 that represents the length of the frame.
 type OpusWriter struct {
 	w          io.Writer
 	userFilter UserFilterFunc
 }
 
+// ParticipantJoin is a no-op.
+func (*OpusWriter) ParticipantJoin(_ snowflake.ID) {}
+
 // ReceiveOpusFrame writes the given opus frame to the underlying io.Writer.
 func (r *OpusWriter) ReceiveOpusFrame(userID snowflake.ID, packet *Packet) error {
 	if r.userFilter != nil && !r.userFilter(userID) {
@@ -0,0 +1,461 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrPlayerNotPlaying is returned by Player.Seek if the Player has no current Track.
+	ErrPlayerNotPlaying = errors.New("player is not playing a track")
+
+	// ErrSeekOutOfRange is returned by Player.Seek if the position is negative or behind the end of the current Track.
+	ErrSeekOutOfRange = errors.New("seek position out of range")
+)
+
+// LoopMode defines what a Player does after a Track finished.
+type LoopMode int
+
+const (
+	// LoopModeOff plays the next Track of the queue.
+	LoopModeOff LoopMode = iota
+	// LoopModeTrack plays the current Track again.
+	LoopModeTrack
+	// LoopModeQueue appends the current Track to the end of the queue.
+	LoopModeQueue
+)
+
+// TrackEndReason is the reason why a Track ended.
+type TrackEndReason int
+
+const (
+	TrackEndReasonFinished TrackEndReason = iota
+	TrackEndReasonSkipped
+	TrackEndReasonStopped
+	TrackEndReasonError
+)
+
+type (
+	// PlayerEventFunc is called for every PlayerEvent of a Player.
+	PlayerEventFunc func(event PlayerEvent)
+
+	// PlayerEvent is one of TrackStart, TrackEnd or TrackError.
+	PlayerEvent interface {
+		playerEvent()
+	}
+
+	// TrackStart is dispatched when a Player starts playing a Track.
+	TrackStart struct {
+		Player Player
+		Target ConnTarget
+		Track  Track
+	}
+
+	// TrackEnd is dispatched when a Track finished, was skipped or stopped or failed.
+	TrackEnd struct {
+		Player Player
+		Target ConnTarget
+		Track  Track
+		Reason TrackEndReason
+		// Position is the number of frames which were played.
+		Position int
+	}
+
+	// TrackError is dispatched when a Track could not be opened or read.
+	TrackError struct {
+		Player Player
+		Target ConnTarget
+		Track  Track
+		Err    error
+	}
+)
+
+func (TrackStart) playerEvent() {}
+func (TrackEnd) playerEvent()   {}
+func (TrackError) playerEvent() {}
+
+// Player plays a queue of Track(s) to the audio track of a Conn. It writes one frame every OpusFrameDuration.
+type Player interface {
+	// Enqueue appends the Track(s) to the queue. The Player starts playing if it is idle.
+	Enqueue(tracks ...Track)
+
+	// Queue returns the queued Track(s) without the current Track.
+	Queue() []Track
+
+	// ClearQueue removes all queued Track(s). The current Track keeps playing.
+	ClearQueue()
+
+	// Current returns the current Track and whether the Player has one.
+	Current() (Track, bool)
+
+	// Position returns the number of frames played of the current Track.
+	Position() int
+
+	// Pause pauses the current Track. The Player sends silence frames while it is paused.
+	Pause()
+
+	// Resume resumes the current Track.
+	Resume()
+
+	// Paused returns whether the Player is paused.
+	Paused() bool
+
+	// Skip ends the current Track and plays the next Track of the queue.
+	Skip()
+
+	// Seek seeks the current Track to the given frame. Seeking backwards opens the Track again.
+	// If the Track ends before the frame, ErrSeekOutOfRange is returned and the Track finishes.
+	Seek(frame int) error
+
+	// SetLoop sets the LoopMode.
+	SetLoop(mode LoopMode)
+
+	// Loop returns the LoopMode.
+	Loop() LoopMode
+
+	// Stop ends the current Track and clears the queue.
+	Stop()
+
+	// Close stops the Player and closes its audio track. It must not be called from a PlayerEventFunc.
+	Close()
+}
+
+// NewPlayer returns a new Player which publishes an audio track via Conn.LiveKit. The Conn has to be open.
+func NewPlayer(conn Conn, opts ...PlayerConfigOpt) (Player, error) {
+	cfg := defaultPlayerConfig()
+	cfg.apply(opts)
+
+	w, err := conn.LiveKit().AudioWriter(cfg.TrackName, cfg.Source)
+	if err != nil {
+		return nil, fmt.Errorf("error while creating audio writer: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &playerImpl{
+		config: cfg,
+		target: conn.Target(),
+		w:      w,
+		wake:   make(chan struct{}, 1),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go p.run(ctx)
+	return p, nil
+}
+
+type playerImpl struct {
+	config playerConfig
+	target ConnTarget
+	w      io.WriteCloser
+
+	mu       sync.Mutex
+	queue    []Track
+	current  *Track
+	provider OpusFrameProvider
+	position int
+	paused   bool
+	loop     LoopMode
+	// events are collected while mu is held and dispatched afterward, so PlayerEventFunc(s) can call the Player.
+	events []PlayerEvent
+
+	wake      chan struct{}
+	cancel    context.CancelFunc
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (p *playerImpl) Enqueue(tracks ...Track) {
+	p.mu.Lock()
+	p.queue = append(p.queue, tracks...)
+	p.mu.Unlock()
+	p.notify()
+}
+
+func (p *playerImpl) Queue() []Track {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.queue)
+}
+
+func (p *playerImpl) ClearQueue() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queue = nil
+}
+
+func (p *playerImpl) Current() (Track, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.current == nil {
+		return Track{}, false
+	}
+	return *p.current, true
+}
+
+func (p *playerImpl) Position() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.position
+}
+
+func (p *playerImpl) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = true
+}
+
+func (p *playerImpl) Resume() {
+	p.mu.Lock()
+	p.paused = false
+	p.mu.Unlock()
+	p.notify()
+}
+
+func (p *playerImpl) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.paused
+}
+
+func (p *playerImpl) Skip() {
+	p.mu.Lock()
+	if p.current != nil {
+		p.endTrack(TrackEndReasonSkipped)
+	}
+	p.mu.Unlock()
+	p.notify()
+}
+
+func (p *playerImpl) Seek(frame int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.current == nil {
+		return ErrPlayerNotPlaying
+	}
+	if frame < 0 {
+		return ErrSeekOutOfRange
+	}
+
+	if frame < p.position {
+		provider, err := p.current.Open()
+		if err != nil {
+			return fmt.Errorf("error while opening track: %w", err)
+		}
+		p.provider.Close()
+		p.provider = provider
+		p.position = 0
+	}
+	for p.position < frame {
+		if _, err := p.provider.ProvideOpusFrame(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return ErrSeekOutOfRange
+			}
+			return fmt.Errorf("error while seeking track: %w", err)
+		}
+		p.position++
+	}
+	return nil
+}
+
+func (p *playerImpl) SetLoop(mode LoopMode) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.loop = mode
+}
+
+func (p *playerImpl) Loop() LoopMode {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.loop
+}
+
+func (p *playerImpl) Stop() {
+	p.mu.Lock()
+	p.queue = nil
+	if p.current != nil {
+		p.endTrack(TrackEndReasonStopped)
+	}
+	p.mu.Unlock()
+	p.notify()
+}
+
+func (p *playerImpl) Close() {
+	p.closeOnce.Do(func() {
+		p.cancel()
+		<-p.done
+
+		p.mu.Lock()
+		p.queue = nil
+		if p.current != nil {
+			p.endTrack(TrackEndReasonStopped)
+		}
+		events := p.takeEvents()
+		p.mu.Unlock()
+
+		p.dispatch(events)
+		if err := p.w.Close(); err != nil {
+			p.config.Logger.Error("error while closing audio writer", slog.Any("err", err))
+		}
+	})
+}
+
+func (p *playerImpl) notify() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (p *playerImpl) run(ctx context.Context) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.config.FrameDuration)
+	defer ticker.Stop()
+
+	var buf []byte
+	for {
+		frame, events, idle := p.nextFrame(buf[:0])
+		p.dispatch(events)
+		if frame != nil {
+			buf = frame
+			if _, err := p.w.Write(frame); err != nil {
+				if errors.Is(err, ErrLiveKitNotConnected) {
+					p.config.Logger.Debug("dropped frame while livekit is not connected")
+				} else {
+					p.config.Logger.Error("error while writing frame", slog.Any("err", err))
+				}
+			}
+		}
+
+		if idle {
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.wake:
+				ticker.Reset(p.config.FrameDuration)
+				continue
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// nextFrame appends the next frame to buf. It returns a nil frame and idle if there is nothing to play.
+func (p *playerImpl) nextFrame(buf []byte) (frame []byte, events []PlayerEvent, idle bool) {
+	p.mu.Lock()
+	defer func() {
+		events = p.takeEvents()
+		p.mu.Unlock()
+	}()
+
+	if p.paused {
+		if p.current == nil && len(p.queue) == 0 {
+			return nil, nil, true
+		}
+		return append(buf, SilenceAudioFrame...), nil, false
+	}
+
+	for {
+		if p.current == nil && !p.startNext() {
+			return nil, nil, true
+		}
+
+		data, err := p.provider.ProvideOpusFrame()
+		if err == nil {
+			p.position++
+			return append(buf, data...), nil, false
+		}
+		if errors.Is(err, io.EOF) {
+			p.endTrack(TrackEndReasonFinished)
+			continue
+		}
+		p.events = append(p.events, TrackError{
+			Player: p,
+			Target: p.target,
+			Track:  *p.current,
+			Err:    err,
+		})
+		p.endTrack(TrackEndReasonError)
+	}
+}
+
+// startNext opens the next Track of the queue. Track(s) which fail to open are skipped.
+func (p *playerImpl) startNext() bool {
+	for len(p.queue) > 0 {
+		track := p.queue[0]
+		p.queue = p.queue[1:]
+
+		provider, err := track.Open()
+		if err != nil {
+			p.events = append(p.events, TrackError{
+				Player: p,
+				Target: p.target,
+				Track:  track,
+				Err:    err,
+			})
+			continue
+		}
+
+		p.current = &track
+		p.provider = provider
+		p.position = 0
+		p.events = append(p.events, TrackStart{
+			Player: p,
+			Target: p.target,
+			Track:  track,
+		})
+		return true
+	}
+	return false
+}
+
+// endTrack closes the current Track and queues it again depending on the LoopMode.
+func (p *playerImpl) endTrack(reason TrackEndReason) {
+	track := *p.current
+	position := p.position
+	p.provider.Close()
+	p.current = nil
+	p.provider = nil
+	p.position = 0
+
+	p.events = append(p.events, TrackEnd{
+		Player:   p,
+		Target:   p.target,
+		Track:    track,
+		Reason:   reason,
+		Position: position,
+	})
+
+	// empty tracks are never looped, as they would end immediately again
+	switch {
+	case reason == TrackEndReasonFinished && position > 0 && p.loop == LoopModeTrack:
+		p.queue = slices.Insert(p.queue, 0, track)
+	case (reason == TrackEndReasonFinished && position > 0 || reason == TrackEndReasonSkipped) && p.loop == LoopModeQueue:
+		p.queue = append(p.queue, track)
+	}
+}
+
+func (p *playerImpl) takeEvents() []PlayerEvent {
+	events := p.events
+	p.events = nil
+	return events
+}
+
+func (p *playerImpl) dispatch(events []PlayerEvent) {
+	if p.config.EventFunc == nil {
+		return
+	}
+	for _, event := range events {
+		p.config.EventFunc(event)
+	}
+}
@@ -0,0 +1,56 @@
+package voice
+
+import (
+	"log/slog"
+	"time"
+)
+
+func defaultPlayerConfig() playerConfig {
+	return playerConfig{
+		Logger:        slog.Default(),
+		TrackName:     "audio",
+		Source:        AudioSourceMicrophone,
+		FrameDuration: OpusFrameDuration,
+	}
+}
+
+type playerConfig struct {
+	Logger        *slog.Logger
+	TrackName     string
+	Source        AudioSource
+	EventFunc     PlayerEventFunc
+	FrameDuration time.Duration
+}
+
+// PlayerConfigOpt is used to functionally configure a playerConfig.
+type PlayerConfigOpt func(config *playerConfig)
+
+func (c *playerConfig) apply(opts []PlayerConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.Logger = c.Logger.With(slog.String("name", "voice_player"))
+}
+
+// WithPlayerLogger sets the Player(s) used Logger.
+func WithPlayerLogger(logger *slog.Logger) PlayerConfigOpt {
+	return func(config *playerConfig) {
+		config.Logger = logger
+	}
+}
+
+// WithPlayerAudioTrack sets the name and AudioSource of the published audio track.
+func WithPlayerAudioTrack(name string, source AudioSource) PlayerConfigOpt {
+	return func(config *playerConfig) {
+		config.TrackName = name
+		config.Source = source
+	}
+}
+
+// WithPlayerEventFunc sets the PlayerEventFunc which is called for every PlayerEvent.
+// Use events.NewPlayerEventFunc to dispatch them through the bot.EventManager.
+func WithPlayerEventFunc(eventFunc PlayerEventFunc) PlayerConfigOpt {
+	return func(config *playerConfig) {
+		config.EventFunc = eventFunc
+	}
+}
@@ -0,0 +1,157 @@
+package voice
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestPlayer(t *testing.T) (Player, chan []byte, chan PlayerEvent) {
+	t.Helper()
+	frames := make(chan []byte, 4096)
+	events := make(chan PlayerEvent, 64)
+	conn := NewConn(&fakeLiveKitConn{frames: frames}, GuildTarget(1), 2, nil, func() {})
+	player, err := NewPlayer(conn,
+		WithPlayerEventFunc(func(event PlayerEvent) { events <- event }),
+		func(config *playerConfig) { config.FrameDuration = time.Millisecond },
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(player.Close)
+	return player, frames, events
+}
+
+func newTestTrack(t *testing.T, name string, id byte, frames int) Track {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	w := NewDCAWriter(buf, nil, nil)
+	for i := range frames {
+		if err := w.WriteFrame([]byte{0xfc, id, byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewBytesTrack(name, buf.Bytes(), TrackFormatDCA)
+}
+
+func waitPlayerEvent[E PlayerEvent](t *testing.T, events chan PlayerEvent) E {
+	t.Helper()
+	select {
+	case event := <-events:
+		e, ok := event.(E)
+		if !ok {
+			t.Fatalf("expected %T, got %#v", *new(E), event)
+		}
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for %T", *new(E))
+	}
+	panic("unreachable")
+}
+
+func TestPlayerQueue(t *testing.T) {
+	player, frames, events := newTestPlayer(t)
+
+	openErr := errors.New("open error")
+	player.Enqueue(
+		newTestTrack(t, "a", 1, 3),
+		Track{Name: "broken", Open: func() (OpusFrameProvider, error) { return nil, openErr }},
+		newTestTrack(t, "b", 2, 2),
+	)
+
+	if e := waitPlayerEvent[TrackStart](t, events); e.Track.Name != "a" || e.Target.GuildID != 1 {
+		t.Fatalf("unexpected track start: %+v", e)
+	}
+	if e := waitPlayerEvent[TrackEnd](t, events); e.Track.Name != "a" || e.Reason != TrackEndReasonFinished || e.Position != 3 {
+		t.Fatalf("unexpected track end: %+v", e)
+	}
+	if e := waitPlayerEvent[TrackError](t, events); e.Track.Name != "broken" || !errors.Is(e.Err, openErr) {
+		t.Fatalf("unexpected track error: %+v", e)
+	}
+	if e := waitPlayerEvent[TrackStart](t, events); e.Track.Name != "b" {
+		t.Fatalf("unexpected track start: %+v", e)
+	}
+	if e := waitPlayerEvent[TrackEnd](t, events); e.Track.Name != "b" || e.Position != 2 {
+		t.Fatalf("unexpected track end: %+v", e)
+	}
+
+	expected := [][]byte{{0xfc, 1, 0}, {0xfc, 1, 1}, {0xfc, 1, 2}, {0xfc, 2, 0}, {0xfc, 2, 1}}
+	for i, frame := range expected {
+		if written := <-frames; !bytes.Equal(written, frame) {
+			t.Fatalf("frame %d: expected %v, got %v", i, frame, written)
+		}
+	}
+	if _, ok := player.Current(); ok {
+		t.Fatal("expected player to be idle")
+	}
+}
+
+func TestPlayerControls(t *testing.T) {
+	player, frames, events := newTestPlayer(t)
+
+	player.Pause()
+	player.Enqueue(newTestTrack(t, "long", 1, 250), newTestTrack(t, "short", 2, 1))
+	if frame := <-frames; !bytes.Equal(frame, SilenceAudioFrame) {
+		t.Fatalf("expected silence frame while paused, got %v", frame)
+	}
+	if _, ok := player.Current(); ok {
+		t.Fatal("expected no track to be started while paused")
+	}
+
+	player.Resume()
+	waitPlayerEvent[TrackStart](t, events)
+	player.Pause()
+	if err := player.Seek(200); err != nil {
+		t.Fatal(err)
+	}
+	if err := player.Seek(10); err != nil {
+		t.Fatal(err)
+	}
+	if position := player.Position(); position != 10 {
+		t.Fatalf("expected position 10, got %d", position)
+	}
+	if err := player.Seek(300); !errors.Is(err, ErrSeekOutOfRange) {
+		t.Fatalf("expected ErrSeekOutOfRange, got %v", err)
+	}
+	if err := player.Seek(10); err != nil {
+		t.Fatal(err)
+	}
+
+	player.Resume()
+	for {
+		// skip silence and the frames written before the player was paused
+		frame := <-frames
+		if bytes.Equal(frame, SilenceAudioFrame) || frame[2] < 10 {
+			continue
+		}
+		if !bytes.Equal(frame, []byte{0xfc, 1, 10}) {
+			t.Fatalf("expected frame 10 after seek, got %v", frame)
+		}
+		break
+	}
+
+	player.SetLoop(LoopModeTrack)
+	player.Skip()
+	if e := waitPlayerEvent[TrackEnd](t, events); e.Track.Name != "long" || e.Reason != TrackEndReasonSkipped {
+		t.Fatalf("unexpected track end: %+v", e)
+	}
+	for range 2 {
+		if e := waitPlayerEvent[TrackStart](t, events); e.Track.Name != "short" {
+			t.Fatalf("unexpected track start: %+v", e)
+		}
+		if e := waitPlayerEvent[TrackEnd](t, events); e.Track.Name != "short" || e.Reason != TrackEndReasonFinished {
+			t.Fatalf("unexpected track end: %+v", e)
+		}
+	}
+
+	player.Stop()
+	for {
+		if e, ok := (<-events).(TrackEnd); ok && e.Reason == TrackEndReasonStopped {
+			break
+		}
+	}
+	if queue := player.Queue(); len(queue) != 0 {
+		t.Fatalf("expected empty queue, got %d tracks", len(queue))
+	}
+}
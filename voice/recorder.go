@@ -0,0 +1,326 @@
+package voice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// RecorderManifestFile is the name of the manifest which a Recorder writes to its directory.
+const RecorderManifestFile = "manifest.json"
+
+// opusSilenceSamples is the number of samples of SilenceAudioFrame.
+const opusSilenceSamples = 960
+
+// RecorderManifest describes the files of a recording. All offsets are relative to StartedAt.
+type RecorderManifest struct {
+	StartedAt time.Time      `json:"started_at"`
+	Files     []RecordedFile `json:"files"`
+}
+
+// RecordedFile is a single Ogg Opus file of a user in a RecorderManifest.
+type RecordedFile struct {
+	UserID snowflake.ID
+	// Name is the name of the file in the directory of the Recorder.
+	Name string
+	// StartOffset is the position of the start of the file on the timeline of the recording.
+	StartOffset time.Duration
+	// Duration is the duration of the audio in the file, including inserted silence.
+	Duration time.Duration
+}
+
+type recordedFileJSON struct {
+	UserID        snowflake.ID `json:"user_id"`
+	Name          string       `json:"name"`
+	StartOffsetMs int64        `json:"start_offset_ms"`
+	DurationMs    int64        `json:"duration_ms"`
+}
+
+// MarshalJSON writes the StartOffset and Duration in milliseconds.
+func (f RecordedFile) MarshalJSON() ([]byte, error) {
+	return json.Marshal(recordedFileJSON{
+		UserID:        f.UserID,
+		Name:          f.Name,
+		StartOffsetMs: f.StartOffset.Milliseconds(),
+		DurationMs:    f.Duration.Milliseconds(),
+	})
+}
+
+// UnmarshalJSON reads the StartOffset and Duration in milliseconds.
+func (f *RecordedFile) UnmarshalJSON(data []byte) error {
+	var v recordedFileJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*f = RecordedFile{
+		UserID:      v.UserID,
+		Name:        v.Name,
+		StartOffset: time.Duration(v.StartOffsetMs) * time.Millisecond,
+		Duration:    time.Duration(v.DurationMs) * time.Millisecond,
+	}
+	return nil
+}
+
+// Recorder is an AudioReceiver which records the audio of every user to its own Ogg Opus files.
+// Gaps in the audio of a user are filled with silence, so the files line up on a common timeline which starts when the Recorder is created.
+// The position of every file on the timeline is written to a RecorderManifest whenever a file is finished.
+type Recorder interface {
+	AudioReceiver
+
+	// Manifest returns the RecorderManifest of all files recorded so far, including the files which are still being written.
+	Manifest() RecorderManifest
+}
+
+// NewRecorder returns a new Recorder which writes its files and RecorderManifestFile to the given directory. The directory is created if it does not exist.
+// Set it with LiveKitConn.SetAudioReceiver.
+func NewRecorder(dir string, opts ...RecorderConfigOpt) (Recorder, error) {
+	cfg := defaultRecorderConfig()
+	cfg.apply(opts)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error while creating recording directory: %w", err)
+	}
+	return &recorderImpl{
+		config:   cfg,
+		dir:      dir,
+		start:    time.Now(),
+		streams:  make(map[snowflake.ID]*recorderStream),
+		segments: make(map[snowflake.ID]int),
+	}, nil
+}
+
+type recorderImpl struct {
+	config recorderConfig
+	dir    string
+	start  time.Time
+
+	mu      sync.Mutex
+	streams map[snowflake.ID]*recorderStream
+	// segments counts the files of every user to name them.
+	segments map[snowflake.ID]int
+	files    []RecordedFile
+	closed   bool
+}
+
+// recorderStream is the file a user is currently recorded to.
+type recorderStream struct {
+	// index is the index of the RecordedFile in recorderImpl.files.
+	index   int
+	f       *os.File
+	w       *countingWriter
+	stream  *OggOpusStreamWriter
+	samples uint64
+
+	hasLast       bool
+	ssrc          uint32
+	lastTimestamp uint32
+	lastSamples   int
+}
+
+func (s *recorderStream) duration() time.Duration {
+	return time.Duration(s.samples) * time.Second / oggOpusSampleRate
+}
+
+// ParticipantJoin is a no-op. The file of a user is created when the first frame is received.
+func (*recorderImpl) ParticipantJoin(_ snowflake.ID) {}
+
+// ReceiveOpusFrame writes the opus frame to the file of the user. Missing frames since the last frame of the user are written as silence.
+func (r *recorderImpl) ReceiveOpusFrame(userID snowflake.ID, packet *Packet) error {
+	if r.config.UserFilter != nil && !r.config.UserFilter(userID) {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+
+	offset := time.Since(r.start)
+	stream, ok := r.streams[userID]
+	if !ok {
+		var err error
+		if stream, err = r.openStream(userID, offset); err != nil {
+			return err
+		}
+	}
+
+	var gap int64
+	if stream.hasLast && stream.ssrc == packet.SSRC {
+		diff := int32(packet.Timestamp - stream.lastTimestamp)
+		if diff <= 0 {
+			// late or duplicate packet
+			return nil
+		}
+		gap = int64(diff) - int64(stream.lastSamples)
+	} else if stream.hasLast {
+		// the user published a new track, so the RTP timestamps are not comparable
+		position := r.files[stream.index].StartOffset + stream.duration()
+		gap = int64((offset - position) * oggOpusSampleRate / time.Second)
+	}
+	for ; gap >= opusSilenceSamples; gap -= opusSilenceSamples {
+		if err := r.writeFrame(userID, SilenceAudioFrame); err != nil {
+			return err
+		}
+	}
+
+	samples := opusPacketSamples(packet.Opus)
+	if err := r.writeFrame(userID, packet.Opus); err != nil {
+		return err
+	}
+	stream = r.streams[userID]
+	stream.hasLast = true
+	stream.ssrc = packet.SSRC
+	stream.lastTimestamp = packet.Timestamp
+	stream.lastSamples = samples
+	return nil
+}
+
+// writeFrame writes the frame to the file of the user and starts a new file if the current one reached the configured size or duration.
+func (r *recorderImpl) writeFrame(userID snowflake.ID, frame []byte) error {
+	stream := r.streams[userID]
+	if (r.config.MaxFileSize > 0 && stream.w.n >= r.config.MaxFileSize) || (r.config.MaxFileDuration > 0 && stream.duration() >= r.config.MaxFileDuration) {
+		offset := r.files[stream.index].StartOffset + stream.duration()
+		r.closeStream(userID)
+		var err error
+		if stream, err = r.openStream(userID, offset); err != nil {
+			return err
+		}
+		r.writeManifest()
+	}
+
+	if err := stream.stream.WriteFrame(frame); err != nil {
+		return fmt.Errorf("error while writing opus frame: %w", err)
+	}
+	stream.samples += uint64(opusPacketSamples(frame))
+	return nil
+}
+
+func (r *recorderImpl) openStream(userID snowflake.ID, offset time.Duration) (*recorderStream, error) {
+	segment := r.segments[userID]
+	name := fmt.Sprintf("%d_%03d.opus", userID, segment)
+	f, err := os.Create(filepath.Join(r.dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("error while creating recording file: %w", err)
+	}
+	r.segments[userID] = segment + 1
+
+	w := &countingWriter{w: f}
+	stream := &recorderStream{
+		index:  len(r.files),
+		f:      f,
+		w:      w,
+		stream: NewOggOpusStreamWriter(w),
+	}
+	r.files = append(r.files, RecordedFile{
+		UserID:      userID,
+		Name:        name,
+		StartOffset: offset,
+	})
+	r.streams[userID] = stream
+	return stream, nil
+}
+
+func (r *recorderImpl) closeStream(userID snowflake.ID) {
+	stream, ok := r.streams[userID]
+	if !ok {
+		return
+	}
+	delete(r.streams, userID)
+	r.files[stream.index].Duration = stream.duration()
+	if err := errors.Join(stream.stream.Close(), stream.f.Close()); err != nil {
+		r.config.Logger.Error("error while closing recording file", slog.String("file", r.files[stream.index].Name), slog.Any("err", err))
+	}
+}
+
+// CleanupUser finishes the file of the user. A new file is started if the user sends audio again.
+func (r *recorderImpl) CleanupUser(userID snowflake.ID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.streams[userID]; !ok {
+		return
+	}
+	r.closeStream(userID)
+	r.writeManifest()
+}
+
+// Close finishes the files of all users and writes the RecorderManifest.
+func (r *recorderImpl) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	r.closed = true
+	for userID := range r.streams {
+		r.closeStream(userID)
+	}
+	r.writeManifest()
+}
+
+func (r *recorderImpl) Manifest() RecorderManifest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.manifest()
+}
+
+func (r *recorderImpl) manifest() RecorderManifest {
+	files := slices.Clone(r.files)
+	for _, stream := range r.streams {
+		files[stream.index].Duration = stream.duration()
+	}
+	return RecorderManifest{
+		StartedAt: r.start,
+		Files:     files,
+	}
+}
+
+// writeManifest replaces the manifest by writing a temporary file and renaming it.
+func (r *recorderImpl) writeManifest() {
+	if err := r.writeManifestFile(); err != nil {
+		r.config.Logger.Error("error while writing recording manifest", slog.Any("err", err))
+	}
+}
+
+func (r *recorderImpl) writeManifestFile() error {
+	data, err := json.MarshalIndent(r.manifest(), "", "\t")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(r.dir, RecorderManifestFile)
+	tmp, err := os.CreateTemp(r.dir, RecorderManifestFile+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
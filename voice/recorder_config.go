@@ -0,0 +1,57 @@
+package voice
+
+import (
+	"log/slog"
+	"time"
+)
+
+func defaultRecorderConfig() recorderConfig {
+	return recorderConfig{
+		Logger: slog.Default(),
+	}
+}
+
+type recorderConfig struct {
+	Logger          *slog.Logger
+	UserFilter      UserFilterFunc
+	MaxFileSize     int64
+	MaxFileDuration time.Duration
+}
+
+// RecorderConfigOpt is used to functionally configure a recorderConfig.
+type RecorderConfigOpt func(config *recorderConfig)
+
+func (c *recorderConfig) apply(opts []RecorderConfigOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.Logger = c.Logger.With(slog.String("name", "voice_recorder"))
+}
+
+// WithRecorderLogger sets the Recorder(s) used Logger.
+func WithRecorderLogger(logger *slog.Logger) RecorderConfigOpt {
+	return func(config *recorderConfig) {
+		config.Logger = logger
+	}
+}
+
+// WithRecorderUserFilter sets the UserFilterFunc which decides which users are recorded. By default, all users are recorded.
+func WithRecorderUserFilter(userFilter UserFilterFunc) RecorderConfigOpt {
+	return func(config *recorderConfig) {
+		config.UserFilter = userFilter
+	}
+}
+
+// WithRecorderMaxFileSize starts a new file for a user once the current file is larger than the given size in bytes. 0 disables rotation by size.
+func WithRecorderMaxFileSize(size int64) RecorderConfigOpt {
+	return func(config *recorderConfig) {
+		config.MaxFileSize = size
+	}
+}
+
+// WithRecorderMaxFileDuration starts a new file for a user once the current file is longer than the given duration. 0 disables rotation by time.
+func WithRecorderMaxFileDuration(duration time.Duration) RecorderConfigOpt {
+	return func(config *recorderConfig) {
+		config.MaxFileDuration = duration
+	}
+}
@@ -0,0 +1,76 @@
+package voice
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+func TestRecorder(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := NewRecorder(dir,
+		WithRecorderUserFilter(func(userID snowflake.ID) bool { return userID == 1 }),
+		WithRecorderMaxFileDuration(100*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 20ms frames with three missing frames between the second and third
+	for _, timestamp := range []uint32{0, 960, 4800} {
+		for _, userID := range []snowflake.ID{1, 2} {
+			if err = recorder.ReceiveOpusFrame(userID, &Packet{SSRC: 1, Timestamp: timestamp, Opus: []byte{0x08, byte(timestamp / 960)}}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	recorder.CleanupUser(1)
+	recorder.Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, RecorderManifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest RecorderManifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Files) != 2 {
+		t.Fatalf("expected 2 files, got %+v", manifest.Files)
+	}
+	first, second := manifest.Files[0], manifest.Files[1]
+	if first.UserID != 1 || first.Name != "1_000.opus" || first.Duration != 100*time.Millisecond {
+		t.Fatalf("unexpected first file: %+v", first)
+	}
+	if second.Name != "1_001.opus" || second.Duration != 20*time.Millisecond || second.StartOffset != first.StartOffset+100*time.Millisecond {
+		t.Fatalf("unexpected second file: %+v", second)
+	}
+
+	expected := map[string][][]byte{
+		first.Name:  {{0x08, 0}, {0x08, 1}, SilenceAudioFrame, SilenceAudioFrame, SilenceAudioFrame},
+		second.Name: {{0x08, 5}},
+	}
+	for name, frames := range expected {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := NewOggOpusReader(f)
+		for i, frame := range frames {
+			if data, err := r.ProvideOpusFrame(); err != nil || !bytes.Equal(data, frame) {
+				t.Fatalf("%s frame %d: expected %v, got %v: %v", name, i, frame, data, err)
+			}
+		}
+		if _, err = r.ProvideOpusFrame(); !errors.Is(err, io.EOF) {
+			t.Fatalf("%s: expected io.EOF, got %v", name, err)
+		}
+		_ = f.Close()
+	}
+}
@@ -0,0 +1,80 @@
+package voice
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+type (
+	// TrackOpenFunc opens a new OpusFrameProvider which provides the frames of a Track from the beginning.
+	TrackOpenFunc func() (OpusFrameProvider, error)
+
+	// TrackFormat creates an OpusFrameProvider which reads the frames of a Track from the given io.Reader.
+	TrackFormat func(r io.Reader) (OpusFrameProvider, error)
+)
+
+var (
+	// TrackFormatOpus reads opus frames prefixed with their length, see OpusReader.
+	TrackFormatOpus TrackFormat = func(r io.Reader) (OpusFrameProvider, error) {
+		return NewOpusReader(r), nil
+	}
+
+	// TrackFormatOggOpus reads Ogg Opus files, see OggOpusReader.
+	TrackFormatOggOpus TrackFormat = func(r io.Reader) (OpusFrameProvider, error) {
+		return NewOggOpusReader(r), nil
+	}
+
+	// TrackFormatDCA reads DCA files, see DCAReader.
+	TrackFormatDCA TrackFormat = func(r io.Reader) (OpusFrameProvider, error) {
+		return NewDCAReader(r)
+	}
+)
+
+// Track is a playable item of a Player. It is opened again every time it is played or seeked backwards.
+type Track struct {
+	// Name is the name of the Track, for example a title or file name.
+	Name string
+	// Open opens the OpusFrameProvider of the Track.
+	Open TrackOpenFunc
+}
+
+// NewFileTrack returns a Track which reads the file at the given path in the given TrackFormat.
+func NewFileTrack(path string, format TrackFormat) Track {
+	return Track{
+		Name: filepath.Base(path),
+		Open: func() (OpusFrameProvider, error) {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			provider, err := format(f)
+			if err != nil {
+				_ = f.Close()
+				return nil, err
+			}
+			return &fileFrameProvider{OpusFrameProvider: provider, f: f}, nil
+		},
+	}
+}
+
+// NewBytesTrack returns a Track which reads the given data in the given TrackFormat, for example an embedded file.
+func NewBytesTrack(name string, data []byte, format TrackFormat) Track {
+	return Track{
+		Name: name,
+		Open: func() (OpusFrameProvider, error) {
+			return format(bytes.NewReader(data))
+		},
+	}
+}
+
+type fileFrameProvider struct {
+	OpusFrameProvider
+	f *os.File
+}
+
+func (p *fileFrameProvider) Close() {
+	p.OpusFrameProvider.Close()
+	_ = p.f.Close()
+}
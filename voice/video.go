@@ -0,0 +1,128 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// VideoFrame is a single encoded video frame.
+type VideoFrame struct {
+	// Data is the encoded frame. It is only valid until the next call to VideoFrameProvider.ProvideVideoFrame.
+	Data []byte
+	// Timestamp is the presentation time of the frame relative to the start of the stream.
+	Timestamp time.Duration
+}
+
+// VideoFrameProvider provides video frames, for example from a file.
+type VideoFrameProvider interface {
+	// ProvideVideoFrame returns the next VideoFrame or io.EOF if there are no more frames.
+	ProvideVideoFrame() (VideoFrame, error)
+
+	// Close closes the VideoFrameProvider.
+	Close()
+}
+
+// WriteVideo writes the frames of the VideoFrameProvider to w at their timestamps, usually to a LiveKitConn.VideoWriter or LiveKitConn.VideoCodecWriter.
+// See WriteAudioVideo for details.
+func WriteVideo(ctx context.Context, w io.Writer, video VideoFrameProvider) error {
+	return WriteAudioVideo(ctx, nil, nil, w, video)
+}
+
+// WriteAudioVideo writes the frames of the OpusFrameProvider to audioW and the frames of the VideoFrameProvider to videoW on a common clock,
+// so the audio stays in sync with the video, for example the Ogg Opus and H.264 output of ffmpeg for a screen share.
+// The timestamp of an opus frame is the duration of all frames before it. Either provider may be nil.
+//
+// It returns nil once both providers returned io.EOF or the error of ctx once it is done.
+// Frames written while the LiveKitConn is not connected are dropped.
+func WriteAudioVideo(ctx context.Context, audioW io.Writer, audio OpusFrameProvider, videoW io.Writer, video VideoFrameProvider) error {
+	var streams []*mediaStream
+	if audio != nil {
+		var position time.Duration
+		streams = append(streams, &mediaStream{
+			w: audioW,
+			provide: func() ([]byte, time.Duration, error) {
+				frame, err := audio.ProvideOpusFrame()
+				if err != nil {
+					return nil, 0, err
+				}
+				timestamp := position
+				if samples := opusPacketSamples(frame); samples > 0 {
+					position += time.Duration(samples) * time.Second / oggOpusSampleRate
+				} else {
+					position += OpusFrameDuration
+				}
+				return frame, timestamp, nil
+			},
+		})
+	}
+	if video != nil {
+		streams = append(streams, &mediaStream{
+			w: videoW,
+			provide: func() ([]byte, time.Duration, error) {
+				frame, err := video.ProvideVideoFrame()
+				return frame.Data, frame.Timestamp, err
+			},
+		})
+	}
+	for _, stream := range streams {
+		if err := stream.next(); err != nil {
+			return err
+		}
+	}
+
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		var stream *mediaStream
+		for _, s := range streams {
+			if !s.done && (stream == nil || s.timestamp < stream.timestamp) {
+				stream = s
+			}
+		}
+		if stream == nil {
+			return nil
+		}
+
+		timer.Reset(time.Until(start.Add(stream.timestamp)))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		if _, err := stream.w.Write(stream.data); err != nil && !errors.Is(err, ErrLiveKitNotConnected) {
+			return fmt.Errorf("error while writing frame: %w", err)
+		}
+		if err := stream.next(); err != nil {
+			return err
+		}
+	}
+}
+
+// mediaStream is the next frame of a stream written by WriteAudioVideo.
+type mediaStream struct {
+	w       io.Writer
+	provide func() ([]byte, time.Duration, error)
+
+	data      []byte
+	timestamp time.Duration
+	done      bool
+}
+
+func (s *mediaStream) next() error {
+	data, timestamp, err := s.provide()
+	if errors.Is(err, io.EOF) {
+		s.done = true
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error while reading frame: %w", err)
+	}
+	s.data = data
+	s.timestamp = timestamp
+	return nil
+}
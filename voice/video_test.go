@@ -0,0 +1,163 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestIVFReader(t *testing.T) {
+	buf := &bytes.Buffer{}
+	header := make([]byte, ivfHeaderSize)
+	copy(header, "DKIF")
+	binary.LittleEndian.PutUint16(header[6:], ivfHeaderSize)
+	copy(header[8:], "VP80")
+	binary.LittleEndian.PutUint16(header[12:], 640)
+	binary.LittleEndian.PutUint16(header[14:], 480)
+	binary.LittleEndian.PutUint32(header[16:], 1000)
+	binary.LittleEndian.PutUint32(header[20:], 1)
+	buf.Write(header)
+	for i, pts := range []uint64{0, 40, 80} {
+		frameHeader := make([]byte, ivfFrameHeaderSize)
+		binary.LittleEndian.PutUint32(frameHeader, 2)
+		binary.LittleEndian.PutUint64(frameHeader[4:], pts)
+		buf.Write(frameHeader)
+		buf.Write([]byte{0x10, byte(i)})
+	}
+
+	r, err := NewIVFReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if codec, err := r.Header().Codec(); err != nil || codec != VideoCodecVP8 {
+		t.Fatalf("expected VP8, got %d: %v", codec, err)
+	}
+	if fps := r.Header().FPS(); fps != 1000 {
+		t.Fatalf("expected 1000 fps, got %d", fps)
+	}
+	for i := range 3 {
+		frame, err := r.ProvideVideoFrame()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(frame.Data, []byte{0x10, byte(i)}) || frame.Timestamp != time.Duration(i)*40*time.Millisecond {
+			t.Fatalf("frame %d: unexpected frame %+v", i, frame)
+		}
+	}
+	if _, err = r.ProvideVideoFrame(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestH264Reader(t *testing.T) {
+	var (
+		sps   = []byte{0x67, 0x42, 0x00, 0x1f}
+		pps   = []byte{0x68, 0xce, 0x3c, 0x80}
+		idr1  = []byte{0x65, 0x88, 0x84}
+		idr2  = []byte{0x65, 0x40, 0x11}
+		slice = []byte{0x41, 0x9a, 0x00, 0x00, 0x03, 0x01}
+	)
+	var stream []byte
+	for i, nal := range [][]byte{sps, pps, idr1, idr2, slice} {
+		if i%2 == 0 {
+			stream = append(stream, 0x00)
+		}
+		stream = append(stream, 0x00, 0x00, 0x01)
+		stream = append(stream, nal...)
+	}
+	stream = append(stream, 0x00)
+
+	r, err := NewH264Reader(bytes.NewReader(stream), 25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	annexB := func(nals ...[]byte) []byte {
+		var data []byte
+		for _, nal := range nals {
+			data = append(append(data, h264StartCode...), nal...)
+		}
+		return data
+	}
+	// the second IDR slice continues the picture of the first one
+	expected := [][]byte{annexB(sps, pps, idr1, idr2), annexB(slice)}
+	for i, data := range expected {
+		frame, err := r.ProvideVideoFrame()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(frame.Data, data) || frame.Timestamp != time.Duration(i)*40*time.Millisecond {
+			t.Fatalf("frame %d: expected %x, got %x at %s", i, data, frame.Data, frame.Timestamp)
+		}
+	}
+	if _, err = r.ProvideVideoFrame(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+type fakeVideoFrameProvider struct {
+	frames []VideoFrame
+}
+
+func (p *fakeVideoFrameProvider) ProvideVideoFrame() (VideoFrame, error) {
+	if len(p.frames) == 0 {
+		return VideoFrame{}, io.EOF
+	}
+	frame := p.frames[0]
+	p.frames = p.frames[1:]
+	return frame, nil
+}
+
+func (*fakeVideoFrameProvider) Close() {}
+
+type orderWriter struct {
+	name   string
+	writes *[]string
+}
+
+func (w orderWriter) Write(p []byte) (int, error) {
+	*w.writes = append(*w.writes, w.name+string(p))
+	return len(p), nil
+}
+
+func TestWriteAudioVideo(t *testing.T) {
+	// opus frames with a 10ms duration
+	audio := &bytes.Buffer{}
+	w := NewDCAWriter(audio, nil, nil)
+	for _, id := range "abcd" {
+		if err := w.WriteFrame([]byte{0x00, byte(id)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	audioReader, err := NewDCAReader(audio)
+	if err != nil {
+		t.Fatal(err)
+	}
+	video := &fakeVideoFrameProvider{frames: []VideoFrame{
+		{Data: []byte("1"), Timestamp: 0},
+		{Data: []byte("2"), Timestamp: 15 * time.Millisecond},
+		{Data: []byte("3"), Timestamp: 50 * time.Millisecond},
+	}}
+
+	var writes []string
+	start := time.Now()
+	if err = WriteAudioVideo(context.Background(), orderWriter{name: "audio ", writes: &writes}, audioReader, orderWriter{name: "video ", writes: &writes}, video); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("expected frames to be paced, took %s", elapsed)
+	}
+
+	expected := []string{"audio \x00a", "video 1", "audio \x00b", "video 2", "audio \x00c", "audio \x00d", "video 3"}
+	if len(writes) != len(expected) {
+		t.Fatalf("expected %q, got %q", expected, writes)
+	}
+	for i := range expected {
+		if writes[i] != expected[i] {
+			t.Fatalf("expected %q, got %q", expected, writes)
+		}
+	}
+}